@@ -0,0 +1,267 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Interval during which a satellite can access a ground point
+type AccessWindow struct {
+	Start, Stop time.Time
+
+	// Time of maximum elevation
+	Culmination time.Time
+
+	// Look angles from the ground point at start, culmination and stop
+	StartAngles, CulminationAngles, StopAngles LookAngles
+
+	// Doppler corrected frequencies of the satellite transmitters at start, culmination
+	// and stop, filled in when AccessOptions.Transmitters is set
+	Doppler []TransmitterDoppler
+}
+
+// Returns the length of the window
+func (w AccessWindow) Duration() time.Duration {
+	return w.Stop.Sub(w.Start)
+}
+
+// Options for NextAccesses
+type AccessOptions struct {
+	// Minimum elevation of the satellite above the target horizon in radians
+	MinElevation Radians
+
+	// Lower the minimum elevation by the horizon dip of an elevated target, so windows of
+	// aircraft or mountaintop stations open below 0 deg nominal elevation
+	HorizonDip bool
+
+	// Optional terrain or obstruction profile the satellite must also clear. The horizon
+	// dip is not applied to it.
+	Mask *HorizonMask
+
+	// Maximum angle in radians between nadir and the target seen from the satellite.
+	// Zero leaves the off-nadir angle unconstrained.
+	MaxOffNadir Radians
+
+	// Limit windows to times an optical observer can see the satellite: the satellite is
+	// outside the umbra of the Earth while the sun is below TwilightElevation at the target.
+	// Civil twilight at -6 deg is used when TwilightElevation is zero.
+	Optical           bool
+	TwilightElevation Radians
+
+	// Optional imaging sensor; windows are limited to times the sensor can image the target
+	Sensor *Sensor
+
+	// Optional transmitter table used to fill in AccessWindow.Doppler
+	Transmitters TransmitterTable
+
+	// Sampling step of the coarse search, 30 seconds when zero
+	Step time.Duration
+
+	// How far past start to search, 7 days when zero
+	Horizon time.Duration
+}
+
+// Returns up to n access windows of the satellite or ephemeris over the target starting at start.
+// Windows in progress at start or at the end of the horizon are clipped. Window edges are refined to
+// a tenth of a second and the culmination to about a second.
+func NextAccesses(sat Propagator, target LatLongAlt, start time.Time, n int, opts AccessOptions) ([]AccessWindow, error) {
+	if n <= 0 {
+		return nil, errors.New("number of access windows must be positive")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+	horizon := opts.Horizon
+	if horizon <= 0 {
+		horizon = 7 * 24 * time.Hour
+	}
+
+	a := newAccessSearch(sat, target, opts)
+	windows, err := a.windows(start, start.Add(horizon), step, n)
+	return windows, err
+}
+
+// Evaluates access geometry of one satellite over one ground point
+type accessSearch struct {
+	sat    Propagator
+	target LatLongAlt
+	obs    Vector3
+	minEl  float64
+	opts   AccessOptions
+	grav   GravConst
+	err    error
+
+	// Earth fixed position of the last evaluated state
+	last Vector3
+
+	// Optional callback invoked by scan after each sample with the windows closed since the
+	// previous call, and once more at the stop time with the window still open there
+	onSample func(t time.Time, closed []AccessWindow)
+}
+
+func newAccessSearch(sat Propagator, target LatLongAlt, opts AccessOptions) *accessSearch {
+	grav := gravityOf(sat)
+	return &accessSearch{
+		sat:    sat,
+		target: target,
+		obs:    LLAToECEF(target, grav),
+		minEl:  observerMinElevation(opts.MinElevation, target, opts.HorizonDip, grav),
+		opts:   opts,
+		grav:   grav,
+	}
+}
+
+// Returns the look angles at t and the access margin, which is positive while the
+// target is accessible. Propagation errors are kept in a.err.
+func (a *accessSearch) at(t time.Time) (LookAngles, float64) {
+	s, err := a.sat.StateAt(t)
+	if err != nil {
+		if a.err == nil {
+			a.err = err
+		}
+		return LookAngles{}, -1
+	}
+	pos, vel := s.Position, s.Velocity
+	gmst := a.grav.rotationAngle(t)
+	satECEF := ECIToECEF(pos, gmst)
+	a.last = satECEF
+	la := ecefLookAngles(satECEF, a.obs, a.target)
+	margin := la.El - a.minEl
+	if a.opts.Mask != nil {
+		margin = math.Min(margin, la.El-a.opts.Mask.Elevation(la.Az))
+	}
+	if a.opts.Optical {
+		margin = math.Min(margin, a.opticalMargin(pos, gmst, t))
+	}
+	if a.opts.MaxOffNadir > 0 {
+		los := Vector3{a.obs.X - satECEF.X, a.obs.Y - satECEF.Y, a.obs.Z - satECEF.Z}
+		nadir := Vector3{-satECEF.X, -satECEF.Y, -satECEF.Z}
+		offNadir := math.Acos(math.Max(-1, math.Min(1, dot(los, nadir)/(los.Magnitude()*nadir.Magnitude()))))
+		margin = math.Min(margin, float64(a.opts.MaxOffNadir)-offNadir)
+	}
+	if a.opts.Sensor != nil {
+		var sun Vector3
+		if a.opts.Sensor.SunConstraint {
+			sun = ECIToECEF(SunPositionECI(NewJDayFromTime(t)), gmst)
+		}
+		margin = math.Min(margin, a.opts.Sensor.margin(satECEF, ECIToECEF(vel, gmst), a.obs, a.target, sun))
+	}
+	return la, margin
+}
+
+// Returns a margin that is positive while the satellite at the inertial position is
+// outside the umbra over a dark target
+func (a *accessSearch) opticalMargin(pos Vector3, gmst float64, t time.Time) float64 {
+	if !a.grav.earth() {
+		// The sun position is geocentric, targets around other bodies count as daylight
+		return -1
+	}
+	twilight := float64(a.opts.TwilightElevation)
+	if twilight == 0 {
+		twilight = -6 * DEG2RAD
+	}
+	sun := SunPositionECI(NewJDayFromTime(t))
+	dark := twilight - elevationOf(ECIToECEF(sun, gmst), a.obs, a.target)
+	_, umbra := shadowAngles(pos, sun, a.grav.radiusearthkm)
+	return math.Min(dark, umbra)
+}
+
+func (a *accessSearch) margin(t time.Time) float64 {
+	_, m := a.at(t)
+	return m
+}
+
+// Returns the time between lo and hi where the access margin changes sign
+func (a *accessSearch) edge(lo, hi time.Time) time.Time {
+	sec := bisect(func(s float64) float64 {
+		return a.margin(lo.Add(time.Duration(s * float64(time.Second))))
+	}, 0, hi.Sub(lo).Seconds(), 0.1)
+	return lo.Add(time.Duration(sec * float64(time.Second)))
+}
+
+// Fills in the culmination and look angles of a window with known start and stop
+func (a *accessSearch) complete(w AccessWindow) AccessWindow {
+	sec, _ := goldenSection(func(s float64) float64 {
+		la, _ := a.at(w.Start.Add(time.Duration(s * float64(time.Second))))
+		return -la.El
+	}, 0, w.Stop.Sub(w.Start).Seconds(), 1)
+	w.Culmination = w.Start.Add(time.Duration(sec * float64(time.Second)))
+	w.StartAngles, _ = a.at(w.Start)
+	w.CulminationAngles, _ = a.at(w.Culmination)
+	w.StopAngles, _ = a.at(w.Stop)
+	if satnum := a.sat.CatalogNumber(); len(a.opts.Transmitters[satnum]) > 0 {
+		for _, t := range []time.Time{w.Start, w.Culmination, w.Stop} {
+			pos, vel, err := stateECEF(a.sat, t)
+			if err == nil {
+				w.Doppler = append(w.Doppler, a.opts.Transmitters.doppler(satnum, t, topocentricRangeRate(pos, vel, a.obs))...)
+			}
+		}
+	}
+	return w
+}
+
+// Returns up to n windows between start and stop, n < 0 returns all of them
+func (a *accessSearch) windows(start, stop time.Time, step time.Duration, n int) (windows []AccessWindow, err error) {
+	return a.scan(start, stop, n, func(time.Time, bool, Vector3) time.Duration { return step })
+}
+
+// Returns up to n windows between start and stop sampled at the steps returned by next,
+// which is given the last sample time, whether the target was accessible then and the
+// Earth fixed satellite position
+func (a *accessSearch) scan(start, stop time.Time, n int, next func(t time.Time, access bool, satECEF Vector3) time.Duration) (windows []AccessWindow, err error) {
+	var open *AccessWindow
+	reported := 0
+	prevT := start
+	prev := a.margin(start) > 0
+	pos := a.last
+	if a.err != nil {
+		return nil, a.err
+	}
+	if prev {
+		open = &AccessWindow{Start: start}
+	}
+
+	for t := start.Add(next(start, prev, pos)); n < 0 || len(windows) < n; t = t.Add(next(t, prev, pos)) {
+		if t.After(stop) {
+			t = stop
+		}
+		now := a.margin(t) > 0
+		pos = a.last
+		switch {
+		case now && !prev:
+			open = &AccessWindow{Start: a.edge(prevT, t)}
+		case !now && prev:
+			open.Stop = a.edge(prevT, t)
+			windows = append(windows, a.complete(*open))
+			open = nil
+		}
+		if a.err != nil {
+			return windows, a.err
+		}
+		reported = a.sampled(t, windows, reported)
+		if a.err != nil {
+			return windows, a.err
+		}
+		prev, prevT = now, t
+		if !t.Before(stop) {
+			break
+		}
+	}
+	if open != nil && (n < 0 || len(windows) < n) {
+		open.Stop = stop
+		windows = append(windows, a.complete(*open))
+	}
+	a.sampled(stop, windows, reported)
+	return windows, a.err
+}
+
+// Passes the windows after the first reported to the sample callback and returns the new
+// number of reported windows
+func (a *accessSearch) sampled(t time.Time, windows []AccessWindow, reported int) int {
+	if a.onSample != nil {
+		a.onSample(t, windows[reported:])
+	}
+	return len(windows)
+}
@@ -0,0 +1,68 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NextAccesses", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should return the requested number of windows above the minimum elevation", func() {
+		sat := jobTestSatellites()[0]
+		windows, err := NextAccesses(&sat, copenhagen, start, 3, AccessOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+		Expect(windows).To(HaveLen(3))
+
+		for i, w := range windows {
+			Expect(w.Start).To(BeTemporally(">=", start))
+			Expect(w.Stop).To(BeTemporally(">", w.Start))
+			Expect(w.Culmination).To(BeTemporally(">=", w.Start))
+			Expect(w.Culmination).To(BeTemporally("<=", w.Stop))
+			Expect(w.StartAngles.El * RAD2DEG).To(BeNumerically("~", 10, 0.05))
+			Expect(w.StopAngles.El * RAD2DEG).To(BeNumerically("~", 10, 0.05))
+			Expect(w.CulminationAngles.El).To(BeNumerically(">=", w.StartAngles.El))
+			if i > 0 {
+				Expect(w.Start).To(BeTemporally(">", windows[i-1].Stop))
+			}
+		}
+	})
+
+	It("should agree with the inertial look angle computation", func() {
+		sat := jobTestSatellites()[0]
+		windows, err := NextAccesses(&sat, copenhagen, start, 1, AccessOptions{})
+		Expect(err).To(BeNil())
+
+		t := windows[0].Culmination.Truncate(time.Second)
+		pos, _, err := sat.propagateAt(t)
+		Expect(err).To(BeNil())
+		want := ECIToLookAngles(pos, copenhagen, NewJDayFromTime(t), sat.Gravity)
+
+		a := newAccessSearch(&sat, copenhagen, AccessOptions{})
+		got, _ := a.at(t)
+		Expect(got.El).To(BeNumerically("~", want.El, 1e-4))
+		Expect(got.Az).To(BeNumerically("~", want.Az, 1e-4))
+		Expect(got.Rg).To(BeNumerically("~", want.Rg, 0.1))
+	})
+
+	It("should narrow windows with an off-nadir constraint", func() {
+		sat := jobTestSatellites()[0]
+		wide, err := NextAccesses(&sat, copenhagen, start, 5, AccessOptions{})
+		Expect(err).To(BeNil())
+		narrow, err := NextAccesses(&sat, copenhagen, start, 1, AccessOptions{MaxOffNadir: Degrees(65).Radians()})
+		Expect(err).To(BeNil())
+		Expect(narrow).To(HaveLen(1))
+
+		contained := false
+		for _, w := range wide {
+			if !narrow[0].Start.Before(w.Start) && !narrow[0].Stop.After(w.Stop) {
+				contained = true
+				Expect(narrow[0].Duration()).To(BeNumerically("<", w.Duration()))
+			}
+		}
+		Expect(contained).To(BeTrue())
+	})
+})
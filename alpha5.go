@@ -0,0 +1,40 @@
+package satellite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Leading letters of Alpha-5 catalog numbers standing for 10 to 33. I and O are skipped to
+// avoid confusion with 1 and 0.
+const alpha5Letters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// Parses the five character catalog number of a TLE. Numbers from 100000 to 339999 are
+// written in the Alpha-5 scheme with a leading letter for the multiple of 10000, so E8493
+// is 148493.
+func ParseAlpha5(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty catalog number")
+	}
+	if i := strings.IndexByte(alpha5Letters, s[0]); i >= 0 {
+		rest, err := strconv.ParseUint(s[1:], 10, 64)
+		if err != nil || len(s) != 5 {
+			return 0, fmt.Errorf("bad Alpha-5 catalog number %q", s)
+		}
+		return int64(i+10)*10000 + int64(rest), nil
+	}
+	return parseInt(s)
+}
+
+// Returns the five character TLE form of a catalog number, in the Alpha-5 scheme from 100000 on
+func FormatAlpha5(satnum int64) (string, error) {
+	switch {
+	case satnum < 0 || satnum > 339999:
+		return "", fmt.Errorf("catalog number %d does not fit a TLE", satnum)
+	case satnum > 99999:
+		return fmt.Sprintf("%c%04d", alpha5Letters[satnum/10000-10], satnum%10000), nil
+	}
+	return fmt.Sprintf("%05d", satnum), nil
+}
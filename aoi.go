@@ -0,0 +1,184 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Area of interest given as a ring of points in radians. The ring is closed
+// implicitly; it may cross the antimeridian but must not enclose a pole.
+type Polygon []LatLong
+
+// Reports whether the point lies inside the polygon
+func (p Polygon) Contains(pt LatLong) bool {
+	inside := false
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		// Longitudes relative to the point so rings crossing the antimeridian work
+		ax, bx := wrapPi(a.Longitude-pt.Longitude), wrapPi(b.Longitude-pt.Longitude)
+		if (a.Latitude > pt.Latitude) != (b.Latitude > pt.Latitude) {
+			x := ax + (pt.Latitude-a.Latitude)*(bx-ax)/(b.Latitude-a.Latitude)
+			if x > 0 {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Returns the smallest central angle in radians between the point and the polygon boundary
+func (p Polygon) boundaryAngle(pt LatLong) float64 {
+	best := math.Inf(1)
+	for i := range p {
+		best = math.Min(best, segmentAngle(pt, p[i], p[(i+1)%len(p)]))
+	}
+	return best
+}
+
+// Interval during which the sub-satellite point or sensor footprint overlaps an area of interest
+type Overflight struct {
+	Entry, Exit time.Time
+}
+
+// Options for Overflights
+type OverflightOptions struct {
+	// Sampling step of the coarse search, 30 seconds when zero
+	Step time.Duration
+
+	// Half angle in radians of a nadir pointing sensor cone. When zero only the
+	// sub-satellite point is tested against the area.
+	SensorHalfAngle Radians
+}
+
+// Returns the intervals between start and stop during which the satellite flies over
+// the area of interest. Entry and exit are refined to a tenth of a second; an
+// interval in progress at start or stop is clipped to the window.
+func Overflights(sat *Satellite, aoi Polygon, start, stop time.Time, opts OverflightOptions) (overflights []Overflight, err error) {
+	if len(aoi) < 3 {
+		return nil, errors.New("area of interest needs at least three points")
+	}
+	if !stop.After(start) {
+		return nil, errors.New("overflight stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+
+	over := func(t time.Time) (bool, error) {
+		pos, _, err := sat.propagateAt(t)
+		if err != nil {
+			return false, err
+		}
+		alt, _, sub := ECIToLLA(pos, gmstAt(t))
+		sub.Longitude = wrapPi(sub.Longitude)
+		if aoi.Contains(sub) {
+			return true, nil
+		}
+		if opts.SensorHalfAngle <= 0 {
+			return false, nil
+		}
+		return aoi.boundaryAngle(sub) <= footprintAngle(alt, float64(opts.SensorHalfAngle), sat.Gravity.radiusearthkm), nil
+	}
+
+	var refineErr error
+	refine := func(lo, hi time.Time) time.Time {
+		span := hi.Sub(lo).Seconds()
+		sec := bisect(func(s float64) float64 {
+			in, err := over(lo.Add(time.Duration(s * float64(time.Second))))
+			if err != nil {
+				refineErr = err
+			}
+			if in {
+				return 1
+			}
+			return -1
+		}, 0, span, 0.1)
+		return lo.Add(time.Duration(sec * float64(time.Second)))
+	}
+
+	times := sampleTimes(start, stop, step)
+	prev, err := over(times[0])
+	if err != nil {
+		return nil, err
+	}
+	var entry time.Time
+	if prev {
+		entry = start
+	}
+	for k := 1; k < len(times); k++ {
+		now, err := over(times[k])
+		if err != nil {
+			return overflights, err
+		}
+		switch {
+		case now && !prev:
+			entry = refine(times[k-1], times[k])
+		case !now && prev:
+			overflights = append(overflights, Overflight{Entry: entry, Exit: refine(times[k-1], times[k])})
+		}
+		if refineErr != nil {
+			return overflights, refineErr
+		}
+		prev = now
+	}
+	if prev {
+		overflights = append(overflights, Overflight{Entry: entry, Exit: stop})
+	}
+	return overflights, nil
+}
+
+// Returns the earth central angle covered by a nadir cone of the given half angle
+// seen from altitude, limited by the horizon
+func footprintAngle(altitude, halfAngle, radius float64) float64 {
+	horizon := math.Acos(radius / (radius + altitude))
+	s := (radius + altitude) / radius * math.Sin(halfAngle)
+	if s >= 1 {
+		return horizon
+	}
+	return math.Min(math.Asin(s)-halfAngle, horizon)
+}
+
+// Returns the central angle between p and the great circle segment from a to b
+func segmentAngle(p, a, b LatLong) float64 {
+	toVec := func(ll LatLong) Vector3 {
+		return Vector3{
+			math.Cos(ll.Latitude) * math.Cos(ll.Longitude),
+			math.Cos(ll.Latitude) * math.Sin(ll.Longitude),
+			math.Sin(ll.Latitude),
+		}
+	}
+	pv, av, bv := toVec(p), toVec(a), toVec(b)
+	n := cross(av, bv)
+	if n.Magnitude() < 1e-12 {
+		return centralAngle(p, a)
+	}
+
+	// Closest point on the full great circle, used when it lies between a and b
+	c := cross(n, cross(pv, n))
+	if cm := c.Magnitude(); cm > 1e-12 {
+		c = Vector3{c.X / cm, c.Y / cm, c.Z / cm}
+		if dot(cross(av, c), n) >= 0 && dot(cross(c, bv), n) >= 0 {
+			return math.Acos(math.Max(-1, math.Min(1, dot(pv, c))))
+		}
+	}
+	return math.Min(centralAngle(p, a), centralAngle(p, b))
+}
+
+func cross(a, b Vector3) Vector3 {
+	return Vector3{a.Y*b.Z - a.Z*b.Y, a.Z*b.X - a.X*b.Z, a.X*b.Y - a.Y*b.X}
+}
+
+func dot(a, b Vector3) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+// Wraps an angle into the range [-pi, pi)
+func wrapPi(a float64) float64 {
+	a = math.Mod(a+math.Pi, TWOPI)
+	if a < 0 {
+		a += TWOPI
+	}
+	return a - math.Pi
+}
@@ -0,0 +1,65 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Overflights", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(24 * time.Hour)
+	europe := Polygon{
+		NewLatLongFromDeg(40, -10),
+		NewLatLongFromDeg(40, 30),
+		NewLatLongFromDeg(50, 30),
+		NewLatLongFromDeg(50, -10),
+	}
+
+	It("should test points against polygons crossing the antimeridian", func() {
+		pacific := Polygon{
+			NewLatLongFromDeg(-10, 170),
+			NewLatLongFromDeg(-10, -170),
+			NewLatLongFromDeg(10, -170),
+			NewLatLongFromDeg(10, 170),
+		}
+		Expect(pacific.Contains(NewLatLongFromDeg(0, 179))).To(BeTrue())
+		Expect(pacific.Contains(NewLatLongFromDeg(0, -175))).To(BeTrue())
+		Expect(pacific.Contains(NewLatLongFromDeg(0, 160))).To(BeFalse())
+		Expect(pacific.Contains(NewLatLongFromDeg(20, 180))).To(BeFalse())
+	})
+
+	It("should find sub-satellite point overflights", func() {
+		sat := jobTestSatellites()[0]
+		overflights, err := Overflights(&sat, europe, start, stop, OverflightOptions{})
+		Expect(err).To(BeNil())
+		Expect(overflights).To(Not(BeEmpty()))
+
+		for _, o := range overflights {
+			Expect(o.Exit).To(BeTemporally(">", o.Entry))
+			mid := o.Entry.Add(o.Exit.Sub(o.Entry) / 2)
+			pos, _, err := sat.propagateAt(mid)
+			Expect(err).To(BeNil())
+			_, _, sub := ECIToLLA(pos, gmstAt(mid))
+			sub.Longitude = wrapPi(sub.Longitude)
+			Expect(europe.Contains(sub)).To(BeTrue())
+		}
+	})
+
+	It("should widen overflights by the sensor footprint", func() {
+		sat := jobTestSatellites()[0]
+		nadir, err := Overflights(&sat, europe, start, stop, OverflightOptions{})
+		Expect(err).To(BeNil())
+		footprint, err := Overflights(&sat, europe, start, stop, OverflightOptions{SensorHalfAngle: Degrees(30).Radians()})
+		Expect(err).To(BeNil())
+
+		total := func(os []Overflight) (d time.Duration) {
+			for _, o := range os {
+				d += o.Exit.Sub(o.Entry)
+			}
+			return
+		}
+		Expect(total(footprint)).To(BeNumerically(">", total(nadir)))
+	})
+})
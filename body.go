@@ -0,0 +1,150 @@
+package satellite
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Physical constants of a central body other than the Earth. Its inertial frame is the
+// body centred frame aligned with its equator, and its fixed frame turns about the pole at
+// RotationRate from the prime meridian angle at J2000.
+type CentralBody struct {
+	Name string
+
+	// Gravitational parameter in km^3/s^2, equatorial radius in km, second zonal harmonic
+	// and flattening of the reference ellipsoid
+	Mu, Radius, J2, Flattening float64
+
+	// Rotation rate in rad/s and prime meridian angle in radians at J2000
+	RotationRate, PrimeMeridian float64
+}
+
+// Central bodies with constants from the IAU WGCCRE 2015 report and the GRAIL and MRO
+// gravity models
+var (
+	Moon = CentralBody{
+		Name: "Moon", Mu: 4902.800066, Radius: 1737.4, J2: 2.0330530e-4, Flattening: 0.0012,
+		RotationRate: 2.6616995e-6, PrimeMeridian: 38.3213 * DEG2RAD,
+	}
+	Mars = CentralBody{
+		Name: "Mars", Mu: 42828.37, Radius: 3396.19, J2: 1.9566e-3, Flattening: 0.00589,
+		RotationRate: 7.0882181e-5, PrimeMeridian: 176.630 * DEG2RAD,
+	}
+)
+
+// Returns the gravity model of the body for the numerical propagators and the coordinate
+// conversions. SGP4 only accepts the Earth models of NewSatFromTLE.
+func (b CentralBody) Gravity() GravConst {
+	grav := GravConst{
+		mu:            b.Mu,
+		radiusearthkm: b.Radius,
+		j2:            b.J2,
+		f:             b.Flattening,
+		body:          b.Name,
+		rotationRate:  b.RotationRate,
+		meridian:      b.PrimeMeridian,
+	}
+	grav.xke = 60.0 / math.Sqrt(grav.radiusearthkm*grav.radiusearthkm*grav.radiusearthkm/grav.mu)
+	grav.tumin = 1.0 / grav.xke
+	return grav
+}
+
+// J2000 epoch, 2000-01-01 12:00 TT taken as UTC
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// Reports whether the gravity model belongs to the Earth
+func (g GravConst) earth() bool {
+	return g.body == ""
+}
+
+// Returns the angle in radians between the inertial and the body fixed frame at t, the
+// greenwich mean sidereal time for the Earth
+func (g GravConst) rotationAngle(t time.Time) float64 {
+	if g.earth() {
+		return gmstAt(t)
+	}
+	return math.Mod(g.meridian+g.rotationRate*t.Sub(j2000).Seconds(), TWOPI)
+}
+
+// Returns the rotation rate of the body fixed frame in rad/s
+func (g GravConst) rotation() float64 {
+	if g.earth() {
+		return earthAngularVelocity
+	}
+	return g.rotationRate
+}
+
+// Converts an inertial position and velocity into the fixed frame of the central body at t
+func (g GravConst) inertialToFixed(pos, vel Vector3, t time.Time) (fixedPos, fixedVel Vector3) {
+	theta := g.rotationAngle(t)
+	fixedPos = ECIToECEF(pos, theta)
+	rotVel := ECIToECEF(vel, theta)
+	w := g.rotation()
+	fixedVel = Vector3{rotVel.X + w*fixedPos.Y, rotVel.Y - w*fixedPos.X, rotVel.Z}
+	return
+}
+
+// Returns the point below the satellite or ephemeris at t on the ellipsoid of its central
+// body, in radians and km
+func SubPoint(p Propagator, t time.Time) (LatLongAlt, error) {
+	pos, _, err := stateECEF(p, t)
+	if err != nil {
+		return LatLongAlt{}, err
+	}
+	return ECEFToLLA(pos, gravityOf(p)), nil
+}
+
+// Propagator integrating an initial state with a prediction model around any central body,
+// such as a lunar or Mars orbiter. States are cached so time ordered queries integrate only
+// the gap since the previous one. It is safe for concurrent use.
+type NumericalPropagator struct {
+	Satnum  int64
+	Gravity GravConst
+
+	// Initial inertial state
+	Epoch State
+
+	// Prediction model, NumericalModel of Gravity when nil
+	Model PredictionModel
+
+	mu   sync.Mutex
+	last State
+}
+
+// Returns a propagator starting from an inertial state around the body of grav
+func NewNumericalPropagator(satnum int64, grav GravConst, epoch State) *NumericalPropagator {
+	return &NumericalPropagator{Satnum: satnum, Gravity: grav, Epoch: epoch}
+}
+
+// Returns the catalog number of the propagated object
+func (np *NumericalPropagator) CatalogNumber() int64 {
+	return np.Satnum
+}
+
+// Returns the inertial state at t
+func (np *NumericalPropagator) StateAt(t time.Time) (State, error) {
+	model := np.Model
+	if model == nil {
+		model = NumericalModel(np.Gravity)
+	}
+	np.mu.Lock()
+	from := np.Epoch
+	if !np.last.Time.IsZero() && t.Sub(np.last.Time).Abs() < t.Sub(from.Time).Abs() {
+		from = np.last
+	}
+	np.mu.Unlock()
+
+	s, err := model(from, t)
+	if err != nil {
+		return State{}, err
+	}
+	np.mu.Lock()
+	np.last = s
+	np.mu.Unlock()
+	return s, nil
+}
+
+func (np *NumericalPropagator) gravity() GravConst {
+	return np.Gravity
+}
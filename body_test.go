@@ -0,0 +1,57 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CentralBody", func() {
+	epoch := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	moon := Moon.Gravity()
+
+	// Circular polar orbit 100 km above the lunar equator crossing the x axis at epoch
+	r := Moon.Radius + 100
+	speed := math.Sqrt(Moon.Mu / r)
+	orbiter := func() *NumericalPropagator {
+		return NewNumericalPropagator(-1, moon, State{Time: epoch, Position: Vector3{r, 0, 0}, Velocity: Vector3{0, 0, speed}})
+	}
+
+	It("should close a two body orbit after one period", func() {
+		period := TWOPI * math.Sqrt(r*r*r/Moon.Mu)
+		s, err := TwoBodyModel(moon)(orbiter().Epoch, epoch.Add(time.Duration(period*float64(time.Second))))
+		Expect(err).To(BeNil())
+		Expect(distance(s.Position, Vector3{r, 0, 0})).To(BeNumerically("<", 0.01))
+	})
+
+	It("should place the sub-satellite point on the lunar ellipsoid", func() {
+		p := orbiter()
+		sub, err := SubPoint(p, epoch)
+		Expect(err).To(BeNil())
+		Expect(sub.AltitudeKm).To(BeNumerically("~", 100, 1e-6))
+		Expect(sub.LatLong.Latitude).To(BeNumerically("~", 0, 1e-12))
+		Expect(wrapPi(sub.LatLong.Longitude + moon.rotationAngle(epoch))).To(BeNumerically("~", 0, 1e-12))
+
+		quarter := epoch.Add(time.Duration(TWOPI / 4 * r / speed * float64(time.Second)))
+		sub, err = SubPoint(p, quarter)
+		Expect(err).To(BeNil())
+		Expect(sub.LatLong.Latitude * RAD2DEG).To(BeNumerically("~", 90, 0.5))
+	})
+
+	It("should predict passes over a lunar station", func() {
+		station := NewLatLongAlt(0, wrapPi(-moon.rotationAngle(epoch))*RAD2DEG, 0)
+		passes, err := PredictPasses(orbiter(), station, epoch, epoch.Add(6*time.Hour), PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(passes).NotTo(BeEmpty())
+		Expect(passes[0].MaxElevation).To(BeNumerically(">", 80*DEG2RAD))
+		Expect(passes[0].Visibility).To(Equal(Daylight))
+	})
+
+	It("should keep the sidereal time of the Earth models", func() {
+		wgs84, err := getGravConst("wgs84")
+		Expect(err).To(BeNil())
+		Expect(wgs84.rotationAngle(epoch)).To(Equal(gmstAt(epoch)))
+	})
+})
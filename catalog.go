@@ -0,0 +1,241 @@
+package satellite
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Collection of satellites indexed by NORAD catalog number. The zero value is an empty
+// catalog. A Catalog is safe for concurrent use.
+type Catalog struct {
+	// Keep a supplemental element set when Add is given a standard one for the same object
+	PreferSupplemental bool
+
+	mu     sync.RWMutex
+	sats   []Satellite
+	index  map[int64]int
+	sorted bool
+}
+
+// Creates a catalog holding the given satellites
+func NewCatalog(sats ...Satellite) *Catalog {
+	c := &Catalog{index: make(map[int64]int), sorted: true}
+	for _, sat := range sats {
+		c.Add(sat)
+	}
+	return c
+}
+
+// Adds a satellite to the catalog, replacing any satellite with the same catalog number
+// unless PreferSupplemental keeps a supplemental one
+func (c *Catalog) Add(sat Satellite) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i, ok := c.index[sat.Satnum]; ok {
+		if c.PreferSupplemental && c.sats[i].Supplemental() && !sat.Supplemental() {
+			return
+		}
+		c.sats[i] = sat
+		return
+	}
+	if c.index == nil {
+		c.index = make(map[int64]int)
+	}
+	c.index[sat.Satnum] = len(c.sats)
+	c.sats = append(c.sats, sat)
+	c.sorted = false
+}
+
+// Adds element sets to the catalog, keeping for every object the set with the latest epoch
+// and respecting PreferSupplemental. Sets from files and fetchers may be merged in any order.
+// Returns the number of members added or replaced.
+func (c *Catalog) Merge(sats ...Satellite) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.index == nil {
+		c.index = make(map[int64]int)
+	}
+	changed := 0
+	for _, sat := range sats {
+		i, ok := c.index[sat.Satnum]
+		if !ok {
+			c.index[sat.Satnum] = len(c.sats)
+			c.sats = append(c.sats, sat)
+			c.sorted = false
+			changed++
+			continue
+		}
+		old := &c.sats[i]
+		if c.PreferSupplemental && old.Supplemental() != sat.Supplemental() {
+			if sat.Supplemental() {
+				*old = sat
+				changed++
+			}
+			continue
+		}
+		if compareEpochs(&sat, old) > 0 {
+			*old = sat
+			changed++
+		}
+	}
+	return changed
+}
+
+// Replaces all members of the catalog at once with the latest epoch of every object in
+// sats, as picked by Merge. Readers see either the old or the new contents.
+func (c *Catalog) Replace(sats ...Satellite) {
+	next := &Catalog{PreferSupplemental: c.PreferSupplemental, index: make(map[int64]int)}
+	next.Merge(sats...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sats, c.index, c.sorted = next.sats, next.index, next.sorted
+}
+
+// Merges the element sets of a two or three line element file into the catalog. Malformed
+// entries are reported as by ParseTLEs after the good ones have been merged.
+func (c *Catalog) ReadTLEs(r io.Reader, gravconst GravModel) error {
+	sats, err := ParseTLEs(r, gravconst)
+	c.Merge(sats...)
+	return err
+}
+
+// Returns the members whose name matches, ignoring case and punctuation, ordered by
+// catalog number
+func (c *Catalog) ByName(name string) []Satellite {
+	key := nameKey(name)
+	if key == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sortLocked()
+
+	var sats []Satellite
+	for i := range c.sats {
+		if nameKey(c.sats[i].Name) == key {
+			sats = append(sats, c.sats[i])
+		}
+	}
+	return sats
+}
+
+// Returns the member with a catalog number such as "25544" or a name such as "ISS (ZARYA)".
+// Returns ErrUnknownSatellite when none matches and ErrAmbiguousName when several share
+// the name.
+func (c *Catalog) Lookup(query string) (Satellite, error) {
+	if satnum, err := strconv.ParseInt(strings.TrimSpace(query), 10, 64); err == nil {
+		sat, ok := c.Get(satnum)
+		if !ok {
+			return sat, newError(ErrUnknownSatellite, "no satellite with catalog number %d", satnum)
+		}
+		return sat, nil
+	}
+	sats := c.ByName(query)
+	switch len(sats) {
+	case 0:
+		return Satellite{}, newError(ErrUnknownSatellite, "no satellite named %q", query)
+	case 1:
+		return sats[0], nil
+	}
+	return Satellite{}, newError(ErrAmbiguousName, "%q matches catalog numbers %d and %d", query, sats[0].Satnum, sats[1].Satnum)
+}
+
+// Orders the members by catalog number, the caller must hold the write lock
+func (c *Catalog) sortLocked() {
+	if c.sorted {
+		return
+	}
+	sort.Slice(c.sats, func(i, j int) bool { return c.sats[i].Satnum < c.sats[j].Satnum })
+	for i := range c.sats {
+		c.index[c.sats[i].Satnum] = i
+	}
+	c.sorted = true
+}
+
+// Returns the satellite with the given catalog number
+func (c *Catalog) Get(satnum int64) (sat Satellite, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	i, ok := c.index[satnum]
+	if !ok {
+		return sat, false
+	}
+	return c.sats[i], true
+}
+
+// Returns the number of satellites in the catalog
+func (c *Catalog) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.sats)
+}
+
+// Returns a copy of all satellites ordered by catalog number
+func (c *Catalog) Satellites() []Satellite {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sortLocked()
+	return append([]Satellite(nil), c.sats...)
+}
+
+// State of one catalog member at a snapshot time
+type SatState struct {
+	Satnum int64
+
+	// Position in km and velocity in km/s in the inertial (TEME) frame
+	Position, Velocity Vector3
+
+	// Position in km in the Earth fixed frame
+	ECEF Vector3
+
+	// Set when the satellite could not be propagated to the snapshot time
+	Err error
+}
+
+// Propagates every member of the catalog to t using all CPU cores. The sidereal
+// time is computed once and shared by all members. States are ordered by catalog number.
+func (c *Catalog) Snapshot(t time.Time) []SatState {
+	return c.SnapshotContext(context.Background(), t)
+}
+
+// Snapshot with a context carrying the parent span of the propagation
+func (c *Catalog) SnapshotContext(ctx context.Context, t time.Time) []SatState {
+	sats := c.Satellites()
+	_, span := startSpan(ctx, "satellite.Catalog.Snapshot", slog.Int("satellite.count", len(sats)))
+	defer span.End(nil)
+
+	gmst := gmstAt(t)
+	states := make([]SatState, len(sats))
+
+	workers := runtime.GOMAXPROCS(0)
+	chunk := (len(sats) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(sats); lo += chunk {
+		hi := min(lo+chunk, len(sats))
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				pos, vel, err := sats[i].propagateAt(t)
+				states[i] = SatState{Satnum: sats[i].Satnum, Position: pos, Velocity: vel, Err: err}
+				if err == nil {
+					states[i].ECEF = ECIToECEF(pos, gmst)
+				}
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+
+	return states
+}
@@ -0,0 +1,144 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Catalog", func() {
+	It("should keep one satellite per catalog number ordered by number", func() {
+		sats := jobTestSatellites()
+		catalog := NewCatalog(sats[3], sats[1], sats[0], sats[1])
+
+		Expect(catalog.Len()).To(Equal(3))
+		members := catalog.Satellites()
+		Expect(members[0].Satnum).To(Equal(int64(25544)))
+		Expect(members[1].Satnum).To(Equal(int64(25545)))
+		Expect(members[2].Satnum).To(Equal(int64(33591)))
+
+		sat, ok := catalog.Get(33591)
+		Expect(ok).To(BeTrue())
+		Expect(sat.Line1).To(Equal(sats[3].Line1))
+		_, ok = catalog.Get(1)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should prefer supplemental element sets when asked", func() {
+		gps, err := ReadGPCSV(strings.NewReader(gpCSV))
+		Expect(err).To(BeNil())
+		gps[0].Source = "NASA"
+		sup, err := NewSatFromGP(gps[0], "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sup.Supplemental()).To(BeTrue())
+		Expect(sup.Source()).To(Equal("NASA"))
+		standard := jobTestSatellites()[0]
+		Expect(standard.Supplemental()).To(BeFalse())
+
+		catalog := NewCatalog(sup, standard)
+		got, _ := catalog.Get(25544)
+		Expect(got.Supplemental()).To(BeFalse())
+
+		catalog = &Catalog{PreferSupplemental: true}
+		catalog.Add(sup)
+		catalog.Add(standard)
+		got, _ = catalog.Get(25544)
+		Expect(got.Source()).To(Equal("NASA"))
+		catalog.Add(standard)
+		catalog.Add(sup)
+		Expect(catalog.Len()).To(Equal(1))
+	})
+
+	It("should merge element sets keeping the latest epoch", func() {
+		catalog := NewCatalog(issTLE.after(1).node(248).sat())
+		Expect(catalog.Merge(issTLE.node(247).sat(), jobTestSatellites()[3])).To(Equal(1))
+		got, _ := catalog.Get(25544)
+		Expect(got.Line2).To(Equal(issTLE.after(1).node(248).sat().Line2))
+
+		Expect(catalog.Merge(issTLE.after(2).node(249).sat(), issTLE.after(2).node(249).sat())).To(Equal(1))
+		got, _ = catalog.Get(25544)
+		Expect(got.Line2).To(Equal(issTLE.after(2).node(249).sat().Line2))
+		Expect(catalog.Len()).To(Equal(2))
+	})
+
+	It("should look members up by name", func() {
+		catalog := &Catalog{}
+		Expect(catalog.ReadTLEs(strings.NewReader(tleFile), "wgs72")).To(BeNil())
+		Expect(catalog.Len()).To(Equal(2))
+
+		Expect(catalog.ByName("iss zarya")).To(HaveLen(1))
+		sat, err := catalog.Lookup("ISS (ZARYA)")
+		Expect(err).To(BeNil())
+		Expect(sat.Satnum).To(Equal(int64(25544)))
+		sat, err = catalog.Lookup("33591")
+		Expect(err).To(BeNil())
+		Expect(sat.Satnum).To(Equal(int64(33591)))
+
+		_, err = catalog.Lookup("NOAA 19")
+		Expect(errors.Is(err, ErrUnknownSatellite)).To(BeTrue())
+		twin := jobTestSatellites()[1]
+		twin.Name = "ISS ZARYA"
+		catalog.Add(twin)
+		_, err = catalog.Lookup("iss (zarya)")
+		Expect(errors.Is(err, ErrAmbiguousName)).To(BeTrue())
+	})
+
+	It("should snapshot all members at one instant", func() {
+		sats := jobTestSatellites()
+		catalog := NewCatalog(sats...)
+		t := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+
+		states := catalog.Snapshot(t)
+		Expect(states).To(HaveLen(len(sats)))
+		for i, state := range states {
+			Expect(state.Err).To(BeNil())
+			Expect(state.Satnum).To(Equal(sats[i].Satnum))
+
+			pos, vel, err := sats[i].propagateAt(t)
+			Expect(err).To(BeNil())
+			Expect(state.Position).To(Equal(pos))
+			Expect(state.Velocity).To(Equal(vel))
+			Expect(state.ECEF).To(Equal(ECIToECEF(pos, gmstAt(t))))
+		}
+	})
+
+	It("should merge the passes of all members into one timeline", func() {
+		sats := jobTestSatellites()
+		catalog := NewCatalog(sats[0], sats[3])
+		obs := NewLatLongAlt(55.6167, 12.6500, 0.005)
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		stop := start.Add(24 * time.Hour)
+
+		passes, err := catalog.Passes(context.Background(), obs, start, stop, PassOptions{})
+		Expect(err).To(BeNil())
+
+		iss, err := PredictPasses(&sats[0], obs, start, stop, PassOptions{})
+		Expect(err).To(BeNil())
+		noaa, err := PredictPasses(&sats[3], obs, start, stop, PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(passes).To(HaveLen(len(iss) + len(noaa)))
+		for i := 1; i < len(passes); i++ {
+			Expect(passes[i].AOS).NotTo(BeTemporally("<", passes[i-1].AOS))
+		}
+	})
+
+	It("should skip members whose ground track stays away from the observer", func() {
+		sats := jobTestSatellites()
+		catalog := NewCatalog(sats[0], sats[3])
+		obs := NewLatLongAlt(80, 15, 0)
+		Expect(sats[0].canRiseOver(obs, PassOptions{})).To(BeFalse())
+		Expect(sats[3].canRiseOver(obs, PassOptions{})).To(BeTrue())
+
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		passes, err := catalog.Passes(context.Background(), obs, start, start.Add(24*time.Hour), PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(passes).NotTo(BeEmpty())
+		for _, p := range passes {
+			Expect(p.Satnum).To(Equal(sats[3].Satnum))
+		}
+	})
+})
@@ -0,0 +1,298 @@
+// Package celestrak fetches element sets published by CelesTrak into satellite catalogs.
+package celestrak
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	satellite "github.com/mpielikis/go-satellite"
+)
+
+// Default location of the CelesTrak general perturbations query
+const DefaultBaseURL = "https://celestrak.org/NORAD/elements/gp.php"
+
+// Default location of the CelesTrak supplemental GP query
+const DefaultSupplementalURL = "https://celestrak.org/NORAD/elements/supplemental/sup-gp.php"
+
+// Named satellite group published by CelesTrak
+type Group string
+
+const (
+	Stations       Group = "stations"
+	Visual         Group = "visual"
+	Active         Group = "active"
+	LastThirtyDays Group = "last-30-days"
+	Weather        Group = "weather"
+	NOAA           Group = "noaa"
+	GOES           Group = "goes"
+	Resource       Group = "resource"
+	SARSAT         Group = "sarsat"
+	Science        Group = "science"
+	Geodetic       Group = "geodetic"
+	Amateur        Group = "amateur"
+	Cubesat        Group = "cubesat"
+	GEO            Group = "geo"
+	Intelsat       Group = "intelsat"
+	Iridium        Group = "iridium-NEXT"
+	Starlink       Group = "starlink"
+	OneWeb         Group = "oneweb"
+	Orbcomm        Group = "orbcomm"
+	Globalstar     Group = "globalstar"
+	GPSOps         Group = "gps-ops"
+	GlonassOps     Group = "glo-ops"
+	Galileo        Group = "galileo"
+	Beidou         Group = "beidou"
+	SBAS           Group = "sbas"
+)
+
+// Operator data source of CelesTrak supplemental element sets
+type Source string
+
+const (
+	SupStarlink Source = "starlink"
+	SupOneWeb   Source = "oneweb"
+	SupPlanet   Source = "planet"
+	SupIridium  Source = "iridium"
+	SupGPS      Source = "gps"
+	SupGlonass  Source = "glonass"
+	SupIntelsat Source = "intelsat"
+	SupSES      Source = "ses"
+	SupTelesat  Source = "telesat"
+	SupOrbcomm  Source = "orbcomm"
+	SupKuiper   Source = "kuiper"
+	SupISS      Source = "iss"
+	SupCSS      Source = "css"
+	SupMeteosat Source = "meteosat"
+)
+
+// Client for CelesTrak queries. The zero value uses http.DefaultClient, DefaultBaseURL,
+// DefaultSupplementalURL and the wgs72 gravity model.
+//
+// Responses carrying an ETag or Last-Modified header are kept, and repeated queries send
+// If-None-Match and If-Modified-Since so CelesTrak answers 304 Not Modified until the
+// element sets are updated, sparing its download limits.
+type Client struct {
+	HTTPClient      *http.Client
+	BaseURL         string
+	SupplementalURL string
+
+	// Gravity model passed to satellite.NewSatFromTLE and satellite.NewSatFromGP
+	Gravity satellite.GravModel
+
+	// Turns off keeping responses for conditional requests
+	NoCache bool
+
+	mu    sync.Mutex
+	cache map[string]cachedResponse
+}
+
+// Response body kept with its validators
+type cachedResponse struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// Client used by the package level functions
+var DefaultClient = &Client{}
+
+// Fetches the element sets of a group into a new catalog
+func FetchGroup(ctx context.Context, group Group) (*satellite.Catalog, error) {
+	return DefaultClient.FetchGroup(ctx, group)
+}
+
+// Fetches the element sets of a group into a new catalog. Malformed element sets are left
+// out of the catalog and reported as *satellite.TLEEntryError.
+func (c *Client) FetchGroup(ctx context.Context, group Group) (*satellite.Catalog, error) {
+	sats, err := c.FetchGroupSatellites(ctx, group)
+	if err != nil && !isEntryError(err) {
+		return nil, err
+	}
+	return satellite.NewCatalog(sats...), err
+}
+
+// Fetches the element sets of a group and merges them into a catalog, keeping the latest
+// epoch of every object. Returns the number of members added or replaced. Malformed element
+// sets are left out and reported as *satellite.TLEEntryError.
+func (c *Client) MergeGroup(ctx context.Context, group Group, catalog *satellite.Catalog) (int, error) {
+	sats, err := c.FetchGroupSatellites(ctx, group)
+	if err != nil && !isEntryError(err) {
+		return 0, err
+	}
+	return catalog.Merge(sats...), err
+}
+
+// Fetches the element sets of a group as initialized satellites
+func FetchGroupSatellites(ctx context.Context, group Group) ([]satellite.Satellite, error) {
+	return DefaultClient.FetchGroupSatellites(ctx, group)
+}
+
+// Fetches the element sets of a group as initialized satellites. Unchanged groups are
+// parsed from the kept response. Malformed element sets are reported as
+// *satellite.TLEEntryError next to the satellites of the good ones.
+func (c *Client) FetchGroupSatellites(ctx context.Context, group Group) ([]satellite.Satellite, error) {
+	log := satellite.Logger().With("group", group)
+	sats, err := c.query(ctx, url.Values{"GROUP": {string(group)}, "FORMAT": {"tle"}})
+	if err != nil {
+		log.Warn("celestrak group fetch failed", "satellites", len(sats), "err", err)
+		return sats, fmt.Errorf("celestrak group %s: %w", group, err)
+	}
+	log.Info("celestrak group fetched", "satellites", len(sats))
+	return sats, nil
+}
+
+// Fetches the supplemental element sets of an operator source. The satellites report the
+// source through Supplemental and Source. Element sets of ephemeris type 4, which need
+// SGP4-XP, are left out.
+func FetchSupplemental(ctx context.Context, source Source) ([]satellite.Satellite, error) {
+	return DefaultClient.FetchSupplemental(ctx, source)
+}
+
+// Fetches the supplemental element sets of an operator source. The satellites report the
+// source through Supplemental and Source. Element sets of ephemeris type 4, which need
+// SGP4-XP, are left out.
+func (c *Client) FetchSupplemental(ctx context.Context, source Source) ([]satellite.Satellite, error) {
+	log := satellite.Logger().With("source", source)
+	sats, err := c.supplemental(ctx, source)
+	if err != nil {
+		log.Warn("celestrak supplemental fetch failed", "satellites", len(sats), "err", err)
+		return sats, fmt.Errorf("celestrak supplemental %s: %w", source, err)
+	}
+	log.Info("celestrak supplemental fetched", "satellites", len(sats))
+	return sats, nil
+}
+
+// Fetches and initializes the supplemental element sets of an operator source
+func (c *Client) supplemental(ctx context.Context, source Source) ([]satellite.Satellite, error) {
+	base := c.SupplementalURL
+	if base == "" {
+		base = DefaultSupplementalURL
+	}
+	body, err := c.get(ctx, base, url.Values{"SOURCE": {string(source)}, "FORMAT": {"csv"}})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	gps, err := satellite.ReadGPCSV(body)
+	if err != nil {
+		return nil, err
+	}
+	sats := make([]satellite.Satellite, 0, len(gps))
+	for _, gp := range gps {
+		if gp.EphemerisType == 4 {
+			continue
+		}
+		if gp.Source == "" {
+			gp.Source = string(source)
+		}
+		sat, err := satellite.NewSatFromGP(gp, c.gravity())
+		if err != nil {
+			return sats, err
+		}
+		sats = append(sats, sat)
+	}
+	return sats, nil
+}
+
+// Runs a query and parses the returned element sets
+func (c *Client) query(ctx context.Context, params url.Values) ([]satellite.Satellite, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	body, err := c.get(ctx, base, params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return c.parse(body)
+}
+
+// Runs a query and returns the response body, or the kept body when the server reports it
+// not modified
+func (c *Client) get(ctx context.Context, base string, params url.Values) (io.ReadCloser, error) {
+	u := base + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	cached, ok := c.cached(u)
+	if ok {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	log := satellite.Logger().With("url", u)
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Debug("celestrak request failed", "err", err)
+		return nil, err
+	}
+	log.Debug("celestrak response", "status", resp.StatusCode, "cached", ok)
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return io.NopCloser(bytes.NewReader(cached.body)), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if c.NoCache || (etag == "" && lastModified == "") {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = map[string]cachedResponse{}
+	}
+	c.cache[u] = cachedResponse{etag: etag, lastModified: lastModified, body: body}
+	c.mu.Unlock()
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// Returns the kept response of a query URL
+func (c *Client) cached(u string) (cachedResponse, bool) {
+	if c.NoCache {
+		return cachedResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.cache[u]
+	return cached, ok
+}
+
+func (c *Client) gravity() satellite.GravModel {
+	if c.Gravity == "" {
+		return satellite.WGS72
+	}
+	return c.Gravity
+}
+
+// Reports whether err holds malformed element sets rather than a failed query
+func isEntryError(err error) bool {
+	var entry *satellite.TLEEntryError
+	return errors.As(err, &entry)
+}
+
+// Parses element sets in two or three line format
+func (c *Client) parse(r io.Reader) ([]satellite.Satellite, error) {
+	return satellite.ParseTLEs(r, c.gravity())
+}
@@ -0,0 +1,13 @@
+package celestrak
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCelestrak(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Celestrak Suite")
+}
@@ -0,0 +1,189 @@
+package celestrak
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	satellite "github.com/mpielikis/go-satellite"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const stations = `ISS (ZARYA)             
+1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927
+2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537
+NOAA 19
+1 33591U 09005A   08264.48990228  .00000077  00000-0  66998-4 0  9990
+2 33591  99.0394 120.2160 0013054 232.8317 127.1662 14.12079902378332
+`
+
+var _ = Describe("FetchGroup", func() {
+	var server *httptest.Server
+	var query string
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			if r.URL.Query().Get("GROUP") != string(Stations) {
+				http.Error(w, "unknown group", http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(stations))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return a catalog of the group members", func() {
+		client := &Client{BaseURL: server.URL}
+		catalog, err := client.FetchGroup(context.Background(), Stations)
+		Expect(err).To(BeNil())
+		Expect(query).To(Equal("FORMAT=tle&GROUP=stations"))
+		Expect(catalog.Len()).To(Equal(2))
+
+		iss, ok := catalog.Get(25544)
+		Expect(ok).To(BeTrue())
+		Expect(iss.Line2).To(HavePrefix("2 25544"))
+		Expect(iss.Name).To(Equal("ISS (ZARYA)"))
+		noaa, ok := catalog.Get(33591)
+		Expect(ok).To(BeTrue())
+		Expect(noaa.Name).To(Equal("NOAA 19"))
+	})
+
+	It("should merge a group into a catalog", func() {
+		client := &Client{BaseURL: server.URL}
+		catalog := &satellite.Catalog{}
+		n, err := client.MergeGroup(context.Background(), Stations, catalog)
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(2))
+		n, err = client.MergeGroup(context.Background(), Stations, catalog)
+		Expect(err).To(BeNil())
+		Expect(n).To(Equal(0))
+		Expect(catalog.Len()).To(Equal(2))
+	})
+
+	It("should report failed queries", func() {
+		client := &Client{BaseURL: server.URL}
+		_, err := client.FetchGroup(context.Background(), Starlink)
+		Expect(err).To(MatchError(ContainSubstring("starlink")))
+	})
+
+	It("should log fetches to the package logger", func() {
+		var buf bytes.Buffer
+		satellite.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		defer satellite.SetLogger(nil)
+
+		client := &Client{BaseURL: server.URL}
+		client.FetchGroup(context.Background(), Stations)
+		client.FetchGroup(context.Background(), Starlink)
+		Expect(buf.String()).To(ContainSubstring("celestrak response"))
+		Expect(buf.String()).To(ContainSubstring("status=404"))
+		Expect(buf.String()).To(ContainSubstring(`msg="celestrak group fetched" group=stations satellites=2`))
+		Expect(buf.String()).To(ContainSubstring(`msg="celestrak group fetch failed" group=starlink`))
+	})
+
+	It("should parse unchanged groups from the kept response", func() {
+		requests := 0
+		cached := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(stations))
+		}))
+		defer cached.Close()
+
+		client := &Client{BaseURL: cached.URL}
+		first, err := client.FetchGroupSatellites(context.Background(), Stations)
+		Expect(err).To(BeNil())
+		second, err := client.FetchGroupSatellites(context.Background(), Stations)
+		Expect(err).To(BeNil())
+		Expect(requests).To(Equal(2))
+		Expect(second).To(HaveLen(2))
+		Expect(second[0].Line1).To(Equal(first[0].Line1))
+	})
+
+	It("should send the validators of kept responses unless NoCache is set", func() {
+		var modifiedSince []string
+		cached := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			modifiedSince = append(modifiedSince, r.Header.Get("If-Modified-Since"))
+			w.Header().Set("Last-Modified", "Sat, 20 Sep 2008 12:00:00 GMT")
+			w.Write([]byte(stations))
+		}))
+		defer cached.Close()
+
+		client := &Client{BaseURL: cached.URL}
+		client.FetchGroup(context.Background(), Stations)
+		client.FetchGroup(context.Background(), Stations)
+		client.NoCache = true
+		client.FetchGroup(context.Background(), Stations)
+		Expect(modifiedSince).To(Equal([]string{"", "Sat, 20 Sep 2008 12:00:00 GMT", ""}))
+	})
+
+	It("should keep the good element sets of a group with malformed ones", func() {
+		partial := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(stations + "BROKEN\n1 99999U short\n2 99999 short\n"))
+		}))
+		defer partial.Close()
+
+		client := &Client{BaseURL: partial.URL}
+		catalog, err := client.FetchGroup(context.Background(), Stations)
+		var entry *satellite.TLEEntryError
+		Expect(errors.As(err, &entry)).To(BeTrue())
+		Expect(entry.Name).To(Equal("BROKEN"))
+		Expect(catalog.Len()).To(Equal(2))
+
+		merged := &satellite.Catalog{}
+		n, err := client.MergeGroup(context.Background(), Stations, merged)
+		Expect(errors.As(err, &entry)).To(BeTrue())
+		Expect(n).To(Equal(2))
+		Expect(merged.Len()).To(Equal(2))
+	})
+
+	It("should report malformed element sets", func() {
+		client := &Client{}
+		_, err := client.parse(strings.NewReader("1 25544U short\n2 25544 short\n"))
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+const starlinkSup = `OBJECT_NAME,OBJECT_ID,EPOCH,MEAN_MOTION,ECCENTRICITY,INCLINATION,RA_OF_ASC_NODE,ARG_OF_PERICENTER,MEAN_ANOMALY,EPHEMERIS_TYPE,CLASSIFICATION_TYPE,NORAD_CAT_ID,ELEMENT_SET_NO,REV_AT_EPOCH,BSTAR,MEAN_MOTION_DOT,MEAN_MOTION_DDOT
+STARLINK-1007,2019-074A,2008-09-20T12:25:40.104192,15.06391,.0001362,53.0536,247.4627,85.1376,274.9826,0,U,44713,999,0,.00012,.00001,0
+STARLINK-1008,2019-074B,2008-09-20T12:25:40.104192,15.06391,.0001362,53.0536,247.4627,85.1376,274.9826,4,U,44714,999,0,.00012,.00001,0
+`
+
+var _ = Describe("FetchSupplemental", func() {
+	var server *httptest.Server
+	var query string
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Write([]byte(starlinkSup))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return supplemental satellites without SGP4-XP element sets", func() {
+		client := &Client{SupplementalURL: server.URL}
+		sats, err := client.FetchSupplemental(context.Background(), SupStarlink)
+		Expect(err).To(BeNil())
+		Expect(query).To(Equal("FORMAT=csv&SOURCE=starlink"))
+		Expect(sats).To(HaveLen(1))
+		Expect(sats[0].Satnum).To(Equal(int64(44713)))
+		Expect(sats[0].Supplemental()).To(BeTrue())
+		Expect(sats[0].Source()).To(Equal("starlink"))
+	})
+})
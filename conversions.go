@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// Earth rotation rate in rad/s
+const earthAngularVelocity = 7.292115146706979e-5
+
 // this procedure converts the day of the year, epochDays, to the equivalent month day, hour, minute and second.
 func days2mdhms(year int64, epochDays float64) (mon, day, hr, min, sec float64) {
 	lmonth := [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
@@ -38,10 +41,43 @@ func days2mdhms(year int64, epochDays float64) (mon, day, hr, min, sec float64)
 	return
 }
 
+// Returns the julian date of t in UTC, keeping the fraction of a second
 func NewJDayFromTime(t time.Time) JDay {
+	t = t.UTC()
 	year, month, day := t.Date()
 	hour, min, sec := t.Clock()
-	return NewJDay(year, int(month), day, hour, min, float64(sec))
+	return NewJDay(year, int(month), day, hour, min, float64(sec)+float64(t.Nanosecond())/1e9)
+}
+
+// Converts a julian date back into a UTC time
+func (jd JDay) ToTime() time.Time {
+	// 2440587.5 is the julian date of the unix epoch
+	days := jd.Day - 2440587.5
+	whole := math.Floor(days)
+	fraction := days - whole + jd.Fraction
+	return time.Unix(int64(whole)*86400, 0).UTC().Add(time.Duration(fraction * 86400 * float64(time.Second)))
+}
+
+// Returns the julian date a duration later, with the fraction kept in [0, 1)
+func (jd JDay) AddDuration(d time.Duration) JDay {
+	whole := d / (24 * time.Hour)
+	jd.Day += float64(whole)
+	jd.Fraction += (d - whole*24*time.Hour).Seconds() / 86400
+	carry := math.Floor(jd.Fraction)
+	jd.Day += carry
+	jd.Fraction -= carry
+	return jd
+}
+
+// Returns the modified julian date, days since midnight of 17 November 1858
+func (jd JDay) MJD() float64 {
+	return (jd.Day - 2400000.5) + jd.Fraction
+}
+
+// Returns the julian date of a modified julian date
+func NewJDayFromMJD(mjd float64) JDay {
+	whole := math.Floor(mjd)
+	return JDay{whole + 2400000.5, mjd - whole}
 }
 
 // Calc julian date given year, month, day, hour, minute and second
@@ -53,8 +89,8 @@ func NewJDay(year, mon, day, hr, minute int, sec float64) JDay {
 }
 
 // this function finds the greenwich sidereal time (iau-82)
-func gstime(jdut1 float64) (temp float64) {
-	tut1 := (jdut1 - 2451545.0) / 36525.0
+func gstime(jdut1 JDay) (temp float64) {
+	tut1 := ((jdut1.Day - 2451545.0) + jdut1.Fraction) / 36525.0
 	temp = -6.2e-6*tut1*tut1*tut1 + 0.093104*tut1*tut1 + (876600.0*3600+8640184.812866)*tut1 + 67310.54841
 	temp = math.Mod((temp * DEG2RAD / 240.0), TWOPI)
 
@@ -65,42 +101,112 @@ func gstime(jdut1 float64) (temp float64) {
 	return
 }
 
+// Returns the greenwich mean sidereal time at t, taking UT1 as UTC
+func gmstAt(t time.Time) float64 {
+	return NewInstant(t).GMST()
+}
+
 // Calc GST given year, month, day, hour, minute and second
 func GSTimeFromDate(year, mon, day, hr, min int, sec float64) float64 {
 	jDay := NewJDay(year, mon, day, hr, min, sec)
-	return gstime(jDay.Single())
+	return gstime(jDay)
+}
+
+// Returns the local mean sidereal time in radians in [0, 2pi) at a julian date in UT1 for an
+// observer at the east longitude in radians
+func LocalSiderealTime(jday JDay, longitude Radians) Radians {
+	return Radians(gstime(jday) + float64(longitude)).Normalize()
+}
+
+// Returns the equation of the equinoxes, apparent minus mean sidereal time, in radians at a
+// julian date (IAU 1994). The date is taken as TT; a UT1 date moves the result by less than
+// 0.0001 arcseconds.
+func EquationOfEquinoxes(jday JDay) float64 {
+	return equationOfEquinoxes(((jday.Day - 2451545.0) + jday.Fraction) / 36525.0)
+}
+
+// Returns the greenwich apparent sidereal time in radians at a julian date in UT1, GMST
+// (IAU-82) plus the equation of the equinoxes. Pass it in place of GMST to ECIToECEF, ECEFToECI
+// and ECIToLLA to convert true of date coordinates, as astronomical software uses; TEME
+// coordinates from SGP4 rotate with GMST.
+func GAST(jdut1 JDay) float64 {
+	return math.Mod(gstime(jdut1)+EquationOfEquinoxes(jdut1)+TWOPI, TWOPI)
 }
 
 // Convert Earth Centered Inertial coordinated into equivalent latitude, longitude, altitude and velocity.
+// The velocity approximates the speed of a circular orbit at the altitude, see ECIStateToLLA
+// for the actual speed.
 // Reference: http://celestrak.com/columns/v02n03/
 func ECIToLLA(eciCoords Vector3, gmst float64) (altitude, velocity float64, ret LatLong) {
-	a := 6378.137     // Semi-major Axis
-	b := 6356.7523142 // Semi-minor Axis
-	f := (a - b) / a  // Flattening
-	e2 := ((2 * f) - math.Pow(f, 2))
-
-	sqx2y2 := math.Sqrt(math.Pow(eciCoords.X, 2) + math.Pow(eciCoords.Y, 2))
-
-	// Spherical Earth Calculations
-	longitude := math.Atan2(eciCoords.Y, eciCoords.X) - gmst
-	latitude := math.Atan2(eciCoords.Z, sqx2y2)
-
-	// Oblate Earth Fix
-	C := 0.0
-	for i := 0; i < 20; i++ {
-		C = 1 / math.Sqrt(1-e2*(math.Sin(latitude)*math.Sin(latitude)))
-		latitude = math.Atan2(eciCoords.Z+(a*C*e2*math.Sin(latitude)), sqx2y2)
-	}
+	sqx2y2 := math.Sqrt(eciCoords.X*eciCoords.X + eciCoords.Y*eciCoords.Y)
 
-	// Calc Alt
-	altitude = (sqx2y2 / math.Cos(latitude)) - (a * C)
+	ret.Latitude, altitude = geodeticLatAlt(sqx2y2, eciCoords.Z)
+	ret.Longitude = math.Atan2(eciCoords.Y, eciCoords.X) - gmst
 
 	// Orbital Speed ≈ sqrt(μ / r) where μ = std. gravitaional parameter
 	velocity = math.Sqrt(398600.4418 / (altitude + 6378.137))
 
-	ret.Latitude = latitude
-	ret.Longitude = longitude
+	return
+}
 
+// Convert Earth Centered Inertial position and velocity into geodetic latitude, longitude in
+// [-pi, pi) and altitude, the inertial speed in km/s and the velocity relative to the rotating
+// Earth in km/s along the local east (X), north (Y) and up (Z) directions
+func ECIStateToLLA(eciPos, eciVel Vector3, gmst float64) (lla LatLongAlt, speed float64, groundVel Vector3) {
+	altitude, _, ll := ECIToLLA(eciPos, gmst)
+	lla = LatLongAlt{LatLong: LatLong{Latitude: ll.Latitude, Longitude: wrapPi(ll.Longitude)}, AltitudeKm: altitude}
+	speed = eciVel.Magnitude()
+
+	_, ecfVel := ECIToECEFState(eciPos, eciVel, gmst)
+	sinLat, cosLat := math.Sincos(lla.LatLong.Latitude)
+	sinLon, cosLon := math.Sincos(lla.LatLong.Longitude)
+	groundVel.X = -sinLon*ecfVel.X + cosLon*ecfVel.Y
+	groundVel.Y = -sinLat*cosLon*ecfVel.X - sinLat*sinLon*ecfVel.Y + cosLat*ecfVel.Z
+	groundVel.Z = cosLat*cosLon*ecfVel.X + cosLat*sinLon*ecfVel.Y + sinLat*ecfVel.Z
+	return
+}
+
+// WGS84 ellipsoid used by the geodetic conversions
+const (
+	wgs84A = 6378.137     // Semi-major Axis
+	wgs84B = 6356.7523142 // Semi-minor Axis
+)
+
+// Returns the geodetic latitude and the height above the ellipsoid for a point given by
+// its distance from the polar axis and its height above the equatorial plane in km
+func geodeticLatAlt(p, z float64) (latitude, altitude float64) {
+	return geodeticLatAltOn(p, z, wgs84A, wgs84B)
+}
+
+// Returns the geodetic latitude and height on the ellipsoid with semi-major axis a and
+// semi-minor axis b in km, in closed form without iteration. Latitudes are exact to the
+// float64 rounding, within 1e-15 rad, and heights within 1e-8 km out to the distance of
+// the Moon, for points more than about 50 km from the center of the ellipsoid.
+// Reference: Vermeille, H. (2002), Direct transformation from geocentric coordinates to
+// geodetic coordinates, Journal of Geodesy 76(8).
+func geodeticLatAltOn(p, z, a, b float64) (latitude, altitude float64) {
+	e2 := 1 - (b*b)/(a*a)
+	e4 := e2 * e2
+
+	if p == 0 {
+		return math.Copysign(math.Pi/2, z), math.Abs(z) - b
+	}
+
+	pp := p * p / (a * a)
+	q := (1 - e2) / (a * a) * z * z
+	r := (pp + q - e4) / 6
+	s := e4 * pp * q / (4 * r * r * r)
+	t := math.Cbrt(1 + s + math.Sqrt(s*(2+s)))
+	u := r * (1 + t + 1/t)
+	v := math.Sqrt(u*u + e4*q)
+	w := e2 * (u + v - q) / (2 * v)
+	k := math.Sqrt(u+v+w*w) - w
+	d := k * p / (k + e2)
+	latitude = 2 * math.Atan2(z, d+math.Hypot(d, z))
+
+	// Height valid at all latitudes, including near the poles
+	sinLat, cosLat := math.Sincos(latitude)
+	altitude = p*cosLat + z*sinLat - a*math.Sqrt(1-e2*sinLat*sinLat)
 	return
 }
 
@@ -123,10 +229,13 @@ func LatLongDeg(rad LatLong) (deg LatLong, err error) {
 
 // Calculate GMST from Julian date.
 // Reference: The 1992 Astronomical Almanac, page B6.
-func ThetaG_JD(jday float64) (ret float64) {
-	_, UT := math.Modf(jday + 0.5)
-	jday = jday - UT
-	TU := (jday - 2451545.0) / 36525.0
+func ThetaG_JD(jday JDay) (ret float64) {
+	// Split into the julian date of the previous midnight and the fraction of day since
+	midnight := math.Floor(jday.Day+0.5) - 0.5
+	UT := (jday.Day - midnight) + jday.Fraction
+	whole := math.Floor(UT)
+	midnight, UT = midnight+whole, UT-whole
+	TU := (midnight - 2451545.0) / 36525.0
 	GMST := 24110.54841 + TU*(8640184.812866+TU*(0.093104-TU*6.2e-6))
 	GMST = math.Mod(GMST+86400.0*1.00273790934*UT, 86400.0)
 	ret = 2 * math.Pi * GMST / 86400.0
@@ -135,7 +244,7 @@ func ThetaG_JD(jday float64) (ret float64) {
 
 // Convert latitude, longitude and altitude into equivalent Earth Centered Intertial coordinates
 // Reference: The 1992 Astronomical Almanac, page K11.
-func LLAToECI(obsCoords LatLongAlt, jday float64, gravConst GravConst) (eciObs Vector3) {
+func LLAToECI(obsCoords LatLongAlt, jday JDay, gravConst GravConst) (eciObs Vector3) {
 	theta := math.Mod(ThetaG_JD(jday)+obsCoords.LatLong.Longitude, TWOPI)
 	latSin := math.Sin(obsCoords.LatLong.Latitude)
 	latCos := math.Cos(obsCoords.LatLong.Latitude)
@@ -149,6 +258,77 @@ func LLAToECI(obsCoords LatLongAlt, jday float64, gravConst GravConst) (eciObs V
 	return
 }
 
+// Astronomical unit in km
+const auKm = 149597870.7
+
+// Calculate the position of the Sun in km in Earth Centered Inertial coordinates for a julian date.
+// Reference: The Astronomical Almanac, low precision formulas for the Sun (accurate to 0.01 deg).
+func SunPositionECI(jday JDay) (eciSun Vector3) {
+	n := (jday.Day - 2451545.0) + jday.Fraction
+	L := math.Mod(280.460+0.9856474*n, 360) * DEG2RAD
+	g := math.Mod(357.528+0.9856003*n, 360) * DEG2RAD
+	lambda := L + (1.915*math.Sin(g)+0.020*math.Sin(2*g))*DEG2RAD
+	epsilon := (23.439 - 0.0000004*n) * DEG2RAD
+	r := (1.00014 - 0.01671*math.Cos(g) - 0.00014*math.Cos(2*g)) * auKm
+
+	eciSun.X = r * math.Cos(lambda)
+	eciSun.Y = r * math.Cos(epsilon) * math.Sin(lambda)
+	eciSun.Z = r * math.Sin(epsilon) * math.Sin(lambda)
+	return
+}
+
+// Calculate the position of the Moon in km in Earth Centered Inertial coordinates for a julian date.
+// Reference: The Astronomical Almanac, low precision formulas for the Moon (accurate to 0.3 deg).
+func MoonPositionECI(jday JDay) (eciMoon Vector3) {
+	T := ((jday.Day - 2451545.0) + jday.Fraction) / 36525.0
+	sinDeg := func(a float64) float64 { return math.Sin(math.Mod(a, 360) * DEG2RAD) }
+	cosDeg := func(a float64) float64 { return math.Cos(math.Mod(a, 360) * DEG2RAD) }
+
+	lambda := (218.32 + 481267.8813*T +
+		6.29*sinDeg(134.9+477198.85*T) - 1.27*sinDeg(259.2-413335.38*T) +
+		0.66*sinDeg(235.7+890534.23*T) + 0.21*sinDeg(269.9+954397.70*T) -
+		0.19*sinDeg(357.5+35999.05*T) - 0.11*sinDeg(186.6+966404.05*T)) * DEG2RAD
+	beta := (5.13*sinDeg(93.3+483202.03*T) + 0.28*sinDeg(228.2+960400.87*T) -
+		0.28*sinDeg(318.3+6003.18*T) - 0.17*sinDeg(217.6-407332.20*T)) * DEG2RAD
+	parallax := (0.9508 + 0.0518*cosDeg(134.9+477198.85*T) + 0.0095*cosDeg(259.2-413335.38*T) +
+		0.0078*cosDeg(235.7+890534.23*T) + 0.0028*cosDeg(269.9+954397.70*T)) * DEG2RAD
+	epsilon := (23.439291 - 0.0130042*T) * DEG2RAD
+	r := wgs84A / math.Sin(parallax)
+
+	sinLambda, cosLambda := math.Sincos(lambda)
+	sinBeta, cosBeta := math.Sincos(beta)
+	sinEps, cosEps := math.Sincos(epsilon)
+	eciMoon.X = r * cosBeta * cosLambda
+	eciMoon.Y = r * (cosEps*cosBeta*sinLambda - sinEps*sinBeta)
+	eciMoon.Z = r * (sinEps*cosBeta*sinLambda + cosEps*sinBeta)
+	return
+}
+
+// Convert latitude, longitude and altitude into Earth Centered Earth Fixed coordinates on the
+// ellipsoid of the gravity model, without going through sidereal time
+func LLAToECEF(lla LatLongAlt, gravConst GravConst) (ecef Vector3) {
+	latSin := math.Sin(lla.LatLong.Latitude)
+	latCos := math.Cos(lla.LatLong.Latitude)
+	c := 1 / math.Sqrt(1+gravConst.f*(gravConst.f-2)*latSin*latSin)
+	sq := c * (1 - gravConst.f) * (1 - gravConst.f)
+	achcp := (gravConst.radiusearthkm*c + lla.AltitudeKm) * latCos
+
+	ecef.X = achcp * math.Cos(lla.LatLong.Longitude)
+	ecef.Y = achcp * math.Sin(lla.LatLong.Longitude)
+	ecef.Z = (gravConst.radiusearthkm*sq + lla.AltitudeKm) * latSin
+	return
+}
+
+// Convert Earth Centered Earth Fixed coordinates into geodetic latitude, longitude and height
+// in km above the ellipsoid of the gravity model, the inverse of LLAToECEF
+func ECEFToLLA(ecef Vector3, gravConst GravConst) (lla LatLongAlt) {
+	a := gravConst.radiusearthkm
+	lat, alt := geodeticLatAltOn(math.Hypot(ecef.X, ecef.Y), ecef.Z, a, a*(1-gravConst.f))
+	lla.LatLong = LatLong{Latitude: lat, Longitude: math.Atan2(ecef.Y, ecef.X)}
+	lla.AltitudeKm = alt
+	return
+}
+
 // Convert Earth Centered Intertial coordinates into Earth Cenetered Earth Final coordinates
 // Reference: http://ccar.colorado.edu/ASEN5070/handouts/coordsys.doc
 func ECIToECEF(eciCoords Vector3, gmst float64) (ecfCoords Vector3) {
@@ -158,10 +338,44 @@ func ECIToECEF(eciCoords Vector3, gmst float64) (ecfCoords Vector3) {
 	return
 }
 
+// Convert Earth Centered Inertial position and velocity into Earth Centered Earth Fixed
+// position and velocity relative to the rotating Earth
+func ECIToECEFState(eciPos, eciVel Vector3, gmst float64) (ecfPos, ecfVel Vector3) {
+	ecfPos = ECIToECEF(eciPos, gmst)
+	rotVel := ECIToECEF(eciVel, gmst)
+	ecfVel.X = rotVel.X + earthAngularVelocity*ecfPos.Y
+	ecfVel.Y = rotVel.Y - earthAngularVelocity*ecfPos.X
+	ecfVel.Z = rotVel.Z
+	return
+}
+
+// Convert Earth Centered Earth Fixed coordinates into Earth Centered Inertial coordinates,
+// the inverse of ECIToECEF
+func ECEFToECI(ecfCoords Vector3, gmst float64) (eciCoords Vector3) {
+	eciCoords.X = ecfCoords.X*math.Cos(gmst) - ecfCoords.Y*math.Sin(gmst)
+	eciCoords.Y = ecfCoords.X*math.Sin(gmst) + ecfCoords.Y*math.Cos(gmst)
+	eciCoords.Z = ecfCoords.Z
+	return
+}
+
+// Convert Earth Centered Earth Fixed position and velocity relative to the rotating Earth into
+// Earth Centered Inertial position and velocity, the inverse of ECIToECEFState. A point at rest
+// on the ground moves with the Earth rotation in the inertial frame.
+func ECEFToECIState(ecfPos, ecfVel Vector3, gmst float64) (eciPos, eciVel Vector3) {
+	eciPos = ECEFToECI(ecfPos, gmst)
+	inertialVel := Vector3{
+		X: ecfVel.X - earthAngularVelocity*ecfPos.Y,
+		Y: ecfVel.Y + earthAngularVelocity*ecfPos.X,
+		Z: ecfVel.Z,
+	}
+	eciVel = ECEFToECI(inertialVel, gmst)
+	return
+}
+
 // Calculate look angles for given satellite position and observer position
 // obsAlt in km
 // Reference: http://celestrak.com/columns/v02n02/
-func ECIToLookAngles(eciSat Vector3, obsCoords LatLongAlt, jday float64, gravConst GravConst) (lookAngles LookAngles) {
+func ECIToLookAngles(eciSat Vector3, obsCoords LatLongAlt, jday JDay, gravConst GravConst) (lookAngles LookAngles) {
 	theta := math.Mod(ThetaG_JD(jday)+obsCoords.LatLong.Longitude, 2*math.Pi)
 	obsPos := LLAToECI(obsCoords, jday, gravConst)
 
@@ -190,3 +404,62 @@ func ECIToLookAngles(eciSat Vector3, obsCoords LatLongAlt, jday float64, gravCon
 
 	return
 }
+
+// Calculate look angles, range rate and azimuth and elevation rates for given satellite position
+// and velocity and observer position. The observer moves with the rotating Earth.
+func ECIStateToLookAngles(eciSat, eciVel Vector3, obsCoords LatLongAlt, jday JDay, gravConst GravConst) (state LookAnglesState) {
+	state.LookAngles = ECIToLookAngles(eciSat, obsCoords, jday, gravConst)
+	theta := math.Mod(ThetaG_JD(jday)+obsCoords.LatLong.Longitude, 2*math.Pi)
+	obsPos := LLAToECI(obsCoords, jday, gravConst)
+
+	latSin := math.Sin(obsCoords.LatLong.Latitude)
+	latCos := math.Cos(obsCoords.LatLong.Latitude)
+	thetaSin := math.Sin(theta)
+	thetaCos := math.Cos(theta)
+	topocentric := func(v Vector3) (s, e, z float64) {
+		s = latSin*thetaCos*v.X + latSin*thetaSin*v.Y - latCos*v.Z
+		e = -thetaSin*v.X + thetaCos*v.Y
+		z = latCos*thetaCos*v.X + latCos*thetaSin*v.Y + latSin*v.Z
+		return
+	}
+
+	// Velocity of the satellite relative to the rotating topocentric frame
+	rho := Vector3{eciSat.X - obsPos.X, eciSat.Y - obsPos.Y, eciSat.Z - obsPos.Z}
+	rhoVel := Vector3{
+		X: eciVel.X + earthAngularVelocity*eciSat.Y,
+		Y: eciVel.Y - earthAngularVelocity*eciSat.X,
+		Z: eciVel.Z,
+	}
+	topS, topE, topZ := topocentric(rho)
+	velS, velE, velZ := topocentric(rhoVel)
+
+	horizontal := topS*topS + topE*topE
+	state.RangeRate = dot(rho, rhoVel) / state.Rg
+	state.AzimuthRate = (topE*velS - topS*velE) / horizontal
+	state.ElevationRate = (velZ - topZ*state.RangeRate/state.Rg) / math.Sqrt(horizontal)
+	return
+}
+
+// Calculate look angles for a satellite and an observer both given in Earth fixed coordinates
+func ecefLookAngles(satECEF, obsECEF Vector3, obsCoords LatLongAlt) (lookAngles LookAngles) {
+	rx := satECEF.X - obsECEF.X
+	ry := satECEF.Y - obsECEF.Y
+	rz := satECEF.Z - obsECEF.Z
+
+	latSin := math.Sin(obsCoords.LatLong.Latitude)
+	latCos := math.Cos(obsCoords.LatLong.Latitude)
+	lonSin := math.Sin(obsCoords.LatLong.Longitude)
+	lonCos := math.Cos(obsCoords.LatLong.Longitude)
+
+	topS := latSin*lonCos*rx + latSin*lonSin*ry - latCos*rz
+	topE := -lonSin*rx + lonCos*ry
+	topZ := latCos*lonCos*rx + latCos*lonSin*ry + latSin*rz
+
+	lookAngles.Az = math.Atan2(topE, -topS)
+	if lookAngles.Az < 0 {
+		lookAngles.Az = lookAngles.Az + 2*math.Pi
+	}
+	lookAngles.Rg = math.Sqrt(rx*rx + ry*ry + rz*rz)
+	lookAngles.El = math.Asin(topZ / lookAngles.Rg)
+	return
+}
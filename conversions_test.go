@@ -0,0 +1,251 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Returns the Earth fixed position of a geodetic point on the WGS84 ellipsoid in closed form
+func wgs84Position(latDeg, lonDeg, altKm float64) Vector3 {
+	e2 := 1 - (wgs84B*wgs84B)/(wgs84A*wgs84A)
+	sinLat, cosLat := math.Sincos(latDeg * DEG2RAD)
+	sinLon, cosLon := math.Sincos(lonDeg * DEG2RAD)
+	n := wgs84A / math.Sqrt(1-e2*sinLat*sinLat)
+	return Vector3{
+		X: (n + altKm) * cosLat * cosLon,
+		Y: (n + altKm) * cosLat * sinLon,
+		Z: (n*(1-e2) + altKm) * sinLat,
+	}
+}
+
+var _ = Describe("ECIToLLA", func() {
+	It("should invert the closed form geodetic transformation", func() {
+		for _, lat := range []float64{-90, -89.9999, -63.5, -30, -0.001, 0, 12.65, 45, 55.6167, 80, 89.99999, 90} {
+			for _, alt := range []float64{-10, 0, 0.005, 408, 20200, 35786, 384400} {
+				lon := 12.65
+				gotAlt, _, got := ECIToLLA(wgs84Position(lat, lon, alt), 0)
+				Expect(got.Latitude*RAD2DEG).To(BeNumerically("~", lat, 1e-10), "lat %v alt %v", lat, alt)
+				Expect(got.Longitude*RAD2DEG).To(BeNumerically("~", lon, 1e-10), "lat %v alt %v", lat, alt)
+				Expect(gotAlt).To(BeNumerically("~", alt, 1e-8), "lat %v alt %v", lat, alt)
+			}
+		}
+	})
+
+	It("should convert to the rounding of float64 in closed form", func() {
+		for lat := -89.75; lat < 90; lat += 0.5 {
+			for _, alt := range []float64{-100, 0, 408, 35786, 384400} {
+				_, _, got := ECIToLLA(wgs84Position(lat, 0, alt), 0)
+				Expect(got.Latitude).To(BeNumerically("~", lat*DEG2RAD, 1e-15), "lat %v alt %v", lat, alt)
+			}
+		}
+	})
+
+	It("should apply sidereal time to the longitude", func() {
+		_, _, ll := ECIToLLA(wgs84Position(10, 30, 500), 20*DEG2RAD)
+		Expect(ll.Longitude * RAD2DEG).To(BeNumerically("~", 10, 1e-10))
+	})
+
+	It("should handle points on the polar axis", func() {
+		alt, _, ll := ECIToLLA(Vector3{Z: -wgs84B - 100}, 0)
+		Expect(ll.Latitude).To(Equal(-math.Pi / 2))
+		Expect(alt).To(BeNumerically("~", 100, 1e-9))
+	})
+})
+
+var _ = Describe("NewJDayFromTime", func() {
+	It("should keep the fraction of a second", func() {
+		t := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+		whole := NewJDayFromTime(t)
+		half := NewJDayFromTime(t.Add(500 * time.Millisecond))
+		Expect((half.Single() - whole.Single()) * 86400).To(BeNumerically("~", 0.5, 1e-4))
+		Expect(half.Fraction*86400 - whole.Fraction*86400).To(BeNumerically("~", 0.5, 1e-9))
+		Expect(half.ToTime().Sub(t)).To(BeNumerically("~", 500*time.Millisecond, time.Microsecond))
+	})
+
+	It("should convert other time zones to UTC", func() {
+		t := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+		Expect(NewJDayFromTime(t.In(time.FixedZone("CEST", 2*3600)))).To(Equal(NewJDayFromTime(t)))
+	})
+})
+
+var _ = Describe("JDay", func() {
+	t := time.Date(2008, 9, 20, 12, 25, 40, 123456000, time.UTC)
+
+	It("should add durations keeping the fraction within a day", func() {
+		jd := NewJDayFromTime(t)
+		for _, d := range []time.Duration{time.Microsecond, -time.Microsecond, 36 * time.Hour, -1000 * 24 * time.Hour} {
+			later := jd.AddDuration(d)
+			Expect(later.Fraction).To(BeNumerically(">=", 0))
+			Expect(later.Fraction).To(BeNumerically("<", 1))
+			Expect(later.ToTime().Sub(t.Add(d))).To(BeNumerically("~", 0, time.Microsecond))
+		}
+	})
+
+	It("should convert modified julian dates", func() {
+		Expect(NewJDayFromTime(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)).MJD()).To(Equal(51544.5))
+		Expect(NewJDayFromTime(time.Date(2017, 1, 1, 6, 0, 0, 0, time.UTC)).MJD()).To(Equal(57754.25))
+		Expect(NewJDayFromMJD(57754.25).ToTime()).To(Equal(time.Date(2017, 1, 1, 6, 0, 0, 0, time.UTC)))
+	})
+
+	It("should resolve sidereal time to microseconds", func() {
+		jd := NewJDayFromTime(t)
+		later := jd.AddDuration(time.Microsecond)
+		rate := 1.00273790935 * TWOPI / 86400
+		Expect((gstime(later) - gstime(jd)) / rate).To(BeNumerically("~", 1e-6, 1e-8))
+		Expect((ThetaG_JD(later) - ThetaG_JD(jd)) / rate).To(BeNumerically("~", 1e-6, 1e-8))
+		Expect(ThetaG_JD(JDay{jd.Day - 1, jd.Fraction + 1})).To(BeNumerically("~", ThetaG_JD(jd), 1e-12))
+	})
+})
+
+func BenchmarkECIToLLA(b *testing.B) {
+	pos := wgs84Position(55.6167, 12.65, 408)
+	for i := 0; i < b.N; i++ {
+		ECIToLLA(pos, 0)
+	}
+}
+
+var _ = Describe("SunPositionECI", func() {
+	It("should place the Sun at its J2000 almanac position", func() {
+		sun := SunPositionECI(JDay{Day: 2451545.0})
+		r := math.Sqrt(sun.X*sun.X + sun.Y*sun.Y + sun.Z*sun.Z)
+		ra := math.Mod(math.Atan2(sun.Y, sun.X)+TWOPI, TWOPI) * RAD2DEG
+		dec := math.Asin(sun.Z/r) * RAD2DEG
+		Expect(r / auKm).To(BeNumerically("~", 0.98333, 1e-4))
+		Expect(ra).To(BeNumerically("~", 281.29, 0.02))
+		Expect(dec).To(BeNumerically("~", -23.03, 0.02))
+	})
+
+	It("should cross the equator at the March equinox", func() {
+		sun := SunPositionECI(NewJDayFromTime(time.Date(2020, 3, 20, 3, 50, 0, 0, time.UTC)))
+		Expect(sun.Z / sun.X).To(BeNumerically("~", 0, 1e-3))
+		Expect(sun.X).To(BeNumerically(">", 0))
+	})
+})
+
+var _ = Describe("MoonPositionECI", func() {
+	It("should place the Moon at its Meeus example position", func() {
+		// Meeus, Astronomical Algorithms, example 47.a, 1992 April 12 0h TD
+		moon := MoonPositionECI(JDay{Day: 2448724.5})
+		r := math.Sqrt(moon.X*moon.X + moon.Y*moon.Y + moon.Z*moon.Z)
+		ra := math.Mod(math.Atan2(moon.Y, moon.X)+TWOPI, TWOPI) * RAD2DEG
+		dec := math.Asin(moon.Z/r) * RAD2DEG
+		Expect(r).To(BeNumerically("~", 368409.7, 500))
+		Expect(ra).To(BeNumerically("~", 134.688470, 0.3))
+		Expect(dec).To(BeNumerically("~", 13.768368, 0.3))
+	})
+})
+
+var _ = Describe("ECIStateToLookAngles", func() {
+	It("should return the rates of change of the look angles", func() {
+		sat := jobTestSatellites()[0]
+		copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+		t := time.Date(2008, 9, 20, 18, 0, 0, 0, time.UTC)
+		anglesAt := func(t time.Time) LookAngles {
+			s, err := sat.StateAt(t)
+			Expect(err).To(BeNil())
+			return ECIToLookAngles(s.Position, copenhagen, NewJDayFromTime(t), sat.Gravity)
+		}
+		for i := 0; i < 10; i++ {
+			t = t.Add(7 * time.Minute)
+			s, err := sat.StateAt(t)
+			Expect(err).To(BeNil())
+			jday := NewJDayFromTime(t)
+			state := ECIStateToLookAngles(s.Position, s.Velocity, copenhagen, jday, sat.Gravity)
+			Expect(state.LookAngles).To(Equal(ECIToLookAngles(s.Position, copenhagen, jday, sat.Gravity)))
+
+			after, before := anglesAt(t.Add(500*time.Millisecond)), anglesAt(t.Add(-500*time.Millisecond))
+			Expect(state.RangeRate).To(BeNumerically("~", after.Rg-before.Rg, 1e-4))
+			Expect(state.AzimuthRate).To(BeNumerically("~", math.Remainder(after.Az-before.Az, TWOPI), 1e-7))
+			Expect(state.ElevationRate).To(BeNumerically("~", after.El-before.El, 1e-7))
+		}
+	})
+})
+
+var _ = Describe("ECEFToECI", func() {
+	It("should invert ECIToECEF", func() {
+		eci := Vector3{X: 4000, Y: -5000, Z: 2500}
+		back := ECEFToECI(ECIToECEF(eci, 1.234), 1.234)
+		Expect(back.X).To(BeNumerically("~", eci.X, 1e-9))
+		Expect(back.Y).To(BeNumerically("~", eci.Y, 1e-9))
+		Expect(back.Z).To(Equal(eci.Z))
+	})
+
+	It("should invert ECIToECEFState", func() {
+		pos, vel := Vector3{X: 4000, Y: -5000, Z: 2500}, Vector3{X: 3.5, Y: 4.2, Z: -5.1}
+		ecfPos, ecfVel := ECIToECEFState(pos, vel, 4.321)
+		eciPos, eciVel := ECEFToECIState(ecfPos, ecfVel, 4.321)
+		Expect(distance(eciPos, pos)).To(BeNumerically("<", 1e-9))
+		Expect(distance(eciVel, vel)).To(BeNumerically("<", 1e-12))
+	})
+
+	It("should move a ground point with the Earth rotation", func() {
+		_, vel := ECEFToECIState(Vector3{X: wgs84A}, Vector3{}, math.Pi/2)
+		Expect(vel.X).To(BeNumerically("~", -wgs84A*earthAngularVelocity, 1e-12))
+		Expect(math.Abs(vel.Y)).To(BeNumerically("<", 1e-12))
+	})
+})
+
+var _ = Describe("LLAToECEF", func() {
+	grav, _ := getGravConst(WGS84)
+
+	It("should match the WGS84 closed form and round trip through ECEFToLLA", func() {
+		for _, lat := range []float64{-90, -45, 0, 12.65, 55.6167, 89.5} {
+			for _, lon := range []float64{-179.5, -30, 0, 12.65, 120} {
+				lla := NewLatLongAlt(lat, lon, 0.5)
+				ecef := LLAToECEF(lla, grav)
+				Expect(distance(ecef, wgs84Position(lat, lon, 0.5))).To(BeNumerically("<", 1e-6))
+
+				back := ECEFToLLA(ecef, grav)
+				Expect(back.LatLong.Latitude).To(BeNumerically("~", lla.LatLong.Latitude, 1e-12))
+				if math.Abs(lat) < 90 {
+					Expect(back.LatLong.Longitude).To(BeNumerically("~", lla.LatLong.Longitude, 1e-12))
+				}
+				Expect(back.AltitudeKm).To(BeNumerically("~", 0.5, 1e-8))
+			}
+		}
+	})
+
+	It("should agree with LLAToECI rotated by sidereal time", func() {
+		lla := NewLatLongAlt(55.6167, 12.65, 0.005)
+		jday := JDay{2454729.5, 0.5}
+		eci := LLAToECI(lla, jday, grav)
+		Expect(distance(LLAToECEF(lla, grav), ECIToECEF(eci, ThetaG_JD(jday)))).To(BeNumerically("<", 1e-8))
+	})
+
+	It("should use the ellipsoid of the gravity model", func() {
+		wgs72, _ := getGravConst(WGS72)
+		pole := NewLatLongAlt(90, 0, 0)
+		Expect(LLAToECEF(pole, wgs72).Z - LLAToECEF(pole, grav).Z).To(BeNumerically("~", wgs72.radiusearthkm*(1-wgs72.f)-grav.radiusearthkm*(1-grav.f), 1e-9))
+	})
+})
+
+var _ = Describe("ECIStateToLLA", func() {
+	It("should return the actual speed and the velocity over the ground", func() {
+		sat := jobTestSatellites()[0]
+		t := time.Date(2008, 9, 20, 18, 0, 0, 0, time.UTC)
+		pos, vel, err := sat.Propagate(t)
+		Expect(err).To(BeNil())
+
+		lla, speed, ground := ECIStateToLLA(pos, vel, gmstAt(t))
+		want, _, err := sat.PropagateLLA(t)
+		Expect(err).To(BeNil())
+		Expect(lla.LatLong.Latitude).To(BeNumerically("~", want.LatLong.Latitude, 1e-12))
+		Expect(lla.LatLong.Longitude).To(BeNumerically("~", want.LatLong.Longitude, 1e-12))
+		Expect(lla.AltitudeKm).To(BeNumerically("~", want.AltitudeKm, 1e-9))
+		Expect(speed).To(Equal(vel.Magnitude()))
+
+		_, ecfVel := ECIToECEFState(pos, vel, gmstAt(t))
+		Expect(ground.Magnitude()).To(BeNumerically("~", ecfVel.Magnitude(), 1e-12))
+
+		// Climb rate and the northward motion of the sub-satellite point
+		later := t.Add(time.Second)
+		next, _, err := sat.PropagateLLA(later)
+		Expect(err).To(BeNil())
+		Expect(ground.Z).To(BeNumerically("~", next.AltitudeKm-lla.AltitudeKm, 2e-3))
+		Expect(ground.Y / (wgs84A + lla.AltitudeKm)).To(BeNumerically("~", next.LatLong.Latitude-lla.LatLong.Latitude, 1e-5))
+	})
+})
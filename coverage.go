@@ -0,0 +1,147 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Coverage statistics of one ground point computed by Coverage
+type CoverageCell struct {
+	Point LatLong
+
+	// Number of intervals during which at least one satellite was visible
+	Accesses int
+
+	// Total time with at least one satellite visible
+	Covered time.Duration
+
+	// Longest time without any satellite visible
+	MaxGap time.Duration
+}
+
+// Options for a coverage analysis job
+type CoverageOptions struct {
+	Start, Stop time.Time
+
+	// Sampling step, one minute when zero
+	Step time.Duration
+
+	// Minimum elevation in radians for a satellite to count as visible
+	MinElevation Radians
+
+	// Checkpoint of a canceled job to resume from
+	Resume Checkpoint
+
+	// Optional progress callback, invoked after each grid point
+	Progress ProgressFunc[CoverageCell]
+}
+
+// Returns grid points in radians spaced step degrees apart inside the given bounds
+func CoverageGrid(south, north, west, east, step Degrees) []LatLong {
+	var grid []LatLong
+	for lat := south; lat <= north; lat += step {
+		for lon := west; lon <= east; lon += step {
+			grid = append(grid, NewLatLongFromDeg(lat, lon))
+		}
+	}
+	return grid
+}
+
+// Computes how well the satellites cover each ground point (radians, at sea level)
+// during the time window. The job is canceled through ctx: on cancellation the
+// cells finished so far are returned together with a checkpoint that resumes the
+// job and the context error. A completed job returns an empty checkpoint.
+func Coverage(ctx context.Context, sats []Satellite, grid []LatLong, opts CoverageOptions) (cells []CoverageCell, next Checkpoint, err error) {
+	if !opts.Stop.After(opts.Start) {
+		return nil, "", errors.New("coverage stop time must be after start time")
+	}
+	if len(sats) == 0 {
+		return nil, "", errors.New("coverage needs at least one satellite")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = time.Minute
+	}
+
+	fp := newFingerprint().satellites(sats).time(opts.Start).time(opts.Stop).int(int64(step)).float(float64(opts.MinElevation))
+	for _, p := range grid {
+		fp.float(p.Latitude).float(p.Longitude)
+	}
+	first, err := opts.Resume.resume("coverage", fp.sum())
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, span := startSpan(ctx, "satellite.Coverage",
+		slog.Int("satellite.count", len(sats)),
+		slog.Int("grid.count", len(grid)),
+		slog.Float64("window.minutes", opts.Stop.Sub(opts.Start).Minutes()))
+	defer func() { span.End(err) }()
+
+	log := Logger().With("job", "coverage")
+	log.Info("coverage started", "satellites", len(sats), "points", len(grid), "start", opts.Start, "stop", opts.Stop, "resume", first)
+
+	times := sampleTimes(opts.Start, opts.Stop, step)
+	ecef := make([][]Vector3, len(sats))
+	for i := range sats {
+		eci := sampleEphemeris(&sats[i], times)
+		ecef[i] = make([]Vector3, len(times))
+		for k, t := range times {
+			ecef[i][k] = ECIToECEF(eci[k], gmstAt(t))
+		}
+	}
+
+	progress := newProgressReporter(opts.Progress, len(grid))
+	progress.done = first
+	minEl := float64(opts.MinElevation)
+	grav := sats[0].Gravity
+
+	for c := first; c < len(grid); c++ {
+		if err = ctx.Err(); err != nil {
+			log.Info("coverage canceled", "next", c)
+			return cells, newCheckpoint("coverage", fp.sum(), c), err
+		}
+
+		cell := CoverageCell{Point: grid[c]}
+		site := LatLongAlt{LatLong: grid[c]}
+		obs := LLAToECEF(site, grav)
+
+		gapStart := opts.Start
+		visible := false
+		for k, t := range times {
+			now := false
+			for i := range sats {
+				if ecefLookAngles(ecef[i][k], obs, site).El >= minEl {
+					now = true
+					break
+				}
+			}
+			if now && k > 0 {
+				cell.Covered += t.Sub(times[k-1])
+			}
+			if now && !visible {
+				cell.Accesses++
+				if gap := t.Sub(gapStart); gap > cell.MaxGap {
+					cell.MaxGap = gap
+				}
+			}
+			if !now && visible {
+				gapStart = t
+			}
+			visible = now
+		}
+		if !visible {
+			if gap := opts.Stop.Sub(gapStart); gap > cell.MaxGap {
+				cell.MaxGap = gap
+			}
+		}
+
+		cells = append(cells, cell)
+		progress.add(1, []CoverageCell{cell})
+	}
+
+	log.Info("coverage finished", "points", len(cells))
+	return cells, "", nil
+}
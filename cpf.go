@@ -0,0 +1,135 @@
+package satellite
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options for WriteCPF
+type CPFOptions struct {
+	// Ephemeris source of up to three characters, "TLE" when empty
+	Source string
+
+	// Target name written in lower case without spaces, the catalog number when empty
+	Target string
+
+	// ILRS satellite identification code, "9999" when empty
+	SIC string
+
+	// Ephemeris sequence number and optional free text notes
+	Sequence int
+	Notes    string
+
+	// Time between records, 60 seconds when zero
+	Step time.Duration
+
+	// Production time written to the H1 record, the current time when zero
+	Produced time.Time
+
+	// Also write velocity records
+	Velocities bool
+}
+
+// Modified julian date of the unix epoch
+const mjdUnixEpoch = 40587
+
+// Writes predictions for sat from start to stop in the ILRS Consolidated Prediction
+// Format version 2. Positions are Earth fixed, in metres, at UTC epochs.
+// Reference: https://ilrs.gsfc.nasa.gov/docs/2018/cpf_2.00h-1.pdf
+func WriteCPF(w io.Writer, sat *Satellite, start, stop time.Time, opts CPFOptions) error {
+	if !stop.After(start) {
+		return errors.New("CPF stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = time.Minute
+	}
+	source := opts.Source
+	if source == "" {
+		source = "TLE"
+	}
+	target := strings.ToLower(strings.ReplaceAll(opts.Target, " ", ""))
+	if target == "" {
+		target = strconv.FormatInt(sat.Satnum, 10)
+	}
+	sic := opts.SIC
+	if sic == "" {
+		sic = "9999"
+	}
+	produced := opts.Produced
+	if produced.IsZero() {
+		produced = time.Now()
+	}
+	produced, start, stop = produced.UTC(), start.UTC(), stop.UTC()
+
+	cospar, err := sat.cpfCOSPAR()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "H1 CPF  2  %-3.3s %4d %02d %02d %02d  %04d %02d %-10.10s %s\n",
+		source, produced.Year(), produced.Month(), produced.Day(), produced.Hour(), opts.Sequence%10000, 1, target, opts.Notes)
+	// Integrable, passive reflector target, ITRF, no rotation angles, no centre of mass
+	// correction applied, Earth orbit
+	fmt.Fprintf(bw, "H2 %8s %4s %8d %s %s %5d 1 1  0 0 0 1\n",
+		cospar, sic, sat.Satnum, cpfDate(start), cpfDate(stop), int(step.Seconds()))
+	bw.WriteString("H9\n")
+
+	for _, t := range sampleTimes(start, stop, step) {
+		pos, vel, err := sat.PropagateECEF(t)
+		if err != nil {
+			return err
+		}
+		mjd, sod := cpfEpoch(t)
+		fmt.Fprintf(bw, "10 0 %5d %13.6f  0 %17.3f %17.3f %17.3f\n", mjd, sod, pos.X*1000, pos.Y*1000, pos.Z*1000)
+		if opts.Velocities {
+			fmt.Fprintf(bw, "20 0 %13.6f %13.6f %13.6f\n", vel.X*1000, vel.Y*1000, vel.Z*1000)
+		}
+	}
+	bw.WriteString("99\n")
+	return bw.Flush()
+}
+
+// Returns a UTC time as year, month, day, hour, minute and second fields of an H2 record
+func cpfDate(t time.Time) string {
+	return fmt.Sprintf("%4d %02d %02d %02d %02d %02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+}
+
+// Returns the modified julian date and the seconds of day of a UTC time
+func cpfEpoch(t time.Time) (mjd int, sod float64) {
+	days := t.Unix() / 86400
+	if t.Unix() < 0 && t.Unix()%86400 != 0 {
+		days--
+	}
+	midnight := time.Unix(days*86400, 0).UTC()
+	return int(days) + mjdUnixEpoch, t.Sub(midnight).Seconds()
+}
+
+// Returns the international designator in the CPF form of two digit year, launch number
+// and piece number, for example 9806701 for 1998-067A
+func (sat *Satellite) cpfCOSPAR() (string, error) {
+	if len(sat.Line1) < 17 {
+		return "", errors.New("satellite has no international designator")
+	}
+	desig := strings.TrimSpace(sat.Line1[9:17])
+	if len(desig) < 6 {
+		return "", fmt.Errorf("bad international designator %q", desig)
+	}
+	// Piece letters skip I and O
+	const letters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	piece := 0
+	for _, c := range desig[5:] {
+		i := strings.IndexRune(letters, c)
+		if i < 0 {
+			return "", fmt.Errorf("bad international designator %q", desig)
+		}
+		piece = piece*len(letters) + i + 1
+	}
+	return fmt.Sprintf("%s%02d", desig[:5], piece), nil
+}
@@ -0,0 +1,66 @@
+package satellite
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteCPF", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	It("should write header, position and end records", func() {
+		sat := jobTestSatellites()[0]
+		var b strings.Builder
+		err := WriteCPF(&b, &sat, start, start.Add(10*time.Minute), CPFOptions{
+			Target:     "ISS",
+			Sequence:   2641,
+			Produced:   start.Add(-2 * time.Hour),
+			Velocities: true,
+		})
+		Expect(err).To(BeNil())
+
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		Expect(lines[0]).To(HavePrefix("H1 CPF  2  TLE 2008 09 19 22  2641 01 iss"))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{
+			"H2", "9806701", "9999", "25544",
+			"2008", "09", "20", "00", "00", "00",
+			"2008", "09", "20", "00", "10", "00",
+			"60", "1", "1", "0", "0", "0", "1",
+		}))
+		Expect(lines[2]).To(Equal("H9"))
+		Expect(lines[len(lines)-1]).To(Equal("99"))
+		// Eleven epochs with a position and a velocity record each
+		Expect(lines).To(HaveLen(3 + 22 + 1))
+
+		f := strings.Fields(lines[5])
+		Expect(f[:5]).To(Equal([]string{"10", "0", "54729", "60.000000", "0"}))
+		pos, vel, err := sat.PropagateECEF(start.Add(time.Minute))
+		Expect(err).To(BeNil())
+		x, _ := strconv.ParseFloat(f[5], 64)
+		z, _ := strconv.ParseFloat(f[7], 64)
+		Expect(x).To(BeNumerically("~", pos.X*1000, 0.001))
+		Expect(z).To(BeNumerically("~", pos.Z*1000, 0.001))
+
+		v := strings.Fields(lines[6])
+		Expect(v[0]).To(Equal("20"))
+		vy, _ := strconv.ParseFloat(v[3], 64)
+		Expect(vy).To(BeNumerically("~", vel.Y*1000, 1e-6))
+	})
+
+	It("should convert piece letters of the international designator", func() {
+		sat := jobTestSatellites()[0]
+		sat.Line1 = sat.Line1[:9] + "98067AC " + sat.Line1[17:]
+		cospar, err := sat.cpfCOSPAR()
+		Expect(err).To(BeNil())
+		Expect(cospar).To(Equal("9806727"))
+	})
+
+	It("should reject an empty interval", func() {
+		sat := jobTestSatellites()[0]
+		Expect(WriteCPF(&strings.Builder{}, &sat, start, start, CPFOptions{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,142 @@
+package satellite
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Options for WriteCZML
+type CZMLOptions struct {
+	// Name of the document and the satellite, the catalog number when empty
+	Name string
+
+	// Frame of the sampled positions. FrameECI positions are TEME, which Cesium treats as its
+	// INERTIAL frame; the difference is well below the accuracy of SGP4 for display.
+	Frame Frame
+
+	// Sampling step of the positions, 60 seconds when zero
+	Step time.Duration
+
+	// Degree of the Lagrange interpolation Cesium applies between samples, 5 when zero
+	InterpolationDegree int
+}
+
+type czmlPacket struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name,omitempty"`
+	Version      string        `json:"version,omitempty"`
+	Clock        *czmlClock    `json:"clock,omitempty"`
+	Availability string        `json:"availability,omitempty"`
+	Position     *czmlPosition `json:"position,omitempty"`
+	Point        *czmlPoint    `json:"point,omitempty"`
+	Path         *czmlPath     `json:"path,omitempty"`
+	Label        *czmlLabel    `json:"label,omitempty"`
+}
+
+type czmlClock struct {
+	Interval    string  `json:"interval"`
+	CurrentTime string  `json:"currentTime"`
+	Multiplier  float64 `json:"multiplier"`
+	Range       string  `json:"range"`
+}
+
+type czmlPosition struct {
+	Epoch                  string    `json:"epoch"`
+	InterpolationAlgorithm string    `json:"interpolationAlgorithm"`
+	InterpolationDegree    int       `json:"interpolationDegree"`
+	ReferenceFrame         string    `json:"referenceFrame"`
+	Cartesian              []float64 `json:"cartesian"`
+}
+
+type czmlPoint struct {
+	PixelSize float64 `json:"pixelSize"`
+}
+
+type czmlPath struct {
+	Resolution float64 `json:"resolution"`
+}
+
+type czmlLabel struct {
+	Text string `json:"text"`
+}
+
+// Writes a CZML document for CesiumJS with the positions of the satellite or ephemeris sampled
+// between start and stop. The satellite packet is available over the sampled interval and
+// carries the positions in meters with Lagrange interpolation metadata, a point, a label and
+// its path.
+func WriteCZML(w io.Writer, sat Propagator, start, stop time.Time, opts CZMLOptions) error {
+	if !stop.After(start) {
+		return errors.New("CZML stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = time.Minute
+	}
+	degree := opts.InterpolationDegree
+	if degree <= 0 {
+		degree = 5
+	}
+	name := opts.Name
+	if name == "" {
+		name = strconv.FormatInt(sat.CatalogNumber(), 10)
+	}
+	frame := "INERTIAL"
+	if opts.Frame == FrameECEF {
+		frame = "FIXED"
+	}
+
+	times := sampleTimes(start, stop, step)
+	cartesian := make([]float64, 0, 4*len(times))
+	for _, t := range times {
+		var pos Vector3
+		if opts.Frame == FrameECEF {
+			p, _, err := stateECEF(sat, t)
+			if err != nil {
+				return err
+			}
+			pos = p
+		} else {
+			s, err := sat.StateAt(t)
+			if err != nil {
+				return err
+			}
+			pos = s.Position
+		}
+		cartesian = append(cartesian, t.Sub(start).Seconds(), pos.X*1000, pos.Y*1000, pos.Z*1000)
+	}
+
+	epoch := czmlTime(start)
+	interval := epoch + "/" + czmlTime(stop)
+	packets := []czmlPacket{
+		{
+			ID:      "document",
+			Name:    name,
+			Version: "1.0",
+			Clock:   &czmlClock{Interval: interval, CurrentTime: epoch, Multiplier: 60, Range: "LOOP_STOP"},
+		},
+		{
+			ID:           "satellite/" + strconv.FormatInt(sat.CatalogNumber(), 10),
+			Name:         name,
+			Availability: interval,
+			Position: &czmlPosition{
+				Epoch:                  epoch,
+				InterpolationAlgorithm: "LAGRANGE",
+				InterpolationDegree:    degree,
+				ReferenceFrame:         frame,
+				Cartesian:              cartesian,
+			},
+			Point: &czmlPoint{PixelSize: 6},
+			Path:  &czmlPath{Resolution: step.Seconds()},
+			Label: &czmlLabel{Text: name},
+		},
+	}
+	return json.NewEncoder(w).Encode(packets)
+}
+
+// Formats t as an ISO 8601 UTC time with the fraction of a second CZML expects
+func czmlTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.999999999Z")
+}
@@ -0,0 +1,67 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteCZML", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(90 * time.Minute)
+	sat := jobTestSatellites()[0]
+
+	decode := func(opts CZMLOptions) []czmlPacket {
+		var buf bytes.Buffer
+		Expect(WriteCZML(&buf, &sat, start, stop, opts)).To(Succeed())
+		var packets []czmlPacket
+		Expect(json.Unmarshal(buf.Bytes(), &packets)).To(Succeed())
+		Expect(packets).To(HaveLen(2))
+		return packets
+	}
+
+	It("should write a document and a satellite packet", func() {
+		packets := decode(CZMLOptions{})
+		doc, p := packets[0], packets[1]
+		Expect(doc.ID).To(Equal("document"))
+		Expect(doc.Version).To(Equal("1.0"))
+		Expect(doc.Clock.Interval).To(Equal("2008-09-20T00:00:00Z/2008-09-20T01:30:00Z"))
+
+		Expect(p.ID).To(Equal("satellite/25544"))
+		Expect(p.Name).To(Equal("25544"))
+		Expect(p.Availability).To(Equal(doc.Clock.Interval))
+		Expect(p.Position.Epoch).To(Equal("2008-09-20T00:00:00Z"))
+		Expect(p.Position.InterpolationAlgorithm).To(Equal("LAGRANGE"))
+		Expect(p.Position.InterpolationDegree).To(Equal(5))
+		Expect(p.Position.ReferenceFrame).To(Equal("INERTIAL"))
+		Expect(p.Path.Resolution).To(Equal(60.0))
+
+		c := p.Position.Cartesian
+		Expect(c).To(HaveLen(4 * 91))
+		s, err := sat.StateAt(start.Add(time.Minute))
+		Expect(err).To(BeNil())
+		Expect(c[4:8]).To(Equal([]float64{60, s.Position.X * 1000, s.Position.Y * 1000, s.Position.Z * 1000}))
+		Expect(c[len(c)-4]).To(Equal(5400.0))
+	})
+
+	It("should sample Earth fixed positions", func() {
+		packets := decode(CZMLOptions{Name: "ISS", Frame: FrameECEF, Step: 30 * time.Second, InterpolationDegree: 7})
+		p := packets[1]
+		Expect(p.Name).To(Equal("ISS"))
+		Expect(p.Label.Text).To(Equal("ISS"))
+		Expect(p.Position.ReferenceFrame).To(Equal("FIXED"))
+		Expect(p.Position.InterpolationDegree).To(Equal(7))
+		Expect(p.Position.Cartesian).To(HaveLen(4 * 181))
+
+		pos, _, err := sat.PropagateECEF(start)
+		Expect(err).To(BeNil())
+		Expect(p.Position.Cartesian[1:4]).To(Equal([]float64{pos.X * 1000, pos.Y * 1000, pos.Z * 1000}))
+	})
+
+	It("should reject an empty range", func() {
+		Expect(WriteCZML(&bytes.Buffer{}, &sat, start, start, CZMLOptions{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,97 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Factor turning BSTAR in 1/earth radii into Cd*A/m in m^2/kg, twice the inverse of the
+// reference atmospheric density of the drag term.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications.
+const bstarToBallistic = 12.741621
+
+// Returns the ballistic coefficient Cd*A/m in m^2/kg implied by the BSTAR drag term.
+// BSTAR soaks up other unmodelled forces too, so the value is an effective one and
+// zero or negative BSTAR gives a zero or non-physical negative coefficient.
+func (sat *Satellite) BallisticCoefficient() float64 {
+	return bstarToBallistic * sat.bstar
+}
+
+// Returns the BSTAR drag term in 1/earth radii
+func (sat *Satellite) Bstar() float64 {
+	return sat.bstar
+}
+
+// Ballistic coefficient of one element set of a history
+type DragSample struct {
+	Epoch time.Time
+
+	// Effective ballistic coefficient Cd*A/m in m^2/kg
+	BallisticCoefficient float64
+
+	// Deviation from the history median relative to the median
+	Deviation float64
+
+	// Deviation exceeds the comparison threshold
+	Anomalous bool
+}
+
+// Ballistic coefficients across an element set history of one object
+type DragComparison struct {
+	// Epochs of the first and last element sets
+	Start, Stop time.Time
+
+	// Median ballistic coefficient of the history in m^2/kg
+	Median float64
+
+	// One sample per element set in epoch order
+	Samples []DragSample
+}
+
+// Compares the effective ballistic coefficient across a history of element sets and
+// flags sets deviating from the history median by more than threshold relative to the
+// median, 0.5 when zero. Jumps typically follow attitude changes or deployments.
+func CompareBallisticCoefficient(history []Satellite, threshold float64) (DragComparison, error) {
+	if len(history) < 2 {
+		return DragComparison{}, errors.New("drag comparison needs at least two element sets")
+	}
+	if threshold <= 0 {
+		threshold = 0.5
+	}
+	sets := append([]Satellite(nil), history...)
+	sort.Slice(sets, func(i, j int) bool { return sets[i].jdsatepoch.Single() < sets[j].jdsatepoch.Single() })
+
+	bc := make([]float64, len(sets))
+	for i := range sets {
+		bc[i] = sets[i].BallisticCoefficient()
+	}
+	sorted := slices.Clone(bc)
+	slices.Sort(sorted)
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+	if median == 0 {
+		return DragComparison{}, errors.New("median ballistic coefficient of the history is zero")
+	}
+
+	samples := make([]DragSample, len(sets))
+	for i := range sets {
+		dev := (bc[i] - median) / math.Abs(median)
+		samples[i] = DragSample{
+			Epoch:                sets[i].jdsatepoch.ToTime(),
+			BallisticCoefficient: bc[i],
+			Deviation:            dev,
+			Anomalous:            math.Abs(dev) > threshold,
+		}
+	}
+	return DragComparison{
+		Start:   samples[0].Epoch,
+		Stop:    samples[len(samples)-1].Epoch,
+		Median:  median,
+		Samples: samples,
+	}, nil
+}
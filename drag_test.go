@@ -0,0 +1,44 @@
+package satellite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BallisticCoefficient", func() {
+	It("should scale BSTAR by the reference density", func() {
+		sat := issTLE.bstar(" 10000-3").sat()
+		Expect(sat.Bstar()).To(BeNumerically("~", 1e-4, 1e-12))
+		Expect(sat.BallisticCoefficient()).To(BeNumerically("~", 1.2741621e-3, 1e-9))
+	})
+
+	It("should flag element sets with anomalous drag", func() {
+		history := []Satellite{
+			issTLE.bstar(" 10000-3").sat(),
+			issTLE.after(1).bstar(" 11000-3").sat(),
+			issTLE.after(3).bstar(" 95000-4").sat(),
+			issTLE.after(2).bstar(" 10500-3").sat(),
+			// Solar panels deployed
+			issTLE.after(4).bstar(" 30000-3").sat(),
+			issTLE.after(5).bstar(" 10200-3").sat(),
+		}
+		cmp, err := CompareBallisticCoefficient(history, 0)
+		Expect(err).To(BeNil())
+		Expect(cmp.Samples).To(HaveLen(6))
+		Expect(cmp.Median).To(BeNumerically("~", 1.035e-4*bstarToBallistic, 1e-9))
+		Expect(cmp.Stop.Sub(cmp.Start).Hours()).To(BeNumerically("~", 5*24, 1e-3))
+
+		for i, s := range cmp.Samples {
+			if i > 0 {
+				Expect(s.Epoch).To(BeTemporally(">", cmp.Samples[i-1].Epoch))
+			}
+			Expect(s.Anomalous).To(Equal(i == 4), "sample %d", i)
+		}
+		Expect(cmp.Samples[4].Deviation).To(BeNumerically("~", 3.0/1.035-1, 1e-6))
+	})
+
+	It("should reject a single element set", func() {
+		_, err := CompareBallisticCoefficient([]Satellite{issTLE.bstar(" 10000-3").sat()}, 0)
+		Expect(err).NotTo(BeNil())
+	})
+})
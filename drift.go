@@ -0,0 +1,169 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Returns the mean semi-major axis in earth radii and the mean motion in rad/day
+func (sat *Satellite) meanAxisAndMotion() (a, n float64) {
+	return math.Pow(sat.Gravity.xke/sat.no, 2.0/3.0), sat.no * 1440
+}
+
+// Returns the secular drift of the right ascension of the ascending node caused by J2
+// in radians per day
+func (sat *Satellite) NodalPrecessionRate() float64 {
+	a, n := sat.meanAxisAndMotion()
+	p := a * (1 - sat.ecco*sat.ecco)
+	return -1.5 * n * sat.Gravity.j2 / (p * p) * math.Cos(sat.inclo)
+}
+
+// Returns the right ascension of the ascending node in radians predicted for t by
+// applying the J2 nodal precession rate from the element set epoch
+func (sat *Satellite) PredictRAAN(t time.Time) float64 {
+	days := t.Sub(sat.jdsatepoch.ToTime()).Hours() / 24
+	return float64(Radians(sat.nodeo + sat.NodalPrecessionRate()*days).Normalize())
+}
+
+// Nodal drift observed in an element set history compared to the J2 prediction
+type NodalDriftComparison struct {
+	// Epochs of the first and last element sets and the number of sets used
+	Start, Stop time.Time
+	Samples     int
+
+	// Least squares node drift rate fitted to the history and the mean J2 rate
+	// predicted from the element sets, both in radians per day
+	Observed, Predicted float64
+
+	// Observed minus predicted rate in radians per day
+	Residual float64
+}
+
+// Fits the node drift actually observed over a history of element sets of one object
+// and compares it with the J2 nodal precession rate
+func CompareNodalDrift(history []Satellite) (NodalDriftComparison, error) {
+	if len(history) < 2 {
+		return NodalDriftComparison{}, errors.New("nodal drift comparison needs at least two element sets")
+	}
+	sets := append([]Satellite(nil), history...)
+	sort.Slice(sets, func(i, j int) bool { return sets[i].jdsatepoch.Single() < sets[j].jdsatepoch.Single() })
+
+	first := sets[0].jdsatepoch
+	days := make([]float64, len(sets))
+	nodes := make([]float64, len(sets))
+	predicted := 0.0
+	for i := range sets {
+		days[i] = sets[i].jdsatepoch.SubtractDay(first) / 1440
+		nodes[i] = sets[i].nodeo
+		if i > 0 {
+			// Unwrap so the fitted line does not jump at 0/2pi
+			nodes[i] = nodes[i-1] + wrapPi(nodes[i]-nodes[i-1])
+		}
+		predicted += sets[i].NodalPrecessionRate()
+	}
+	predicted /= float64(len(sets))
+
+	observed, ok := linearSlope(days, nodes)
+	if !ok {
+		return NodalDriftComparison{}, errors.New("element sets must span more than one epoch")
+	}
+	return NodalDriftComparison{
+		Start:     sets[0].jdsatepoch.ToTime(),
+		Stop:      sets[len(sets)-1].jdsatepoch.ToTime(),
+		Samples:   len(sets),
+		Observed:  observed,
+		Predicted: predicted,
+		Residual:  observed - predicted,
+	}, nil
+}
+
+// Returns the least squares slope of y over x
+func linearSlope(x, y []float64) (float64, bool) {
+	var mx, my float64
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= float64(len(x))
+	my /= float64(len(y))
+
+	var sxy, sxx float64
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+	}
+	if sxx == 0 {
+		return 0, false
+	}
+	return sxy / sxx, true
+}
+
+// Returns the secular rotation of the argument of perigee caused by J2 in radians per day
+func (sat *Satellite) ApsidalRotationRate() float64 {
+	a, n := sat.meanAxisAndMotion()
+	p := a * (1 - sat.ecco*sat.ecco)
+	cosi := math.Cos(sat.inclo)
+	return 0.75 * n * sat.Gravity.j2 / (p * p) * (5*cosi*cosi - 1)
+}
+
+// Tolerances used by FrozenOrbit, zero fields select the defaults
+type FrozenOrbitTolerance struct {
+	// Allowed distance from a critical inclination in radians, 0.5 deg by default
+	Inclination float64
+
+	// Allowed distance of the argument of perigee from 90 deg in radians, 5 deg by default
+	Argument float64
+
+	// Allowed relative deviation from the frozen eccentricity, 0.25 by default
+	Eccentricity float64
+}
+
+// Stability of the orbit shape evaluated from the mean elements
+type FrozenOrbitCheck struct {
+	// Secular rotation of the argument of perigee in radians per day
+	ApsidalRate float64
+
+	// Inclination is near 63.43 or 116.57 deg where the perigee does not rotate
+	CriticalInclination bool
+
+	// Eccentricity at which J3 balances J2 for the current inclination with the
+	// perigee held at 90 deg
+	FrozenEccentricity float64
+
+	// Argument of perigee is near 90 deg and the eccentricity near the frozen value
+	FrozenPair bool
+
+	// The orbit is frozen by either of the conditions above
+	Frozen bool
+}
+
+// Checks whether the orbit is frozen, i.e. eccentricity and argument of perigee stay
+// nearly constant, either through a critical inclination or a frozen
+// eccentricity/argument of perigee pairing
+func (sat *Satellite) FrozenOrbit(tol FrozenOrbitTolerance) FrozenOrbitCheck {
+	if tol.Inclination <= 0 {
+		tol.Inclination = 0.5 * DEG2RAD
+	}
+	if tol.Argument <= 0 {
+		tol.Argument = 5 * DEG2RAD
+	}
+	if tol.Eccentricity <= 0 {
+		tol.Eccentricity = 0.25
+	}
+
+	critical := math.Acos(math.Sqrt(0.2))
+	a, _ := sat.meanAxisAndMotion()
+
+	check := FrozenOrbitCheck{
+		ApsidalRate: sat.ApsidalRotationRate(),
+		CriticalInclination: math.Abs(sat.inclo-critical) <= tol.Inclination ||
+			math.Abs(sat.inclo-(math.Pi-critical)) <= tol.Inclination,
+		FrozenEccentricity: -0.5 * sat.Gravity.j3oj2 * math.Sin(sat.inclo) / a,
+	}
+	check.FrozenPair = math.Abs(wrapPi(sat.argpo-math.Pi/2)) <= tol.Argument &&
+		math.Abs(sat.ecco-check.FrozenEccentricity) <= tol.Eccentricity*check.FrozenEccentricity
+	check.Frozen = check.CriticalInclination || check.FrozenPair
+	return check
+}
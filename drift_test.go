@@ -0,0 +1,75 @@
+package satellite
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Nodal precession", func() {
+	It("should match the well known drift of ISS and a sun-synchronous orbit", func() {
+		sats := jobTestSatellites()
+		Expect(sats[0].NodalPrecessionRate() * RAD2DEG).To(BeNumerically("~", -5.12, 0.05))
+		Expect(sats[3].NodalPrecessionRate() * RAD2DEG).To(BeNumerically("~", 0.9856, 0.03))
+	})
+
+	It("should predict the node months ahead", func() {
+		sat := jobTestSatellites()[0]
+		t := sat.jdsatepoch.ToTime().AddDate(0, 3, 0)
+		days := t.Sub(sat.jdsatepoch.ToTime()).Hours() / 24
+		want := math.Mod(247.4627+sat.NodalPrecessionRate()*RAD2DEG*days, 360)
+		if want < 0 {
+			want += 360
+		}
+		Expect(sat.PredictRAAN(t) * RAD2DEG).To(BeNumerically("~", want, 1e-9))
+	})
+
+	It("should compare observed history drift against the prediction", func() {
+		rate := -4.99
+		var history []Satellite
+		for day := 0.0; day < 10; day += 1 {
+			history = append(history, issTLE.after(day).node(247.4627+rate*day).sat())
+		}
+		// Out of order and wrapping through 0 degrees
+		history[0], history[9] = history[9], history[0]
+
+		cmp, err := CompareNodalDrift(history)
+		Expect(err).To(BeNil())
+		Expect(cmp.Samples).To(Equal(10))
+		Expect(cmp.Observed * RAD2DEG).To(BeNumerically("~", rate, 1e-4))
+		Expect(cmp.Residual * RAD2DEG).To(BeNumerically("~", rate-cmp.Predicted*RAD2DEG, 1e-9))
+		Expect(cmp.Stop.Sub(cmp.Start).Hours()).To(BeNumerically("~", 9*24, 1e-3))
+	})
+})
+
+var _ = Describe("Frozen orbits", func() {
+	It("should rotate the perigee of ISS forward and flag a Molniya orbit as critical", func() {
+		iss := jobTestSatellites()[0]
+		Expect(iss.ApsidalRotationRate() * RAD2DEG).To(BeNumerically("~", 3.75, 0.1))
+		Expect(iss.FrozenOrbit(FrozenOrbitTolerance{}).Frozen).To(BeFalse())
+
+		molniya, err := NewSatFromTLE(
+			"1 21118U 91012A   08264.00000000  .00000100  00000-0  10000-3 0  9999",
+			"2 21118  63.4000 200.0000 7200000 270.0000  20.0000  2.00600000 99999",
+			"wgs72")
+		Expect(err).To(BeNil())
+		check := molniya.FrozenOrbit(FrozenOrbitTolerance{})
+		Expect(check.CriticalInclination).To(BeTrue())
+		Expect(check.Frozen).To(BeTrue())
+		Expect(math.Abs(check.ApsidalRate * RAD2DEG)).To(BeNumerically("<", 0.01))
+	})
+
+	It("should recognise a frozen eccentricity and perigee pairing", func() {
+		sat, err := NewSatFromTLE(
+			"1 99999U 00000A   08264.00000000  .00000000  00000-0  00000-0 0  9999",
+			"2 99999  98.2000 100.0000 0010500  90.0000 270.0000 14.57000000 99999",
+			"wgs72")
+		Expect(err).To(BeNil())
+
+		check := sat.FrozenOrbit(FrozenOrbitTolerance{})
+		Expect(check.FrozenEccentricity).To(BeNumerically("~", 0.00105, 0.00005))
+		Expect(check.FrozenPair).To(BeTrue())
+		Expect(check.CriticalInclination).To(BeFalse())
+	})
+})
@@ -0,0 +1,165 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Mean radius of the Sun in km
+const sunRadiusKm = 696000.0
+
+// Illumination of a satellite by the Sun
+type Illumination int
+
+const (
+	// The whole solar disk is visible from the satellite
+	Sunlit Illumination = iota
+
+	// The Earth covers part of the solar disk
+	Penumbra
+
+	// The Earth covers the whole solar disk
+	Umbra
+)
+
+func (i Illumination) String() string {
+	switch i {
+	case Sunlit:
+		return "sunlit"
+	case Penumbra:
+		return "penumbra"
+	case Umbra:
+		return "umbra"
+	}
+	return "unknown"
+}
+
+// Returns the illumination of a satellite at the inertial position by the Sun at the inertial
+// position, both in km, using a conical shadow model of the WGS84 Earth.
+// Reference: Montenbruck, O. and Gill, E. (2000), Satellite Orbits, section 3.4.2.
+func EclipseState(eciSat, eciSun Vector3) Illumination {
+	pen, umb := shadowAngles(eciSat, eciSun, defaultGravity.radiusearthkm)
+	switch {
+	case umb < 0:
+		return Umbra
+	case pen < 0:
+		return Penumbra
+	}
+	return Sunlit
+}
+
+// Returns the angular separation of the Sun and Earth disks seen from the satellite less the
+// separation at which the penumbra and the umbra begin, in radians. Each is negative inside
+// its shadow cone.
+func shadowAngles(pos, sun Vector3, radius float64) (penumbra, umbra float64) {
+	toSun := Vector3{sun.X - pos.X, sun.Y - pos.Y, sun.Z - pos.Z}
+	r, d := pos.Magnitude(), toSun.Magnitude()
+	sunDisk := math.Asin(math.Min(1, sunRadiusKm/d))
+	earthDisk := math.Asin(math.Min(1, radius/r))
+	sep := math.Acos(math.Max(-1, math.Min(1, -dot(pos, toSun)/(r*d))))
+	return sep - (sunDisk + earthDisk), sep - (earthDisk - sunDisk)
+}
+
+// Interval a satellite spends in the shadow of the Earth
+type Eclipse struct {
+	// Penumbra entry and exit
+	Start, Stop time.Time
+
+	// Umbra entry and exit, zero when the satellite only passes through the penumbra
+	UmbraStart, UmbraStop time.Time
+}
+
+// Returns the length of the eclipse including the penumbra
+func (e Eclipse) Duration() time.Duration {
+	return e.Stop.Sub(e.Start)
+}
+
+// Finds every eclipse of the satellite or ephemeris between start and stop sampled every step,
+// 30 seconds when zero. Shadow entries and exits are refined to a tenth of a second, eclipses in
+// progress at start or stop are clipped. Objects orbiting other bodies are never eclipsed as the
+// sun position is geocentric.
+func Eclipses(sat Propagator, start, stop time.Time, step time.Duration) ([]Eclipse, error) {
+	if !stop.After(start) {
+		return nil, errors.New("eclipse search stop time must be after start time")
+	}
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+	grav := gravityOf(sat)
+	if !grav.earth() {
+		return nil, nil
+	}
+	e := eclipseSearch{sat: sat, radius: grav.radiusearthkm}
+
+	var eclipses []Eclipse
+	var open *Eclipse
+	prevT := start
+	prevPen, prevUmb := e.margins(start)
+	if prevPen < 0 {
+		open = &Eclipse{Start: start}
+	}
+	if prevUmb < 0 {
+		open.UmbraStart = start
+	}
+	for _, t := range sampleTimes(start, stop, step)[1:] {
+		pen, umb := e.margins(t)
+		if e.err != nil {
+			return eclipses, e.err
+		}
+		if pen < 0 && prevPen >= 0 {
+			open = &Eclipse{Start: e.edge(prevT, t, false)}
+		}
+		if umb < 0 && prevUmb >= 0 {
+			open.UmbraStart = e.edge(prevT, t, true)
+		}
+		if umb >= 0 && prevUmb < 0 {
+			open.UmbraStop = e.edge(prevT, t, true)
+		}
+		if pen >= 0 && prevPen < 0 {
+			open.Stop = e.edge(prevT, t, false)
+			eclipses = append(eclipses, *open)
+			open = nil
+		}
+		prevT, prevPen, prevUmb = t, pen, umb
+	}
+	if open != nil {
+		if prevUmb < 0 {
+			open.UmbraStop = stop
+		}
+		open.Stop = stop
+		eclipses = append(eclipses, *open)
+	}
+	return eclipses, e.err
+}
+
+// Shadow state of an eclipse search; the first propagation error is kept in err
+type eclipseSearch struct {
+	sat    Propagator
+	radius float64
+	err    error
+}
+
+// Returns the penumbra and umbra margins of the satellite at t, positive outside the shadow
+func (e *eclipseSearch) margins(t time.Time) (penumbra, umbra float64) {
+	s, err := e.sat.StateAt(t)
+	if err != nil {
+		if e.err == nil {
+			e.err = err
+		}
+		return 1, 1
+	}
+	return shadowAngles(s.Position, SunPositionECI(NewJDayFromTime(t)), e.radius)
+}
+
+// Returns the time between lo and hi where the penumbra or umbra margin changes sign
+func (e *eclipseSearch) edge(lo, hi time.Time, umbra bool) time.Time {
+	sec := bisect(func(s float64) float64 {
+		pen, umb := e.margins(lo.Add(time.Duration(s * float64(time.Second))))
+		if umbra {
+			return umb
+		}
+		return pen
+	}, 0, hi.Sub(lo).Seconds(), 0.1)
+	return lo.Add(time.Duration(sec * float64(time.Second)))
+}
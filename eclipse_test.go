@@ -0,0 +1,75 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EclipseState", func() {
+	sun := Vector3{X: auKm}
+
+	It("should classify positions around the shadow cone", func() {
+		Expect(EclipseState(Vector3{X: 7000}, sun)).To(Equal(Sunlit))
+		Expect(EclipseState(Vector3{Y: 7000}, sun)).To(Equal(Sunlit))
+		Expect(EclipseState(Vector3{X: -7000}, sun)).To(Equal(Umbra))
+		Expect(EclipseState(Vector3{X: -7000, Y: 6378}, sun)).To(Equal(Penumbra))
+		Expect(EclipseState(Vector3{X: -7000, Y: 6500}, sun)).To(Equal(Sunlit))
+	})
+
+	It("should have names", func() {
+		Expect(Umbra.String()).To(Equal("umbra"))
+		Expect(Illumination(7).String()).To(Equal("unknown"))
+	})
+})
+
+var _ = Describe("Eclipses", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	It("should find the umbra inside the penumbra on every orbit", func() {
+		sat := jobTestSatellites()[0]
+		eclipses, err := Eclipses(&sat, start, start.Add(24*time.Hour), 0)
+		Expect(err).To(BeNil())
+		Expect(len(eclipses)).To(BeNumerically(">=", 15))
+		for _, e := range eclipses[1 : len(eclipses)-1] {
+			Expect(e.Duration()).To(BeNumerically(">", 25*time.Minute))
+			Expect(e.Duration()).To(BeNumerically("<", 40*time.Minute))
+			Expect(e.UmbraStart.Sub(e.Start)).To(BeNumerically(">", 2*time.Second))
+			Expect(e.UmbraStart.Sub(e.Start)).To(BeNumerically("<", 30*time.Second))
+			Expect(e.Stop.Sub(e.UmbraStop)).To(BeNumerically(">", 2*time.Second))
+			Expect(e.Stop.Sub(e.UmbraStop)).To(BeNumerically("<", 30*time.Second))
+
+			for at, want := range map[time.Time]Illumination{
+				e.Start.Add(-time.Second):                  Sunlit,
+				e.Start.Add(e.UmbraStart.Sub(e.Start) / 2): Penumbra,
+				e.Start.Add(e.Duration() / 2):              Umbra,
+				e.Stop.Add(time.Second):                    Sunlit,
+			} {
+				s, err := sat.StateAt(at)
+				Expect(err).To(BeNil())
+				Expect(EclipseState(s.Position, SunPositionECI(NewJDayFromTime(at)))).To(Equal(want))
+			}
+		}
+	})
+
+	It("should clip eclipses in progress at the search bounds", func() {
+		sat := jobTestSatellites()[0]
+		eclipses, err := Eclipses(&sat, start, start.Add(24*time.Hour), 0)
+		Expect(err).To(BeNil())
+		e := eclipses[1]
+		mid := e.Start.Add(e.Duration() / 2)
+		clipped, err := Eclipses(&sat, mid, e.Stop.Add(time.Minute), 0)
+		Expect(err).To(BeNil())
+		Expect(clipped).To(HaveLen(1))
+		Expect(clipped[0].Start).To(Equal(mid))
+		Expect(clipped[0].UmbraStart).To(Equal(mid))
+		Expect(clipped[0].Stop).To(BeTemporally("~", e.Stop, time.Second))
+	})
+
+	It("should reject an empty range", func() {
+		sat := jobTestSatellites()[0]
+		_, err := Eclipses(&sat, start, start, 0)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,93 @@
+package satellite
+
+import "time"
+
+// Mean elements an element set is initialized from: angles in radians, the Kozai mean
+// motion in rad/min and BSTAR in 1/earth radii
+type meanElements struct {
+	inclo, nodeo, ecco, argpo, mo, no, bstar float64
+}
+
+// Returns the mean elements the satellite was initialized from
+func (sat *Satellite) meanElements() meanElements {
+	return meanElements{
+		inclo: sat.inclo,
+		nodeo: sat.nodeo,
+		ecco:  sat.ecco,
+		argpo: sat.argpo,
+		mo:    sat.mo,
+		no:    sat.noKozai,
+		bstar: sat.bstar,
+	}
+}
+
+// Returns a copy of the satellite initialized from other mean elements at the same epoch.
+// Line1 and Line2 keep describing the original element set.
+func (sat Satellite) withMeanElements(el meanElements) (Satellite, error) {
+	sat.inclo, sat.nodeo, sat.ecco = el.inclo, el.nodeo, el.ecco
+	sat.argpo, sat.mo, sat.bstar = el.argpo, el.mo, el.bstar
+	sat.no, sat.noKozai = el.no, el.no
+	_, _, err := sat.sgp4init(sat.jdsatepoch.Subtract(2433281.5))
+	return sat, err
+}
+
+// Mean orbital elements of an element set in TLE units: angles in degrees, mean motion in
+// revolutions per day and BSTAR in 1/earth radii
+type Elements struct {
+	Epoch time.Time
+
+	Inclination     float64
+	RAAN            float64
+	Eccentricity    float64
+	ArgOfPericenter float64
+	MeanAnomaly     float64
+
+	// Kozai mean motion as published in the element set
+	MeanMotion float64
+
+	Bstar float64
+
+	// First derivative of the mean motion divided by two in rev/day² and the second
+	// derivative divided by six in rev/day³
+	MeanMotionDot  float64
+	MeanMotionDDot float64
+}
+
+// Returns the mean elements of the element set the satellite was initialized from
+func (sat *Satellite) Elements() Elements {
+	return Elements{
+		Epoch:           sat.jdsatepoch.ToTime(),
+		Inclination:     sat.inclo * RAD2DEG,
+		RAAN:            sat.nodeo * RAD2DEG,
+		Eccentricity:    sat.ecco,
+		ArgOfPericenter: sat.argpo * RAD2DEG,
+		MeanAnomaly:     sat.mo * RAD2DEG,
+		MeanMotion:      sat.noKozai * XPDOTP,
+		Bstar:           sat.bstar,
+		MeanMotionDot:   sat.ndot * XPDOTP * 1440,
+		MeanMotionDDot:  sat.nddot * XPDOTP * 1440 * 1440,
+	}
+}
+
+// Returns the anomalistic period of the mean orbit
+func (sat *Satellite) Period() time.Duration {
+	return time.Duration(TWOPI / sat.no * float64(time.Minute))
+}
+
+// Returns the mean semi-major axis in km
+func (sat *Satellite) SemiMajorAxisKm() float64 {
+	a, _ := sat.meanAxisAndMotion()
+	return a * sat.Gravity.radiusearthkm
+}
+
+// Returns the mean apogee altitude above the equatorial radius in km
+func (sat *Satellite) ApogeeAltKm() float64 {
+	_, apogee := sat.apsisRadii()
+	return apogee - sat.Gravity.radiusearthkm
+}
+
+// Returns the mean perigee altitude above the equatorial radius in km
+func (sat *Satellite) PerigeeAltKm() float64 {
+	perigee, _ := sat.apsisRadii()
+	return perigee - sat.Gravity.radiusearthkm
+}
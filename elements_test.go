@@ -0,0 +1,63 @@
+package satellite
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Elements", func() {
+	It("should return the parsed TLE fields in TLE units", func() {
+		sat, err := NewSatFromTLE(
+			"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+			"wgs72")
+		Expect(err).To(BeNil())
+
+		el := sat.Elements()
+		Expect(el.Epoch).To(BeTemporally("~", time.Date(2008, 9, 20, 12, 25, 40, 104e6, time.UTC), time.Millisecond))
+		Expect(el.Inclination).To(BeNumerically("~", 51.6416, 1e-9))
+		Expect(el.RAAN).To(BeNumerically("~", 247.4627, 1e-9))
+		Expect(el.Eccentricity).To(BeNumerically("~", 0.0006703, 1e-12))
+		Expect(el.ArgOfPericenter).To(BeNumerically("~", 130.5360, 1e-9))
+		Expect(el.MeanAnomaly).To(BeNumerically("~", 325.0288, 1e-9))
+		Expect(el.MeanMotion).To(BeNumerically("~", 15.72125391, 1e-9))
+		Expect(el.Bstar).To(BeNumerically("~", -.11606e-4, 1e-15))
+		Expect(el.MeanMotionDot).To(BeNumerically("~", -.00002182, 1e-12))
+		Expect(el.MeanMotionDDot).To(BeNumerically("~", 0, 1e-15))
+	})
+
+	It("should derive the period and apsis altitudes of the mean orbit", func() {
+		sat, err := NewSatFromTLE(
+			"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+			"wgs72")
+		Expect(err).To(BeNil())
+
+		Expect(sat.Period().Minutes()).To(BeNumerically("~", 1440/15.72125391, 0.1))
+		Expect(sat.SemiMajorAxisKm()).To(BeNumerically("~", 6731.5, 0.5))
+		Expect(sat.ApogeeAltKm()).To(BeNumerically(">", sat.PerigeeAltKm()))
+		Expect(sat.ApogeeAltKm() - sat.PerigeeAltKm()).To(BeNumerically("~", 2*0.0006703*sat.SemiMajorAxisKm(), 1e-6))
+		Expect(sat.PerigeeAltKm()).To(BeNumerically("~", 348.8, 0.5))
+	})
+
+	It("should keep the element set metadata", func() {
+		sat := jobTestSatellites()[0]
+		Expect(sat.Classification()).To(Equal("U"))
+		Expect(sat.InternationalDesignator()).To(Equal("98067A"))
+		Expect(sat.EphemerisType()).To(Equal(0))
+		Expect(sat.ElementSetNumber()).To(Equal(int64(292)))
+		Expect(sat.RevolutionNumber()).To(Equal(int64(56353)))
+
+		gps, err := ReadGPCSV(strings.NewReader(gpCSV))
+		Expect(err).To(BeNil())
+		gp, err := NewSatFromGP(gps[0], "wgs72")
+		Expect(err).To(BeNil())
+		Expect(gp.InternationalDesignator()).To(Equal("98067A"))
+		Expect(gp.ElementSetNumber()).To(Equal(int64(292)))
+		Expect(gp.RevolutionNumber()).To(Equal(int64(56353)))
+		Expect(gp.Classification()).To(Equal("U"))
+	})
+})
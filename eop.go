@@ -0,0 +1,174 @@
+package satellite
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Earth orientation parameters at a moment
+type EOP struct {
+	// Polar motion of the pole along the x and y axes in radians
+	XP, YP float64
+
+	// UT1 minus UTC in seconds
+	DUT1 float64
+
+	// Length of day minus 86400 SI seconds, in seconds
+	LOD float64
+}
+
+// Daily Earth orientation parameters at midnight UTC of a modified julian date
+type EOPRecord struct {
+	MJD int
+	EOP
+
+	// The values are predictions rather than IERS observations
+	Predicted bool
+}
+
+// Daily Earth orientation parameters in date order
+type EOPTable struct {
+	Records []EOPRecord
+}
+
+// Reads the daily Earth orientation parameters of an IERS finals2000A file, such as
+// finals2000A.all or finals2000A.daily. Bulletin A values are used; days without polar
+// motion or UT1 values, at the end of the prediction span, are skipped.
+// Reference: https://maia.usno.navy.mil/ser7/readme.finals2000A
+func ReadFinals2000A(r io.Reader) (*EOPTable, error) {
+	table := &EOPTable{}
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		n++
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		mjd, err := strconv.ParseFloat(finalsField(line, 7, 15), 64)
+		if err != nil {
+			return nil, fmt.Errorf("finals2000A line %d: %w", n, err)
+		}
+		xp, yp, dut1 := finalsField(line, 18, 27), finalsField(line, 37, 46), finalsField(line, 58, 68)
+		if xp == "" || yp == "" || dut1 == "" {
+			continue
+		}
+		rec := EOPRecord{MJD: int(mjd), Predicted: line[16] == 'P' || line[57] == 'P'}
+		for _, f := range []struct {
+			s string
+			v *float64
+		}{{xp, &rec.XP}, {yp, &rec.YP}, {dut1, &rec.DUT1}} {
+			if *f.v, err = strconv.ParseFloat(f.s, 64); err != nil {
+				return nil, fmt.Errorf("finals2000A line %d: %w", n, err)
+			}
+		}
+		rec.XP *= arcsec
+		rec.YP *= arcsec
+		if lod, err := strconv.ParseFloat(finalsField(line, 79, 86), 64); err == nil {
+			// Milliseconds
+			rec.LOD = lod / 1000
+		}
+		table.Records = append(table.Records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.Records) == 0 {
+		return nil, errors.New("finals2000A file has no Earth orientation parameters")
+	}
+	return table, nil
+}
+
+// Returns the trimmed columns from and to of a line, empty where the line is shorter
+func finalsField(line string, from, to int) string {
+	if len(line) <= from {
+		return ""
+	}
+	return strings.TrimSpace(line[from:min(to, len(line))])
+}
+
+// Returns the Earth orientation parameters at a UTC time, interpolated linearly between the
+// daily values. UT1 minus UTC is interpolated across the jump of a leap second. Times outside
+// the table are an ErrOutOfRange error.
+func (e *EOPTable) At(t time.Time) (EOP, error) {
+	mjd, sod := cpfEpoch(t.UTC())
+	recs := e.Records
+	i := sort.Search(len(recs), func(i int) bool { return recs[i].MJD > mjd }) - 1
+	if i < 0 || i >= len(recs) || (i == len(recs)-1 && (recs[i].MJD != mjd || sod > 0)) {
+		return EOP{}, newError(ErrOutOfRange, "no Earth orientation parameters at %s", t.UTC().Format(time.RFC3339))
+	}
+	if sod == 0 && recs[i].MJD == mjd {
+		return recs[i].EOP, nil
+	}
+	a, b := recs[i], recs[i+1]
+	frac := (float64(mjd-a.MJD) + sod/86400) / float64(b.MJD-a.MJD)
+	lerp := func(x, y float64) float64 {
+		return x + frac*(y-x)
+	}
+	// A leap second raises UT1 minus UTC by one second
+	jump := math.Round(b.DUT1 - a.DUT1)
+	return EOP{
+		XP:   lerp(a.XP, b.XP),
+		YP:   lerp(a.YP, b.YP),
+		DUT1: lerp(a.DUT1, b.DUT1-jump),
+		LOD:  lerp(a.LOD, b.LOD),
+	}, nil
+}
+
+// Returns the instant at a UTC time with UT1 minus UTC from the table, whose GMST puts
+// Earth fixed coordinates up to 0.9 seconds or 400 m at the equator closer to ITRF than
+// NewInstant. Times outside the table are an ErrOutOfRange error.
+func (e *EOPTable) Instant(utc time.Time) (Instant, error) {
+	eop, err := e.At(utc)
+	if err != nil {
+		return Instant{}, err
+	}
+	return Instant{UTC: utc.UTC(), DUT1: eop.DUT1}, nil
+}
+
+// Returns the rotation from the pseudo Earth fixed frame into ITRF for polar motion in radians
+// (IAU 1980)
+func polarMotionMatrix(xp, yp float64) [][]float64 {
+	sx, cx := math.Sincos(xp)
+	sy, cy := math.Sincos(yp)
+	return [][]float64{
+		{cx, sx * sy, sx * cy},
+		{0, cy, -sy},
+		{-sx, cx * sy, cx * cy},
+	}
+}
+
+// Converts a TEME position and velocity at a UTC time into ITRF with the Earth orientation
+// parameters at that time, for example from EOPTable.At. The Earth rotation angle is GMST at
+// UT1 and the pole is moved by polar motion, which keeps ground coordinates consistent with
+// ITRF to the centimeter level where ECIToECEF, using GMST at UTC, is off by hundreds of meters.
+// Reference: Vallado, D. A., Crawford, P., Hujsak, R. and Kelso, T. S. (2006), Revisiting
+// Spacetrack Report #3, AIAA 2006-6753, appendix C.
+func TEMEToITRF(pos, vel Vector3, t time.Time, eop EOP) (itrfPos, itrfVel Vector3) {
+	gmst := Instant{UTC: t, DUT1: eop.DUT1}.GMST()
+	omega := earthAngularVelocity * (1 - eop.LOD/86400)
+	pefPos := ECIToECEF(pos, gmst)
+	pefVel := ECIToECEF(vel, gmst)
+	pefVel.X += omega * pefPos.Y
+	pefVel.Y -= omega * pefPos.X
+	pm := polarMotionMatrix(eop.XP, eop.YP)
+	return rotateVector(pm, pefPos), rotateVector(pm, pefVel)
+}
+
+// Converts an ITRF position and velocity at a UTC time into TEME, the inverse of TEMEToITRF
+func ITRFToTEME(pos, vel Vector3, t time.Time, eop EOP) (temePos, temeVel Vector3) {
+	gmst := Instant{UTC: t, DUT1: eop.DUT1}.GMST()
+	omega := earthAngularVelocity * (1 - eop.LOD/86400)
+	pm := transpose(polarMotionMatrix(eop.XP, eop.YP))
+	pefPos, pefVel := rotateVector(pm, pos), rotateVector(pm, vel)
+	pefVel.X -= omega * pefPos.Y
+	pefVel.Y += omega * pefPos.X
+	return ECEFToECI(pefPos, gmst), ECEFToECI(pefVel, gmst)
+}
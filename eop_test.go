@@ -0,0 +1,91 @@
+package satellite
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Days around the leap second at the end of 2016 and a day without values
+const finals2000A = `161230 57752.00 I  0.116524 0.000050  0.259440 0.000050  I-0.4076587 0.0000070  0.9030 0.0050
+161231 57753.00 I  0.114950 0.000050  0.259787 0.000050  I-0.4085841 0.0000070  0.9450 0.0050
+170101 57754.00 I  0.113361 0.000050  0.260138 0.000050  I 0.5904967 0.0000070  0.9851 0.0050
+170102 57755.00 P  0.111761 0.000050  0.260491 0.000050  P 0.5894883 0.0000070  1.0220 0.0050
+170103 57756.00
+`
+
+var _ = Describe("ReadFinals2000A", func() {
+	It("should read the daily values", func() {
+		table, err := ReadFinals2000A(strings.NewReader(finals2000A))
+		Expect(err).To(BeNil())
+		Expect(table.Records).To(HaveLen(4))
+		rec := table.Records[1]
+		Expect(rec.MJD).To(Equal(57753))
+		Expect(rec.XP / arcsec).To(BeNumerically("~", 0.114950, 1e-12))
+		Expect(rec.YP / arcsec).To(BeNumerically("~", 0.259787, 1e-12))
+		Expect(rec.DUT1).To(Equal(-0.4085841))
+		Expect(rec.LOD).To(BeNumerically("~", 0.000945, 1e-12))
+		Expect(rec.Predicted).To(BeFalse())
+		Expect(table.Records[3].Predicted).To(BeTrue())
+	})
+
+	It("should reject files without values", func() {
+		_, err := ReadFinals2000A(strings.NewReader("170103 57756.00\n"))
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("EOPTable", func() {
+	table, _ := ReadFinals2000A(strings.NewReader(finals2000A))
+
+	It("should interpolate between days", func() {
+		eop, err := table.At(time.Date(2016, 12, 30, 12, 0, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(eop.XP / arcsec).To(BeNumerically("~", (0.116524+0.114950)/2, 1e-12))
+		Expect(eop.DUT1).To(BeNumerically("~", (-0.4076587-0.4085841)/2, 1e-12))
+	})
+
+	It("should interpolate across a leap second", func() {
+		eop, err := table.At(time.Date(2016, 12, 31, 18, 0, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(eop.DUT1).To(BeNumerically("~", -0.4085841+0.75*(0.5904967-1+0.4085841), 1e-12))
+
+		eop, err = table.At(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(eop.DUT1).To(Equal(0.5904967))
+	})
+
+	It("should not extrapolate", func() {
+		_, err := table.At(time.Date(2016, 12, 29, 23, 0, 0, 0, time.UTC))
+		Expect(errors.Is(err, ErrOutOfRange)).To(BeTrue())
+		_, err = table.At(time.Date(2017, 1, 2, 0, 0, 1, 0, time.UTC))
+		Expect(errors.Is(err, ErrOutOfRange)).To(BeTrue())
+		_, err = table.At(time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("TEMEToITRF", func() {
+	// Vallado et al. (2006), Revisiting Spacetrack Report #3, example of appendix C
+	t := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+	eop := EOP{XP: -0.140682 * arcsec, YP: 0.333309 * arcsec, DUT1: -0.4399619, LOD: 0.0015563}
+	teme := Vector3{5094.18016210, 6127.64465950, 6380.34453270}
+	temeVel := Vector3{-4.746131487, 0.785818041, 5.531931288}
+
+	It("should match the ITRF state of Vallado", func() {
+		pos, vel := TEMEToITRF(teme, temeVel, t, eop)
+		// The reference rounds the UT1 julian date to a single float64, about 10 microseconds
+		Expect(distance(pos, Vector3{-1033.4793830, 7901.2952754, 6380.3565958})).To(BeNumerically("<", 1e-5))
+		Expect(distance(vel, Vector3{-3.225636520, -2.872451450, 5.531924446})).To(BeNumerically("<", 1e-8))
+	})
+
+	It("should be undone by ITRFToTEME", func() {
+		itrf, itrfVel := TEMEToITRF(teme, temeVel, t, eop)
+		pos, vel := ITRFToTEME(itrf, itrfVel, t, eop)
+		Expect(distance(pos, teme)).To(BeNumerically("<", 1e-8))
+		Expect(distance(vel, temeVel)).To(BeNumerically("<", 1e-11))
+	})
+})
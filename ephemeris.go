@@ -0,0 +1,189 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Time tagged position in km and velocity in km/s in the inertial (TEME) frame
+type State struct {
+	Time               time.Time
+	Position, Velocity Vector3
+}
+
+// Number of float64 values stored per record: seconds from start, position, velocity
+const ephemerisRecordLen = 7
+
+// Table of precomputed states answering StateAt by binary search and interpolation,
+// cubic Hermite between neighbouring records or Lagrange over a window of records. It
+// trades memory for fast repeated queries in simulation loops. Records are stored in one
+// contiguous array.
+type EphemerisTable struct {
+	Satnum int64
+
+	start time.Time
+	data  []float64
+
+	// Lagrange interpolation degree, zero for cubic Hermite
+	degree int
+
+	// File mapping backing data for tables opened with OpenEphemerisTable
+	mapped []byte
+}
+
+// Propagates sat from start to stop at a fixed step into a new table
+func NewEphemerisTable(sat *Satellite, start, stop time.Time, step time.Duration) (*EphemerisTable, error) {
+	if !stop.After(start) {
+		return nil, errors.New("ephemeris stop time must be after start time")
+	}
+	if step <= 0 {
+		return nil, errors.New("ephemeris step must be positive")
+	}
+
+	times := sampleTimes(start, stop, step)
+	table := &EphemerisTable{
+		Satnum: sat.Satnum,
+		start:  start,
+		data:   make([]float64, 0, len(times)*ephemerisRecordLen),
+	}
+	for _, t := range times {
+		pos, vel, err := sat.propagateAt(t)
+		if err != nil {
+			return nil, err
+		}
+		table.data = append(table.data, t.Sub(start).Seconds(), pos.X, pos.Y, pos.Z, vel.X, vel.Y, vel.Z)
+	}
+	return table, nil
+}
+
+// Builds a table from states in time order, such as an imported ephemeris. Degree zero
+// interpolates with cubic Hermite polynomials using the velocities; a positive degree
+// interpolates positions and velocities with Lagrange polynomials over degree+1 records.
+func NewEphemerisTableFromStates(satnum int64, states []State, degree int) (*EphemerisTable, error) {
+	if len(states) == 0 {
+		return nil, errors.New("ephemeris needs at least one state")
+	}
+	if degree < 0 || degree >= len(states) && len(states) > 1 {
+		return nil, fmt.Errorf("interpolation degree %d needs more than %d states", degree, len(states))
+	}
+	table := &EphemerisTable{
+		Satnum: satnum,
+		start:  states[0].Time,
+		data:   make([]float64, 0, len(states)*ephemerisRecordLen),
+		degree: degree,
+	}
+	for i, s := range states {
+		if i > 0 && !s.Time.After(states[i-1].Time) {
+			return nil, fmt.Errorf("ephemeris states not in time order at %s", s.Time.Format(time.RFC3339Nano))
+		}
+		p, v := s.Position, s.Velocity
+		table.data = append(table.data, s.Time.Sub(table.start).Seconds(), p.X, p.Y, p.Z, v.X, v.Y, v.Z)
+	}
+	return table, nil
+}
+
+// Returns the Lagrange interpolation degree, zero for cubic Hermite interpolation
+func (e *EphemerisTable) Degree() int {
+	return e.degree
+}
+
+// Returns the number of records in the table
+func (e *EphemerisTable) Len() int {
+	return len(e.data) / ephemerisRecordLen
+}
+
+// Returns the time of the first record
+func (e *EphemerisTable) Start() time.Time {
+	return e.start
+}
+
+// Returns the time of the last record
+func (e *EphemerisTable) Stop() time.Time {
+	return e.recordTime(e.Len() - 1)
+}
+
+// Returns the i-th record of the table
+func (e *EphemerisTable) At(i int) State {
+	r := e.data[i*ephemerisRecordLen : (i+1)*ephemerisRecordLen]
+	return State{
+		Time:     e.recordTime(i),
+		Position: Vector3{r[1], r[2], r[3]},
+		Velocity: Vector3{r[4], r[5], r[6]},
+	}
+}
+
+func (e *EphemerisTable) recordTime(i int) time.Time {
+	return e.start.Add(time.Duration(e.data[i*ephemerisRecordLen] * float64(time.Second)))
+}
+
+// Returns the interpolated state at t, which must lie within the table
+func (e *EphemerisTable) StateAt(t time.Time) (State, error) {
+	n := e.Len()
+	if n == 0 {
+		return State{}, newError(ErrOutOfRange, "ephemeris table is empty")
+	}
+	sec := t.Sub(e.start).Seconds()
+	last := e.data[(n-1)*ephemerisRecordLen]
+	if sec < 0 || sec > last {
+		return State{}, newError(ErrOutOfRange, "time %s outside ephemeris table %s to %s", t.Format(time.RFC3339), e.start.Format(time.RFC3339), e.Stop().Format(time.RFC3339))
+	}
+
+	if n == 1 {
+		return e.At(0), nil
+	}
+
+	// First record after t, the state is interpolated between it and its predecessor
+	i := sort.Search(n, func(i int) bool { return e.data[i*ephemerisRecordLen] > sec })
+	if i == n {
+		i = n - 1
+	}
+
+	if e.degree > 0 {
+		return e.lagrange(t, sec, i), nil
+	}
+
+	a := e.data[(i-1)*ephemerisRecordLen : i*ephemerisRecordLen]
+	b := e.data[i*ephemerisRecordLen : (i+1)*ephemerisRecordLen]
+	dt := b[0] - a[0]
+	s := (sec - a[0]) / dt
+	s2, s3 := s*s, s*s*s
+
+	h00, h10, h01, h11 := 2*s3-3*s2+1, s3-2*s2+s, -2*s3+3*s2, s3-s2
+	d00, d10, d01, d11 := (6*s2-6*s)/dt, 3*s2-4*s+1, (-6*s2+6*s)/dt, 3*s2-2*s
+
+	var p, v [3]float64
+	for k := 0; k < 3; k++ {
+		p0, p1, v0, v1 := a[1+k], b[1+k], a[4+k], b[4+k]
+		p[k] = h00*p0 + h10*dt*v0 + h01*p1 + h11*dt*v1
+		v[k] = d00*p0 + d10*v0 + d01*p1 + d11*v1
+	}
+
+	return State{
+		Time:     t,
+		Position: Vector3{p[0], p[1], p[2]},
+		Velocity: Vector3{v[0], v[1], v[2]},
+	}, nil
+}
+
+// Interpolates over the degree+1 records centred on the interval ending at record i
+func (e *EphemerisTable) lagrange(t time.Time, sec float64, i int) State {
+	n := e.Len()
+	first := min(max(i-(e.degree+1)/2, 0), n-e.degree-1)
+	var v [6]float64
+	for j := first; j <= first+e.degree; j++ {
+		rj := e.data[j*ephemerisRecordLen : (j+1)*ephemerisRecordLen]
+		w := 1.0
+		for k := first; k <= first+e.degree; k++ {
+			if k != j {
+				tk := e.data[k*ephemerisRecordLen]
+				w *= (sec - tk) / (rj[0] - tk)
+			}
+		}
+		for m := range v {
+			v[m] += w * rj[1+m]
+		}
+	}
+	return State{Time: t, Position: Vector3{v[0], v[1], v[2]}, Velocity: Vector3{v[3], v[4], v[5]}}
+}
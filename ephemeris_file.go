@@ -0,0 +1,171 @@
+package satellite
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+	"unsafe"
+)
+
+// Binary ephemeris file layout, all values little endian:
+//
+//	magic   [8]byte  "GOSATEPH"
+//	version uint32
+//	degree  uint32   Lagrange interpolation degree, zero for cubic Hermite
+//	satnum  int64
+//	start   int64    unix nanoseconds of the first record
+//	count   int64    number of records
+//	data    [count*7]float64
+//
+// The header is 40 bytes so the record data stays 8 byte aligned when mapped.
+var ephemerisMagic = [8]byte{'G', 'O', 'S', 'A', 'T', 'E', 'P', 'H'}
+
+const (
+	ephemerisVersion    = 1
+	ephemerisHeaderSize = 40
+)
+
+var errMapUnsupported = errors.New("memory mapping not supported")
+
+type ephemerisHeader struct {
+	Magic   [8]byte
+	Version uint32
+	Degree  uint32
+	Satnum  int64
+	Start   int64
+	Count   int64
+}
+
+// Writes the table in the binary ephemeris file format
+func (e *EphemerisTable) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	hdr := ephemerisHeader{
+		Magic:   ephemerisMagic,
+		Version: ephemerisVersion,
+		Degree:  uint32(e.degree),
+		Satnum:  e.Satnum,
+		Start:   e.start.UnixNano(),
+		Count:   int64(e.Len()),
+	}
+	if err := binary.Write(bw, binary.LittleEndian, &hdr); err != nil {
+		return 0, err
+	}
+	var buf [8]byte
+	for _, v := range e.data {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return 0, err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return ephemerisHeaderSize + int64(len(e.data))*8, nil
+}
+
+// Writes the table to a binary ephemeris file that can be mapped by OpenEphemerisTable
+func (e *EphemerisTable) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := e.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Reads a table in the binary ephemeris file format into memory
+func ReadEphemerisTable(r io.Reader) (*EphemerisTable, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	table, err := decodeEphemeris(b, false)
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// Opens a binary ephemeris file. Where supported the records are memory mapped
+// read only, so processes opening the same file share one copy of the data; the
+// table must then be closed to release the mapping.
+func OpenEphemerisTable(path string) (*EphemerisTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := mapFile(f)
+	if errors.Is(err, errMapUnsupported) {
+		return ReadEphemerisTable(f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	table, err := decodeEphemeris(b, true)
+	if err != nil {
+		unmapFile(b)
+		return nil, err
+	}
+	table.mapped = b
+	return table, nil
+}
+
+// Releases the file mapping of a table opened with OpenEphemerisTable. The table
+// must not be used afterwards. Closing an in-memory table does nothing.
+func (e *EphemerisTable) Close() error {
+	if e.mapped == nil {
+		return nil
+	}
+	b := e.mapped
+	e.mapped, e.data = nil, nil
+	return unmapFile(b)
+}
+
+// Decodes the file contents in b. When alias is set the records reference b
+// directly instead of being copied, which requires a little endian host.
+func decodeEphemeris(b []byte, alias bool) (*EphemerisTable, error) {
+	if len(b) < ephemerisHeaderSize {
+		return nil, errors.New("ephemeris file is too short")
+	}
+	var hdr ephemerisHeader
+	if err := binary.Read(bytes.NewReader(b[:ephemerisHeaderSize]), binary.LittleEndian, &hdr); err != nil {
+		return nil, err
+	}
+	if hdr.Magic != ephemerisMagic {
+		return nil, errors.New("not an ephemeris file")
+	}
+	if hdr.Version != ephemerisVersion {
+		return nil, fmt.Errorf("unsupported ephemeris file version %d", hdr.Version)
+	}
+	n := int(hdr.Count) * ephemerisRecordLen
+	if hdr.Count < 0 || len(b)-ephemerisHeaderSize < n*8 {
+		return nil, fmt.Errorf("ephemeris file is truncated, expected %d records", hdr.Count)
+	}
+
+	if hdr.Degree > 0 && int64(hdr.Degree) >= hdr.Count {
+		return nil, fmt.Errorf("ephemeris file interpolation degree %d needs more than %d records", hdr.Degree, hdr.Count)
+	}
+
+	table := &EphemerisTable{Satnum: hdr.Satnum, start: time.Unix(0, hdr.Start).UTC(), degree: int(hdr.Degree)}
+	raw := b[ephemerisHeaderSize : ephemerisHeaderSize+n*8]
+	if alias && binary.NativeEndian.Uint16([]byte{1, 0}) == 1 && n > 0 {
+		table.data = unsafe.Slice((*float64)(unsafe.Pointer(&raw[0])), n)
+		return table, nil
+	}
+	table.data = make([]float64, n)
+	for i := range table.data {
+		table.data[i] = math.Float64frombits(binary.LittleEndian.Uint64(raw[i*8:]))
+	}
+	return table, nil
+}
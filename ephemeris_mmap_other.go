@@ -0,0 +1,14 @@
+//go:build !unix
+
+package satellite
+
+import "os"
+
+// Memory mapping is not supported on this platform, files are read into memory instead
+func mapFile(f *os.File) ([]byte, error) {
+	return nil, errMapUnsupported
+}
+
+func unmapFile(b []byte) error {
+	return nil
+}
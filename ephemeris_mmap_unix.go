@@ -0,0 +1,24 @@
+//go:build unix
+
+package satellite
+
+import (
+	"os"
+	"syscall"
+)
+
+// Maps the whole file read only into memory
+func mapFile(f *os.File) ([]byte, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() == 0 {
+		return nil, errMapUnsupported
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapFile(b []byte) error {
+	return syscall.Munmap(b)
+}
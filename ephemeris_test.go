@@ -0,0 +1,194 @@
+package satellite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EphemerisTable", func() {
+	start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+	stop := start.Add(3 * time.Hour)
+
+	It("should interpolate states close to direct propagation", func() {
+		sat := jobTestSatellites()[0]
+		table, err := NewEphemerisTable(&sat, start, stop, time.Minute)
+		Expect(err).To(BeNil())
+		Expect(table.Len()).To(Equal(181))
+		Expect(table.Stop()).To(Equal(stop))
+
+		for t := start.Add(17 * time.Second); t.Before(stop); t = t.Add(7*time.Minute + 13*time.Second) {
+			state, err := table.StateAt(t)
+			Expect(err).To(BeNil())
+
+			pos, vel, err := sat.propagateAt(t)
+			Expect(err).To(BeNil())
+			Expect(distance(state.Position, pos)).To(BeNumerically("<", 0.01))
+			Expect(distance(state.Velocity, vel)).To(BeNumerically("<", 1e-4))
+		}
+	})
+
+	It("should return the stored records exactly", func() {
+		sat := jobTestSatellites()[0]
+		table, err := NewEphemerisTable(&sat, start, stop, time.Minute)
+		Expect(err).To(BeNil())
+
+		state, err := table.StateAt(start.Add(time.Hour))
+		Expect(err).To(BeNil())
+		Expect(state.Position).To(Equal(table.At(60).Position))
+	})
+
+	It("should round trip through a mapped binary file", func() {
+		sat := jobTestSatellites()[0]
+		table, err := NewEphemerisTable(&sat, start, stop, time.Minute)
+		Expect(err).To(BeNil())
+
+		path := filepath.Join(GinkgoT().TempDir(), "iss.eph")
+		Expect(table.WriteFile(path)).To(Succeed())
+
+		mapped, err := OpenEphemerisTable(path)
+		Expect(err).To(BeNil())
+		defer mapped.Close()
+
+		Expect(mapped.Satnum).To(Equal(table.Satnum))
+		Expect(mapped.Len()).To(Equal(table.Len()))
+		Expect(mapped.Start()).To(BeTemporally("==", table.Start()))
+
+		t := start.Add(95 * time.Minute)
+		want, _ := table.StateAt(t)
+		got, err := mapped.StateAt(t)
+		Expect(err).To(BeNil())
+		Expect(got.Position).To(Equal(want.Position))
+		Expect(got.Velocity).To(Equal(want.Velocity))
+
+		var buf bytes.Buffer
+		_, err = table.WriteTo(&buf)
+		Expect(err).To(BeNil())
+		read, err := ReadEphemerisTable(&buf)
+		Expect(err).To(BeNil())
+		Expect(read.At(42)).To(Equal(mapped.At(42)))
+	})
+
+	It("should reject times outside the table", func() {
+		sat := jobTestSatellites()[0]
+		table, err := NewEphemerisTable(&sat, start, stop, time.Minute)
+		Expect(err).To(BeNil())
+
+		_, err = table.StateAt(stop.Add(time.Second))
+		Expect(errors.Is(err, ErrOutOfRange)).To(BeTrue())
+	})
+
+	// OEM of ISS states every step from begin to end interpolated with the given method
+	issOEM := func(begin, end time.Time, step time.Duration, interpolation string) string {
+		sat := jobTestSatellites()[0]
+		var b strings.Builder
+		b.WriteString("CCSDS_OEM_VERS = 2.0\nMETA_START\nOBJECT_NAME = ISS (ZARYA)\nOBJECT_ID = 1998-067A\nCENTER_NAME = EARTH\n")
+		b.WriteString("REF_FRAME = TEME\nTIME_SYSTEM = UTC\n" + interpolation + "META_STOP\n")
+		for t := begin; !t.After(end); t = t.Add(step) {
+			pos, vel, err := sat.propagateAt(t)
+			Expect(err).To(BeNil())
+			fmt.Fprintf(&b, "%s %.6f %.6f %.6f %.9f %.9f %.9f\n", t.Format("2006-01-02T15:04:05.000"), pos.X, pos.Y, pos.Z, vel.X, vel.Y, vel.Z)
+		}
+		return b.String()
+	}
+
+	It("should interpolate OEM states with the Lagrange degree of the metadata", func() {
+		segments, err := ReadOEM(strings.NewReader(issOEM(start, stop, 2*time.Minute, "INTERPOLATION = LAGRANGE\nINTERPOLATION_DEGREE = 7\n")))
+		Expect(err).To(BeNil())
+		Expect(segments[0].Interpolation).To(Equal("LAGRANGE"))
+		Expect(segments[0].InterpolationDegree).To(Equal(7))
+
+		table, err := NewEphemerisTableFromOEM(segments[0], 25544)
+		Expect(err).To(BeNil())
+		Expect(table.Degree()).To(Equal(7))
+		Expect(table.CatalogNumber()).To(Equal(int64(25544)))
+
+		sat := jobTestSatellites()[0]
+		for t := start.Add(17 * time.Second); t.Before(stop); t = t.Add(7*time.Minute + 13*time.Second) {
+			state, err := table.StateAt(t)
+			Expect(err).To(BeNil())
+
+			pos, vel, err := sat.propagateAt(t)
+			Expect(err).To(BeNil())
+			Expect(distance(state.Position, pos)).To(BeNumerically("<", 0.001))
+			Expect(distance(state.Velocity, vel)).To(BeNumerically("<", 1e-5))
+		}
+
+		path := filepath.Join(GinkgoT().TempDir(), "iss.eph")
+		Expect(table.WriteFile(path)).To(Succeed())
+		mapped, err := OpenEphemerisTable(path)
+		Expect(err).To(BeNil())
+		defer mapped.Close()
+		Expect(mapped.Degree()).To(Equal(7))
+	})
+
+	It("should predict the same passes from an OEM as from SGP4", func() {
+		begin := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		end := begin.Add(12 * time.Hour)
+		segments, err := ReadOEM(strings.NewReader(issOEM(begin, end, time.Minute, "")))
+		Expect(err).To(BeNil())
+		table, err := NewEphemerisTableFromOEM(segments[0], 25544)
+		Expect(err).To(BeNil())
+
+		sat := jobTestSatellites()[0]
+		obs := NewLatLongAlt(55.6167, 12.6500, 0.005)
+		want, err := PredictPasses(&sat, obs, begin, end, PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(want).NotTo(BeEmpty())
+
+		got, err := PredictPasses(table, obs, begin, end, PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(got).To(HaveLen(len(want)))
+		for i := range want {
+			Expect(got[i].Satnum).To(Equal(want[i].Satnum))
+			Expect(got[i].AOS).To(BeTemporally("~", want[i].AOS, time.Second))
+			Expect(got[i].LOS).To(BeTemporally("~", want[i].LOS, time.Second))
+			Expect(got[i].MaxElevation).To(BeNumerically("~", want[i].MaxElevation, 1e-4))
+		}
+	})
+
+	It("should interpolate every segment of an OEM", func() {
+		mid := start.Add(stop.Sub(start) / 2)
+		first := issOEM(start, mid, time.Minute, "")
+		second := issOEM(mid, stop, time.Minute, "INTERPOLATION = LAGRANGE\nINTERPOLATION_DEGREE = 5\n")
+		second = second[strings.Index(second, "META_START"):]
+		eph, err := ReadOEMEphemeris(strings.NewReader(first+second), 25544)
+		Expect(err).To(BeNil())
+		Expect(eph.Segments).To(HaveLen(2))
+		Expect(eph.Segments[1].Degree()).To(Equal(5))
+		Expect(eph.CatalogNumber()).To(Equal(int64(25544)))
+		Expect(eph.Start()).To(Equal(start))
+		Expect(eph.Stop()).To(Equal(stop))
+
+		sat := jobTestSatellites()[0]
+		for t := start.Add(17 * time.Second); t.Before(stop); t = t.Add(7*time.Minute + 13*time.Second) {
+			state, err := eph.StateAt(t)
+			Expect(err).To(BeNil())
+			pos, _, err := sat.propagateAt(t)
+			Expect(err).To(BeNil())
+			Expect(distance(state.Position, pos)).To(BeNumerically("<", 0.01))
+		}
+
+		_, err = eph.StateAt(stop.Add(time.Second))
+		Expect(errors.Is(err, ErrOutOfRange)).To(BeTrue())
+
+		segments, err := ReadOEM(strings.NewReader(first + second))
+		Expect(err).To(BeNil())
+		_, err = NewOEMEphemeris([]OEMSegment{segments[1], segments[0]}, 25544)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should reject OEM states outside the TEME frame", func() {
+		segments, err := ReadOEM(strings.NewReader(issOEM(start, stop, time.Minute, "")))
+		Expect(err).To(BeNil())
+		segments[0].RefFrame = "ITRF"
+		_, err = NewEphemerisTableFromOEM(segments[0], 25544)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,196 @@
+package satellite
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Group of columns in an ephemeris CSV file
+type CSVColumn int
+
+const (
+	// Time in RFC 3339 UTC with nanoseconds
+	CSVTimeUTC CSVColumn = iota
+
+	// Julian date in UTC
+	CSVJulianDate
+
+	// TEME position and velocity
+	CSVECI
+
+	// Earth fixed position and velocity relative to the rotating Earth
+	CSVECEF
+
+	// Geodetic latitude, longitude and altitude of the sub-satellite point
+	CSVLLA
+
+	// Azimuth, elevation, range and range rate seen from the observer
+	CSVLookAngles
+)
+
+// Options for WriteEphemerisCSV and WritePassCSV
+type CSVOptions struct {
+	// Column groups in output order, time and ECI when empty
+	Columns []CSVColumn
+
+	// Sampling step, 60 seconds for ephemerides and 1 second for passes when zero
+	Step time.Duration
+
+	// Observer of the look angle columns in radians, the pass observer for WritePassCSV
+	Observer *LatLongAlt
+
+	// Distances in m and velocities in m/s instead of km and km/s
+	Meters bool
+
+	// Angles in radians instead of degrees
+	Radians bool
+
+	// Leave out the header row
+	NoHeader bool
+}
+
+// Writes the states of the satellite or ephemeris sampled between start and stop as CSV. The
+// header names each column with its unit in brackets, such as "X_ECI [km]", the convention
+// ReadGPCSV understands.
+func WriteEphemerisCSV(w io.Writer, sat Propagator, start, stop time.Time, opts CSVOptions) error {
+	if !stop.After(start) {
+		return errors.New("ephemeris stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = time.Minute
+	}
+	return writeStatesCSV(w, sat, sampleTimes(start, stop, step), opts)
+}
+
+// Writes a pass table from AOS to LOS as CSV, see WriteEphemerisCSV. Look angles are seen
+// from the observer the pass was predicted for unless the options name another one.
+func WritePassCSV(w io.Writer, p *Pass, opts CSVOptions) error {
+	if p.track == nil {
+		return errors.New("pass has no satellite to compute the table from")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = time.Second
+	}
+	if opts.Observer == nil {
+		obs := p.track.obs
+		opts.Observer = &obs
+	}
+	return writeStatesCSV(w, p.track.sat, sampleTimes(p.AOS, p.LOS, step), opts)
+}
+
+// Writes one CSV row of the selected columns per time
+func writeStatesCSV(w io.Writer, sat Propagator, times []time.Time, opts CSVOptions) error {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = []CSVColumn{CSVTimeUTC, CSVECI}
+	}
+	var obsECEF Vector3
+	for _, c := range columns {
+		if c == CSVLookAngles {
+			if opts.Observer == nil {
+				return errors.New("look angle columns need an observer")
+			}
+			obsECEF = LLAToECEF(*opts.Observer, gravityOf(sat))
+		}
+	}
+
+	dist, speed, distPrec, speedPrec := "km", "km/s", 6, 9
+	if opts.Meters {
+		dist, speed, distPrec, speedPrec = "m", "m/s", 3, 6
+	}
+	angle, angPrec := "deg", 6
+	if opts.Radians {
+		angle, angPrec = "rad", 9
+	}
+	scaleDist := func(v float64) string {
+		if opts.Meters {
+			v *= 1000
+		}
+		return strconv.FormatFloat(v, 'f', distPrec, 64)
+	}
+	scaleSpeed := func(v float64) string {
+		if opts.Meters {
+			v *= 1000
+		}
+		return strconv.FormatFloat(v, 'f', speedPrec, 64)
+	}
+	scaleAngle := func(v float64) string {
+		if !opts.Radians {
+			v *= RAD2DEG
+		}
+		return strconv.FormatFloat(v, 'f', angPrec, 64)
+	}
+
+	cw := csv.NewWriter(w)
+	if !opts.NoHeader {
+		var header []string
+		for _, c := range columns {
+			switch c {
+			case CSVTimeUTC:
+				header = append(header, "UTC")
+			case CSVJulianDate:
+				header = append(header, "JD [UTC]")
+			case CSVECI, CSVECEF:
+				frame := "ECI"
+				if c == CSVECEF {
+					frame = "ECEF"
+				}
+				for _, axis := range []string{"X", "Y", "Z"} {
+					header = append(header, axis+"_"+frame+" ["+dist+"]")
+				}
+				for _, axis := range []string{"VX", "VY", "VZ"} {
+					header = append(header, axis+"_"+frame+" ["+speed+"]")
+				}
+			case CSVLLA:
+				header = append(header, "LATITUDE ["+angle+"]", "LONGITUDE ["+angle+"]", "ALTITUDE ["+dist+"]")
+			case CSVLookAngles:
+				header = append(header, "AZIMUTH ["+angle+"]", "ELEVATION ["+angle+"]", "RANGE ["+dist+"]", "RANGE_RATE ["+speed+"]")
+			}
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	grav := gravityOf(sat)
+	var row []string
+	for _, t := range times {
+		s, err := sat.StateAt(t)
+		if err != nil {
+			return err
+		}
+		pos, vel := grav.inertialToFixed(s.Position, s.Velocity, t)
+		row = row[:0]
+		for _, c := range columns {
+			switch c {
+			case CSVTimeUTC:
+				row = append(row, t.UTC().Format(time.RFC3339Nano))
+			case CSVJulianDate:
+				row = append(row, strconv.FormatFloat(NewJDayFromTime(t).Single(), 'f', 9, 64))
+			case CSVECI:
+				row = append(row, scaleDist(s.Position.X), scaleDist(s.Position.Y), scaleDist(s.Position.Z),
+					scaleSpeed(s.Velocity.X), scaleSpeed(s.Velocity.Y), scaleSpeed(s.Velocity.Z))
+			case CSVECEF:
+				row = append(row, scaleDist(pos.X), scaleDist(pos.Y), scaleDist(pos.Z),
+					scaleSpeed(vel.X), scaleSpeed(vel.Y), scaleSpeed(vel.Z))
+			case CSVLLA:
+				alt, _, ll := ECIToLLA(pos, 0)
+				row = append(row, scaleAngle(ll.Latitude), scaleAngle(wrapPi(ll.Longitude)), scaleDist(alt))
+			case CSVLookAngles:
+				la := ecefLookAngles(pos, obsECEF, *opts.Observer)
+				row = append(row, scaleAngle(la.Az), scaleAngle(la.El), scaleDist(la.Rg),
+					scaleSpeed(topocentricRangeRate(pos, vel, obsECEF)))
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,100 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteEphemerisCSV", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+	sat := jobTestSatellites()[0]
+
+	read := func(buf *bytes.Buffer) [][]string {
+		records, err := csv.NewReader(buf).ReadAll()
+		Expect(err).To(BeNil())
+		return records
+	}
+	parse := func(s string) float64 {
+		v, err := strconv.ParseFloat(s, 64)
+		Expect(err).To(BeNil())
+		return v
+	}
+
+	It("should write time and ECI columns by default", func() {
+		var buf bytes.Buffer
+		Expect(WriteEphemerisCSV(&buf, &sat, start, start.Add(10*time.Minute), CSVOptions{})).To(Succeed())
+		records := read(&buf)
+		Expect(records).To(HaveLen(12))
+		Expect(records[0]).To(Equal([]string{"UTC", "X_ECI [km]", "Y_ECI [km]", "Z_ECI [km]", "VX_ECI [km/s]", "VY_ECI [km/s]", "VZ_ECI [km/s]"}))
+		Expect(records[2][0]).To(Equal("2008-09-20T00:01:00Z"))
+
+		pos, vel, err := sat.Propagate(start.Add(time.Minute))
+		Expect(err).To(BeNil())
+		Expect(parse(records[2][1])).To(BeNumerically("~", pos.X, 1e-6))
+		Expect(parse(records[2][6])).To(BeNumerically("~", vel.Z, 1e-9))
+	})
+
+	It("should write the selected columns in the selected units", func() {
+		var buf bytes.Buffer
+		opts := CSVOptions{
+			Columns:  []CSVColumn{CSVJulianDate, CSVECEF, CSVLLA, CSVLookAngles},
+			Step:     30 * time.Second,
+			Observer: &copenhagen,
+			Meters:   true,
+			Radians:  true,
+		}
+		t := start.Add(time.Minute)
+		Expect(WriteEphemerisCSV(&buf, &sat, start, t, opts)).To(Succeed())
+		records := read(&buf)
+		Expect(records).To(HaveLen(4))
+		Expect(records[0]).To(Equal([]string{"JD [UTC]",
+			"X_ECEF [m]", "Y_ECEF [m]", "Z_ECEF [m]", "VX_ECEF [m/s]", "VY_ECEF [m/s]", "VZ_ECEF [m/s]",
+			"LATITUDE [rad]", "LONGITUDE [rad]", "ALTITUDE [m]",
+			"AZIMUTH [rad]", "ELEVATION [rad]", "RANGE [m]", "RANGE_RATE [m/s]"}))
+
+		row := records[3]
+		Expect(parse(row[0])).To(BeNumerically("~", NewJDayFromTime(t).Single(), 1e-8))
+		pos, vel, err := sat.PropagateECEF(t)
+		Expect(err).To(BeNil())
+		Expect(parse(row[1])).To(BeNumerically("~", pos.X*1000, 1e-3))
+		Expect(parse(row[6])).To(BeNumerically("~", vel.Z*1000, 1e-6))
+
+		lla, _, err := sat.PropagateLLA(t)
+		Expect(err).To(BeNil())
+		Expect(parse(row[7])).To(BeNumerically("~", lla.LatLong.Latitude, 1e-9))
+		Expect(parse(row[8])).To(BeNumerically("~", lla.LatLong.Longitude, 1e-9))
+		Expect(parse(row[9])).To(BeNumerically("~", lla.AltitudeKm*1000, 1e-3))
+
+		obsECEF := LLAToECEF(copenhagen, sat.Gravity)
+		la := ecefLookAngles(pos, obsECEF, copenhagen)
+		Expect(parse(row[10])).To(BeNumerically("~", la.Az, 1e-9))
+		Expect(parse(row[11])).To(BeNumerically("~", la.El, 1e-9))
+		Expect(parse(row[12])).To(BeNumerically("~", la.Rg*1000, 1e-3))
+		Expect(parse(row[13])).To(BeNumerically("~", topocentricRangeRate(pos, vel, obsECEF)*1000, 1e-6))
+	})
+
+	It("should need an observer for look angles", func() {
+		err := WriteEphemerisCSV(&bytes.Buffer{}, &sat, start, start.Add(time.Minute), CSVOptions{Columns: []CSVColumn{CSVLookAngles}})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should write a pass table seen from the pass observer", func() {
+		passes, err := NextPasses(&sat, copenhagen, start, 1, PassOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+		p := passes[0]
+
+		var buf bytes.Buffer
+		Expect(WritePassCSV(&buf, &p, CSVOptions{Columns: []CSVColumn{CSVTimeUTC, CSVLookAngles}, NoHeader: true})).To(Succeed())
+		records := read(&buf)
+		Expect(records).To(HaveLen(len(sampleTimes(p.AOS, p.LOS, time.Second))))
+		Expect(records[0][0]).To(Equal(p.AOS.UTC().Format(time.RFC3339Nano)))
+		Expect(parse(records[0][1])).To(BeNumerically("~", p.AOSAzimuth*RAD2DEG, 1e-3))
+		Expect(parse(records[0][2])).To(BeNumerically("~", 10, 1e-2))
+	})
+})
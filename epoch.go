@@ -0,0 +1,51 @@
+package satellite
+
+import "time"
+
+// First year of the hundred year window two digit TLE epoch years are read in. Years
+// 57 to 99 are 1957 to 1999 and 00 to 56 are 2000 to 2056, following the TLE convention.
+const DefaultEpochPivot = 1957
+
+// Settings for reading two line element sets
+type TLEOptions struct {
+	// First year of the window two digit epoch years are read in, for example 1940 for
+	// historical datasets or 1980 once element sets reach 2057. DefaultEpochPivot when zero.
+	EpochPivot int
+}
+
+// Returns the epoch pivot of the options
+func (o TLEOptions) pivot() int64 {
+	if o.EpochPivot == 0 {
+		return DefaultEpochPivot
+	}
+	return int64(o.EpochPivot)
+}
+
+// Returns the full year of an epoch year. Two digit years are placed in the hundred
+// years starting at the pivot; years from 100 on, as given by OMM, are full years already.
+func epochYear(year, pivot int64) int64 {
+	if year >= 100 {
+		return year
+	}
+	full := pivot - pivot%100 + year
+	if year < pivot%100 {
+		full += 100
+	}
+	return full
+}
+
+// Sets the Julian date of the element set epoch from its year and fractional day of year
+func (sat *Satellite) setEpoch(year int64, days float64) {
+	mon, day, hr, min, sec := days2mdhms(year, days)
+	sat.jdsatepoch = NewJDay(int(year), int(mon), int(day), int(hr), int(min), sec)
+}
+
+// Returns the epoch of the element set in UTC
+func (sat *Satellite) EpochTime() time.Time {
+	return sat.jdsatepoch.ToTime()
+}
+
+// Returns the age of the element set at t, negative before its epoch
+func (sat *Satellite) AgeAt(t time.Time) time.Duration {
+	return t.Sub(sat.EpochTime())
+}
@@ -0,0 +1,58 @@
+package satellite
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Epoch year", func() {
+	It("should read two digit years in the window starting at the pivot", func() {
+		Expect(epochYear(57, DefaultEpochPivot)).To(Equal(int64(1957)))
+		Expect(epochYear(99, DefaultEpochPivot)).To(Equal(int64(1999)))
+		Expect(epochYear(0, DefaultEpochPivot)).To(Equal(int64(2000)))
+		Expect(epochYear(56, DefaultEpochPivot)).To(Equal(int64(2056)))
+		Expect(epochYear(56, 1940)).To(Equal(int64(1956)))
+		Expect(epochYear(39, 1940)).To(Equal(int64(2039)))
+		Expect(epochYear(60, 1980)).To(Equal(int64(2060)))
+		Expect(epochYear(80, 1980)).To(Equal(int64(1980)))
+		Expect(epochYear(8, 2000)).To(Equal(int64(2008)))
+	})
+
+	It("should keep full years", func() {
+		Expect(epochYear(2057, DefaultEpochPivot)).To(Equal(int64(2057)))
+		Expect(epochYear(1958, 1980)).To(Equal(int64(1958)))
+	})
+
+	It("should apply the configured pivot when parsing element sets", func() {
+		l1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+		l2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+		sat, err := NewSatFromTLE(l1, l2, "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sat.jdsatepoch.ToTime().Year()).To(Equal(2008))
+
+		old, err := NewSatFromTLEWithOptions(l1, l2, "wgs72", TLEOptions{EpochPivot: 1905})
+		Expect(err).To(BeNil())
+		Expect(old.jdsatepoch.ToTime().Year()).To(Equal(1908))
+		Expect(sat.jdsatepoch.ToTime().Sub(old.jdsatepoch.ToTime())).To(BeNumerically("~", 36525*24*time.Hour, 24*time.Hour))
+
+		sats, err := ParseTLEsWithOptions(strings.NewReader("ISS (ZARYA)\n"+l1+"\n"+l2+"\n"), "wgs72", TLEOptions{EpochPivot: 1905})
+		Expect(err).To(BeNil())
+		Expect(sats).To(HaveLen(1))
+		Expect(sats[0].Name).To(Equal("ISS (ZARYA)"))
+		Expect(sats[0].EpochTime()).To(Equal(old.EpochTime()))
+	})
+})
+
+var _ = Describe("EpochTime", func() {
+	It("should return the epoch in UTC and the element set age", func() {
+		sat := jobTestSatellites()[0]
+		epoch := sat.EpochTime()
+		Expect(epoch.Location()).To(Equal(time.UTC))
+		Expect(epoch).To(BeTemporally("~", time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC), time.Millisecond))
+		Expect(sat.AgeAt(epoch.Add(72 * time.Hour))).To(Equal(72 * time.Hour))
+		Expect(sat.AgeAt(epoch.Add(-time.Hour))).To(Equal(-time.Hour))
+	})
+})
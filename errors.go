@@ -0,0 +1,144 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Sentinel errors for the expected failure modes. Returned errors wrap these
+// so callers can branch on them with errors.Is.
+var (
+	// A TLE line is not 69 characters long
+	ErrBadLineLength = errors.New("bad TLE line length")
+
+	// A TLE line does not match its modulo 10 checksum
+	ErrChecksum = errors.New("TLE checksum mismatch")
+
+	// The requested gravity model name is not known
+	ErrUnknownGravModel = errors.New("unknown gravity model")
+
+	// The propagated orbit radius is below the Earth's surface
+	ErrSatelliteDecayed = errors.New("satellite has decayed")
+
+	// The propagated elements left the range SGP4 is valid for
+	ErrOutsideValidity = errors.New("elements outside SGP4 validity range")
+
+	// Propagation failures by PropagationError code, each also matching ErrOutsideValidity
+	// or ErrSatelliteDecayed
+	ErrMeanElements         = errors.New("mean eccentricity out of range")
+	ErrNegativeMeanMotion   = errors.New("mean motion is negative")
+	ErrNegativeEccentricity = errors.New("perturbed eccentricity out of range")
+	ErrSemiLatusRectum      = errors.New("semi-latus rectum is negative")
+	ErrSubOrbital           = errors.New("epoch elements are sub-orbital")
+
+	// A requested time lies outside the span covered by precomputed data
+	ErrOutOfRange = errors.New("time outside covered range")
+
+	// A resume checkpoint was produced by a different job or for different inputs
+	ErrBadCheckpoint = errors.New("checkpoint does not match job")
+
+	// No satellite matches a catalog number or name
+	ErrUnknownSatellite = errors.New("unknown satellite")
+
+	// A satellite name matches more than one catalog number
+	ErrAmbiguousName = errors.New("ambiguous satellite name")
+
+	// An element set was fitted for a propagator other than SGP4, such as the SGP4-XP
+	// elements of ephemeris type 4
+	ErrUnsupportedEphemeris = errors.New("unsupported ephemeris type")
+)
+
+// Error carrying a descriptive message while matching one of the sentinel errors
+type satError struct {
+	msg  string
+	kind error
+}
+
+func (e *satError) Error() string {
+	return e.msg
+}
+
+func (e *satError) Unwrap() error {
+	return e.kind
+}
+
+// Returns an error with the formatted message that wraps kind
+func newError(kind error, format string, a ...interface{}) error {
+	return &satError{msg: fmt.Sprintf(format, a...), kind: kind}
+}
+
+// Error returned when SGP4 propagation fails. Code follows the numbering of the
+// reference sgp4 implementation:
+//
+//	1 - mean eccentricity not within range 0.0 <= e < 1.0
+//	2 - mean motion less than 0.0
+//	3 - perturbed eccentricity not within range 0.0 <= e <= 1.0
+//	4 - semi-latus rectum less than 0.0
+//	5 - epoch elements are sub-orbital
+//	6 - satellite has decayed
+type PropagationError struct {
+	Code   int
+	Satnum int64
+
+	// Requested propagation time and its offset from the TLE epoch in minutes
+	Time   time.Time
+	Tsince float64
+
+	// Offending quantity: eccentricity for codes 1 and 3, mean motion in
+	// rad/min for code 2, semi-latus rectum in earth radii for code 4,
+	// perigee radius in km for code 5 and orbit radius in km for code 6
+	Value float64
+}
+
+func (e *PropagationError) Error() string {
+	var what string
+	switch e.Code {
+	case 1:
+		what = fmt.Sprintf("mean eccentricity %g not within range 0.0 <= e < 1.0", e.Value)
+	case 2:
+		what = fmt.Sprintf("mean motion %g rad/min is less than zero", e.Value)
+	case 3:
+		what = fmt.Sprintf("perturbed eccentricity %g not within range 0.0 <= e <= 1.0", e.Value)
+	case 4:
+		what = fmt.Sprintf("semilatus rectum %g is less than zero", e.Value)
+	case 5:
+		what = fmt.Sprintf("epoch elements are sub-orbital with perigee radius %.3f km", e.Value)
+	case 6:
+		what = fmt.Sprintf("orbit radius %.3f km is below the earth surface indicating the satellite has decayed", e.Value)
+	default:
+		what = fmt.Sprintf("unknown error code %d", e.Code)
+	}
+	return fmt.Sprintf("satellite %d at %s (%.3f min from epoch): %s", e.Satnum, e.Time.Format(time.RFC3339Nano), e.Tsince, what)
+}
+
+// Returns the sentinel of the code and its category, ErrSatelliteDecayed for codes 5 and 6
+// and ErrOutsideValidity otherwise
+func (e *PropagationError) Unwrap() []error {
+	switch e.Code {
+	case 1:
+		return []error{ErrMeanElements, ErrOutsideValidity}
+	case 2:
+		return []error{ErrNegativeMeanMotion, ErrOutsideValidity}
+	case 3:
+		return []error{ErrNegativeEccentricity, ErrOutsideValidity}
+	case 4:
+		return []error{ErrSemiLatusRectum, ErrOutsideValidity}
+	case 5:
+		return []error{ErrSubOrbital, ErrSatelliteDecayed}
+	case 6:
+		return []error{ErrSatelliteDecayed}
+	}
+	return []error{ErrOutsideValidity}
+}
+
+// Returns a PropagationError for the given code at tsince minutes from epoch
+func (sat *Satellite) propagationError(code int, tsince, value float64) error {
+	return &PropagationError{
+		Code:   code,
+		Satnum: sat.Satnum,
+		Time:   sat.jdsatepoch.ToTime().Add(time.Duration(tsince * float64(time.Minute))),
+		Tsince: tsince,
+		Value:  value,
+	}
+}
@@ -0,0 +1,270 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Propagates an inertial state to t
+type PredictionModel func(s State, t time.Time) (State, error)
+
+// Longest integration step of NumericalModel in seconds
+const numericalStep = 30.0
+
+// Returns a numerical model integrating two body motion with the J2 perturbation of the
+// gravity model using fourth order Runge-Kutta steps of at most 30 seconds
+func NumericalModel(grav GravConst) PredictionModel {
+	accel := func(y [6]float64) [6]float64 {
+		x, yy, z := y[0], y[1], y[2]
+		r2 := x*x + yy*yy + z*z
+		r := math.Sqrt(r2)
+		k := -grav.mu / (r2 * r)
+		j := 1.5 * grav.j2 * grav.radiusearthkm * grav.radiusearthkm / r2
+		zz := 5 * z * z / r2
+		return [6]float64{
+			y[3], y[4], y[5],
+			k * x * (1 + j*(1-zz)),
+			k * yy * (1 + j*(1-zz)),
+			k * z * (1 + j*(3-zz)),
+		}
+	}
+	return func(s State, t time.Time) (State, error) {
+		total := t.Sub(s.Time).Seconds()
+		steps := int(math.Ceil(math.Abs(total) / numericalStep))
+		y := [6]float64{s.Position.X, s.Position.Y, s.Position.Z, s.Velocity.X, s.Velocity.Y, s.Velocity.Z}
+		for n := 0; n < steps; n++ {
+			h := total / float64(steps)
+			add := func(d [6]float64, f float64) (out [6]float64) {
+				for i := range y {
+					out[i] = y[i] + f*d[i]
+				}
+				return
+			}
+			k1 := accel(y)
+			k2 := accel(add(k1, h/2))
+			k3 := accel(add(k2, h/2))
+			k4 := accel(add(k3, h))
+			for i := range y {
+				y[i] += h / 6 * (k1[i] + 2*k2[i] + 2*k3[i] + k4[i])
+			}
+		}
+		if math.IsNaN(y[0]) {
+			return State{}, newError(ErrSatelliteDecayed, "numerical propagation diverged")
+		}
+		return State{Time: t, Position: Vector3{y[0], y[1], y[2]}, Velocity: Vector3{y[3], y[4], y[5]}}, nil
+	}
+}
+
+// Returns a model of unperturbed two body motion around the central body of the gravity model
+func TwoBodyModel(grav GravConst) PredictionModel {
+	grav.j2 = 0
+	return NumericalModel(grav)
+}
+
+// Returns a model following the SGP4 trajectory of sat. The deviation of a state from the
+// trajectory is carried forward by the numerical model.
+func SGP4Model(sat *Satellite) PredictionModel {
+	num := NumericalModel(sat.Gravity)
+	return func(s State, t time.Time) (State, error) {
+		p0, v0, err := sat.propagateAt(s.Time)
+		if err != nil {
+			return State{}, err
+		}
+		p1, v1, err := sat.propagateAt(t)
+		if err != nil {
+			return State{}, err
+		}
+		dev, err := num(s, t)
+		if err != nil {
+			return State{}, err
+		}
+		ref, err := num(State{Time: s.Time, Position: p0, Velocity: v0}, t)
+		if err != nil {
+			return State{}, err
+		}
+		return State{
+			Time:     t,
+			Position: Vector3{p1.X + dev.Position.X - ref.Position.X, p1.Y + dev.Position.Y - ref.Position.Y, p1.Z + dev.Position.Z - ref.Position.Z},
+			Velocity: Vector3{v1.X + dev.Velocity.X - ref.Velocity.X, v1.Y + dev.Velocity.Y - ref.Velocity.Y, v1.Z + dev.Velocity.Z - ref.Velocity.Z},
+		}, nil
+	}
+}
+
+// Extended Kalman filter estimating the inertial state of a satellite from streaming observations
+type TrackingFilter struct {
+	Model PredictionModel
+
+	// Earth model used to place the stations
+	Gravity GravConst
+
+	// Current estimate and its 6x6 covariance in km and km/s
+	State      State
+	Covariance [][]float64
+
+	// Spectral density of the white acceleration noise added during prediction in km²/s³
+	ProcessNoise float64
+
+	// Standard deviations of the measurements as for ODOptions, a measurement type is used
+	// only when its deviation is positive
+	Sigma ObservationErrors
+}
+
+// Returns a filter following the SGP4 trajectory of sat, started at t with the given
+// position and velocity deviations in km and km/s
+func NewTrackingFilter(sat *Satellite, t time.Time, positionSigma, velocitySigma float64, sigma ObservationErrors) (*TrackingFilter, error) {
+	pos, vel, err := sat.propagateAt(t)
+	if err != nil {
+		return nil, err
+	}
+	cov := newMatrix(6, 6)
+	for i := 0; i < 3; i++ {
+		cov[i][i] = positionSigma * positionSigma
+		cov[i+3][i+3] = velocitySigma * velocitySigma
+	}
+	return &TrackingFilter{
+		Model:      SGP4Model(sat),
+		Gravity:    sat.Gravity,
+		State:      State{Time: t, Position: pos, Velocity: vel},
+		Covariance: cov,
+		Sigma:      sigma,
+	}, nil
+}
+
+// Propagates the estimate and its covariance to t
+func (tr *TrackingFilter) Predict(t time.Time) error {
+	if t.Equal(tr.State.Time) {
+		return nil
+	}
+	next, err := tr.Model(tr.State, t)
+	if err != nil {
+		return err
+	}
+
+	// State transition matrix by forward differences of the model
+	phi := newMatrix(6, 6)
+	x := stateVector(tr.State)
+	y := stateVector(next)
+	for k := 0; k < 6; k++ {
+		h := 1e-3
+		if k >= 3 {
+			h = 1e-6
+		}
+		xp := x
+		xp[k] += h
+		sp, err := tr.Model(vectorState(tr.State.Time, xp), t)
+		if err != nil {
+			return err
+		}
+		yp := stateVector(sp)
+		for i := 0; i < 6; i++ {
+			phi[i][k] = (yp[i] - y[i]) / h
+		}
+	}
+
+	p := mulMat(mulMat(phi, tr.Covariance), transpose(phi))
+	if q := tr.ProcessNoise; q > 0 {
+		dt := math.Abs(t.Sub(tr.State.Time).Seconds())
+		for i := 0; i < 3; i++ {
+			p[i][i] += q * dt * dt * dt / 3
+			p[i][i+3] += q * dt * dt / 2
+			p[i+3][i] += q * dt * dt / 2
+			p[i+3][i+3] += q * dt
+		}
+	}
+	tr.State, tr.Covariance = next, p
+	return nil
+}
+
+// Predicts the estimate to the observation time and corrects it with the measurement.
+// Returns the residual of the observation against the prediction.
+func (tr *TrackingFilter) Update(o Observation) (ObservationResidual, error) {
+	if tr.Sigma.Angle <= 0 && tr.Sigma.Range <= 0 && tr.Sigma.RangeRate <= 0 {
+		return ObservationResidual{}, errors.New("tracking filter needs at least one measurement deviation")
+	}
+	if err := tr.Predict(o.Time); err != nil {
+		return ObservationResidual{}, err
+	}
+	obsECEF := LLAToECEF(o.Station, tr.Gravity)
+
+	computed := observeState(tr.State, o.Station, obsECEF)
+	res := observationDifference(&o, &computed)
+	y, r := tr.measurements(res)
+
+	// Measurement matrix by forward differences, in the same quantities as the residual
+	x := stateVector(tr.State)
+	hm := newMatrix(len(y), 6)
+	for k := 0; k < 6; k++ {
+		h := 1e-3
+		if k >= 3 {
+			h = 1e-6
+		}
+		xp := x
+		xp[k] += h
+		c := observeState(vectorState(o.Time, xp), o.Station, obsECEF)
+		d, _ := tr.measurements(observationDifference(&c, &computed))
+		for i := range d {
+			hm[i][k] = d[i] / h
+		}
+	}
+
+	// Gain and Joseph form covariance update
+	ht := transpose(hm)
+	s := mulMat(mulMat(hm, tr.Covariance), ht)
+	for i := range s {
+		s[i][i] += r[i]
+	}
+	sinv, err := invertMatrix(s)
+	if err != nil {
+		return res, err
+	}
+	gain := mulMat(mulMat(tr.Covariance, ht), sinv)
+	dx := mulMatVec(gain, y)
+	for k := range x {
+		x[k] += dx[k]
+	}
+	tr.State = vectorState(o.Time, x)
+
+	ikh := mulMat(gain, hm)
+	for i := range ikh {
+		for j := range ikh[i] {
+			ikh[i][j] = -ikh[i][j]
+		}
+		ikh[i][i]++
+	}
+	p := mulMat(mulMat(ikh, tr.Covariance), transpose(ikh))
+	for i := range p {
+		for j := range p[i] {
+			for m := range r {
+				p[i][j] += gain[i][m] * r[m] * gain[j][m]
+			}
+		}
+	}
+	tr.Covariance = p
+	return res, nil
+}
+
+// Returns the used measurements of a residual and their variances
+func (tr *TrackingFilter) measurements(d ObservationResidual) (y, variance []float64) {
+	if s := tr.Sigma.Angle; s > 0 {
+		y = append(y, d.Az, d.El)
+		variance = append(variance, s*s, s*s)
+	}
+	if s := tr.Sigma.Range; s > 0 {
+		y = append(y, d.Range)
+		variance = append(variance, s*s)
+	}
+	if s := tr.Sigma.RangeRate; s > 0 {
+		y = append(y, d.RangeRate)
+		variance = append(variance, s*s)
+	}
+	return y, variance
+}
+
+func stateVector(s State) [6]float64 {
+	return [6]float64{s.Position.X, s.Position.Y, s.Position.Z, s.Velocity.X, s.Velocity.Y, s.Velocity.Z}
+}
+
+func vectorState(t time.Time, x [6]float64) State {
+	return State{Time: t, Position: Vector3{x[0], x[1], x[2]}, Velocity: Vector3{x[3], x[4], x[5]}}
+}
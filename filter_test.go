@@ -0,0 +1,71 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TrackingFilter", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should integrate close to SGP4 over a short arc", func() {
+		sat := jobTestSatellites()[0]
+		pos, vel, err := sat.propagateAt(start)
+		Expect(err).To(BeNil())
+		got, err := NumericalModel(sat.Gravity)(State{Time: start, Position: pos, Velocity: vel}, start.Add(10*time.Minute))
+		Expect(err).To(BeNil())
+		want, _, _ := sat.propagateAt(start.Add(10 * time.Minute))
+		Expect(got.Time).To(Equal(start.Add(10 * time.Minute)))
+		Expect(distance(got.Position, want)).To(BeNumerically("<", 2))
+	})
+
+	It("should follow the SGP4 trajectory without a deviation", func() {
+		sat := jobTestSatellites()[0]
+		pos, vel, _ := sat.propagateAt(start)
+		got, err := SGP4Model(&sat)(State{Time: start, Position: pos, Velocity: vel}, start.Add(3*time.Hour))
+		Expect(err).To(BeNil())
+		want, _, _ := sat.propagateAt(start.Add(3 * time.Hour))
+		Expect(distance(got.Position, want)).To(BeNumerically("<", 1e-6))
+	})
+
+	It("should converge on the truth from streaming observations", func() {
+		truth := jobTestSatellites()[0]
+		noise := ObservationErrors{Angle: 0.01 * DEG2RAD, Range: 0.05, RangeRate: 0.001}
+		sim := &ObservationSimulator{Station: copenhagen, Noise: noise, MinElevation: Degrees(10).Radians()}
+		obs, err := sim.Simulate(&truth, start, start.Add(12*time.Hour), 10*time.Second)
+		Expect(err).To(BeNil())
+
+		// Start from an element set a few tens of km off the truth
+		el := truth.meanElements()
+		el.mo -= 0.3 * DEG2RAD
+		el.nodeo += 0.1 * DEG2RAD
+		apriori, err := truth.withMeanElements(el)
+		Expect(err).To(BeNil())
+
+		tr, err := NewTrackingFilter(&apriori, obs[0].Time, 50, 0.05, noise)
+		Expect(err).To(BeNil())
+		tr.ProcessNoise = 1e-12
+		initial, _, _ := truth.propagateAt(obs[0].Time)
+		Expect(distance(tr.State.Position, initial)).To(BeNumerically(">", 20))
+
+		first, err := tr.Update(obs[0])
+		Expect(err).To(BeNil())
+		Expect(first.Range).NotTo(BeZero())
+		for _, o := range obs[1:] {
+			_, err := tr.Update(o)
+			Expect(err).To(BeNil())
+		}
+
+		want, _, _ := truth.propagateAt(tr.State.Time)
+		Expect(distance(tr.State.Position, want)).To(BeNumerically("<", 1))
+		Expect(tr.Covariance[0][0]).To(BeNumerically("<", 1))
+		Expect(tr.Covariance[0][0]).To(BeNumerically(">", 0))
+
+		Expect(tr.Predict(tr.State.Time.Add(time.Hour))).To(Succeed())
+		want, _, _ = truth.propagateAt(tr.State.Time)
+		Expect(distance(tr.State.Position, want)).To(BeNumerically("<", 5))
+	})
+})
@@ -0,0 +1,188 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Arcseconds in radians
+const arcsec = math.Pi / (180 * 3600)
+
+// Term of the IAU 1980 nutation series: multiples of the fundamental arguments D, M, M', F and
+// Omega and the coefficients of the nutation in longitude and obliquity in 0.0001 arcseconds
+// with their rates per Julian century
+type nutationTerm struct {
+	d, m, mp, f, om      float64
+	psi, psiT, eps, epsT float64
+}
+
+// IAU 1980 nutation terms down to 0.0003 arcseconds, the 63 terms of Meeus table 22.A. The
+// omitted terms of the 106 term series add up to a few milliarcseconds.
+var nutationTerms = []nutationTerm{
+	{0, 0, 0, 0, 1, -171996, -174.2, 92025, 8.9},
+	{-2, 0, 0, 2, 2, -13187, -1.6, 5736, -3.1},
+	{0, 0, 0, 2, 2, -2274, -0.2, 977, -0.5},
+	{0, 0, 0, 0, 2, 2062, 0.2, -895, 0.5},
+	{0, 1, 0, 0, 0, 1426, -3.4, 54, -0.1},
+	{0, 0, 1, 0, 0, 712, 0.1, -7, 0},
+	{-2, 1, 0, 2, 2, -517, 1.2, 224, -0.6},
+	{0, 0, 0, 2, 1, -386, -0.4, 200, 0},
+	{0, 0, 1, 2, 2, -301, 0, 129, -0.1},
+	{-2, -1, 0, 2, 2, 217, -0.5, -95, 0.3},
+	{-2, 0, 1, 0, 0, -158, 0, 0, 0},
+	{-2, 0, 0, 2, 1, 129, 0.1, -70, 0},
+	{0, 0, -1, 2, 2, 123, 0, -53, 0},
+	{2, 0, 0, 0, 0, 63, 0, 0, 0},
+	{0, 0, 1, 0, 1, 63, 0.1, -33, 0},
+	{2, 0, -1, 2, 2, -59, 0, 26, 0},
+	{0, 0, -1, 0, 1, -58, -0.1, 32, 0},
+	{0, 0, 1, 2, 1, -51, 0, 27, 0},
+	{-2, 0, 2, 0, 0, 48, 0, 0, 0},
+	{0, 0, -2, 2, 1, 46, 0, -24, 0},
+	{2, 0, 0, 2, 2, -38, 0, 16, 0},
+	{0, 0, 2, 2, 2, -31, 0, 13, 0},
+	{0, 0, 2, 0, 0, 29, 0, 0, 0},
+	{-2, 0, 1, 2, 2, 29, 0, -12, 0},
+	{0, 0, 0, 2, 0, 26, 0, 0, 0},
+	{-2, 0, 0, 2, 0, -22, 0, 0, 0},
+	{0, 0, -1, 2, 1, 21, 0, -10, 0},
+	{0, 2, 0, 0, 0, 17, -0.1, 0, 0},
+	{2, 0, -1, 0, 1, 16, 0, -8, 0},
+	{-2, 2, 0, 2, 2, -16, 0.1, 7, 0},
+	{0, 1, 0, 0, 1, -15, 0, 9, 0},
+	{-2, 0, 1, 0, 1, -13, 0, 7, 0},
+	{0, -1, 0, 0, 1, -12, 0, 6, 0},
+	{0, 0, 2, -2, 0, 11, 0, 0, 0},
+	{2, 0, -1, 2, 1, -10, 0, 5, 0},
+	{2, 0, 1, 2, 2, -8, 0, 3, 0},
+	{0, 1, 0, 2, 2, 7, 0, -3, 0},
+	{-2, 1, 1, 0, 0, -7, 0, 0, 0},
+	{0, -1, 0, 2, 2, -7, 0, 3, 0},
+	{2, 0, 0, 2, 1, -7, 0, 3, 0},
+	{2, 0, 1, 0, 0, 6, 0, 0, 0},
+	{-2, 0, 2, 2, 2, 6, 0, -3, 0},
+	{-2, 0, 1, 2, 1, 6, 0, -3, 0},
+	{2, 0, -2, 0, 1, -6, 0, 3, 0},
+	{2, 0, 0, 0, 1, -6, 0, 3, 0},
+	{0, -1, 1, 0, 0, 5, 0, 0, 0},
+	{-2, -1, 0, 2, 1, -5, 0, 3, 0},
+	{-2, 0, 0, 0, 1, -5, 0, 3, 0},
+	{0, 0, 2, 2, 1, -5, 0, 3, 0},
+	{-2, 0, 2, 0, 1, 4, 0, 0, 0},
+	{-2, 1, 0, 2, 1, 4, 0, 0, 0},
+	{0, 0, 1, -2, 0, 4, 0, 0, 0},
+	{-1, 0, 1, 0, 0, -4, 0, 0, 0},
+	{-2, 1, 0, 0, 0, -4, 0, 0, 0},
+	{1, 0, 0, 0, 0, -4, 0, 0, 0},
+	{0, 0, 1, 2, 0, 3, 0, 0, 0},
+	{0, 0, -2, 2, 2, -3, 0, 0, 0},
+	{-1, -1, 1, 0, 0, -3, 0, 0, 0},
+	{0, 1, 1, 0, 0, -3, 0, 0, 0},
+	{0, -1, 1, 2, 2, -3, 0, 0, 0},
+	{2, -1, -1, 2, 2, -3, 0, 0, 0},
+	{0, 0, 3, 2, 2, -3, 0, 0, 0},
+	{2, -1, 0, 2, 2, -3, 0, 0, 0},
+}
+
+// Returns the nutation in longitude and obliquity, the mean obliquity of the ecliptic and the
+// longitude of the ascending node of the Moon in radians at ttt Julian centuries of TT since
+// J2000.0 (IAU 1980)
+// Reference: Meeus, J. (1998), Astronomical Algorithms, chapter 22.
+func nutation(ttt float64) (dpsi, deps, meanEps, om float64) {
+	deg := func(v float64) float64 {
+		return math.Mod(v, 360) * DEG2RAD
+	}
+	d := deg(297.85036306 + (1602961601.3280+(-6.891+0.019*ttt)*ttt)*ttt/3600)
+	m := deg(357.52772333 + (129596581.2240+(-0.577-0.012*ttt)*ttt)*ttt/3600)
+	mp := deg(134.96298139 + (1717915922.6330+(31.310+0.064*ttt)*ttt)*ttt/3600)
+	f := deg(93.27191028 + (1739527263.1370+(-13.257+0.011*ttt)*ttt)*ttt/3600)
+	om = deg(125.04452222 + (-6962890.5390+(7.455+0.008*ttt)*ttt)*ttt/3600)
+
+	// Sum the smallest terms first
+	for i := len(nutationTerms) - 1; i >= 0; i-- {
+		n := nutationTerms[i]
+		arg := n.d*d + n.m*m + n.mp*mp + n.f*f + n.om*om
+		dpsi += (n.psi + n.psiT*ttt) * math.Sin(arg)
+		deps += (n.eps + n.epsT*ttt) * math.Cos(arg)
+	}
+	dpsi *= 1e-4 * arcsec
+	deps *= 1e-4 * arcsec
+	meanEps = (84381.448 + (-46.8150+(-0.00059+0.001813*ttt)*ttt)*ttt) * arcsec
+	return
+}
+
+// Returns the equation of the equinoxes in radians at ttt Julian centuries of TT since
+// J2000.0 with the terms in the node of the Moon added in 1994
+// Reference: IERS Conventions (1996), chapter 5.
+func equationOfEquinoxes(ttt float64) float64 {
+	dpsi, _, meanEps, om := nutation(ttt)
+	return dpsi*math.Cos(meanEps) + (0.00264*math.Sin(om)+0.000063*math.Sin(2*om))*arcsec
+}
+
+// Returns the rotation from the mean equator and equinox of date into J2000 (IAU 1976)
+// Reference: Vallado, D. A. (2013), Fundamentals of Astrodynamics and Applications, 4th ed., eq. 3-88.
+func precessionMatrix(ttt float64) [][]float64 {
+	zeta := (2306.2181 + (0.30188+0.017998*ttt)*ttt) * ttt * arcsec
+	theta := (2004.3109 + (-0.42665-0.041833*ttt)*ttt) * ttt * arcsec
+	z := (2306.2181 + (1.09468+0.018203*ttt)*ttt) * ttt * arcsec
+	sZeta, cZeta := math.Sincos(zeta)
+	sTheta, cTheta := math.Sincos(theta)
+	sZ, cZ := math.Sincos(z)
+	return [][]float64{
+		{cZ*cTheta*cZeta - sZ*sZeta, sZ*cTheta*cZeta + cZ*sZeta, sTheta * cZeta},
+		{-cZ*cTheta*sZeta - sZ*cZeta, -sZ*cTheta*sZeta + cZ*cZeta, -sTheta * sZeta},
+		{-cZ * sTheta, -sZ * sTheta, cTheta},
+	}
+}
+
+// Returns the rotation from the true equator and equinox of date into the mean equator and
+// equinox of date
+func nutationMatrix(dpsi, deps, meanEps float64) [][]float64 {
+	sPsi, cPsi := math.Sincos(dpsi)
+	sEps, cEps := math.Sincos(meanEps)
+	sTrue, cTrue := math.Sincos(meanEps + deps)
+	return [][]float64{
+		{cPsi, cTrue * sPsi, sTrue * sPsi},
+		{-cEps * sPsi, cTrue*cEps*cPsi + sTrue*sEps, sTrue*cEps*cPsi - sEps*cTrue},
+		{-sEps * sPsi, cTrue*sEps*cPsi - sTrue*cEps, sTrue*sEps*cPsi + cTrue*cEps},
+	}
+}
+
+// Returns the rotation from TEME into J2000 at a UTC time. TEME differs from the true equator
+// and equinox of date by the equation of the equinoxes without the terms added in 1994.
+func temeToJ2000Matrix(t time.Time) [][]float64 {
+	ttt := NewInstant(t).CenturiesTT()
+	dpsi, deps, meanEps, _ := nutation(ttt)
+	sEq, cEq := math.Sincos(dpsi * math.Cos(meanEps))
+	eqe := [][]float64{
+		{cEq, -sEq, 0},
+		{sEq, cEq, 0},
+		{0, 0, 1},
+	}
+	return mulMat(precessionMatrix(ttt), mulMat(nutationMatrix(dpsi, deps, meanEps), eqe))
+}
+
+// Returns the vector rotated by the matrix
+func rotateVector(m [][]float64, v Vector3) Vector3 {
+	r := mulMatVec(m, []float64{v.X, v.Y, v.Z})
+	return Vector3{X: r[0], Y: r[1], Z: r[2]}
+}
+
+// Converts a TEME position and velocity, the frame of SGP4, at a UTC time into the J2000 mean
+// equator and equinox frame with IAU 1976 precession and IAU 1980 nutation. The result agrees
+// with the full nutation series within 0.005 arcseconds, about 0.2 m at the distance of a low
+// orbit, and with GCRF within the 0.02 arcsecond frame bias and the unmodelled celestial pole
+// offsets of up to 0.1 arcseconds, a few meters in low orbit. TT is taken from the leap second
+// table, see AddLeapSecond.
+// Reference: Vallado, D. A., Crawford, P., Hujsak, R. and Kelso, T. S. (2006), Revisiting
+// Spacetrack Report #3, AIAA 2006-6753, appendix C.
+func TEMEToJ2000(pos, vel Vector3, t time.Time) (j2000Pos, j2000Vel Vector3) {
+	m := temeToJ2000Matrix(t)
+	return rotateVector(m, pos), rotateVector(m, vel)
+}
+
+// Converts a J2000 position and velocity at a UTC time into TEME, the inverse of TEMEToJ2000
+func J2000ToTEME(pos, vel Vector3, t time.Time) (temePos, temeVel Vector3) {
+	m := transpose(temeToJ2000Matrix(t))
+	return rotateVector(m, pos), rotateVector(m, vel)
+}
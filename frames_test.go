@@ -0,0 +1,72 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("nutation", func() {
+	It("should match Meeus example 22.a", func() {
+		dpsi, deps, meanEps, _ := nutation((2446895.5 - 2451545.0) / 36525.0)
+		Expect(dpsi / arcsec).To(BeNumerically("~", -3.788, 0.001))
+		Expect(deps / arcsec).To(BeNumerically("~", 9.443, 0.001))
+		Expect(meanEps / arcsec).To(BeNumerically("~", 84387.407, 0.001))
+	})
+})
+
+var _ = Describe("TEMEToJ2000", func() {
+	// Vallado et al. (2006), Revisiting Spacetrack Report #3, example of appendix C
+	t := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+	teme := Vector3{5094.18016210, 6127.64465950, 6380.34453270}
+	temeVel := Vector3{-4.746131487, 0.785818041, 5.531931288}
+
+	It("should match the J2000 state of Vallado", func() {
+		pos, vel := TEMEToJ2000(teme, temeVel, t)
+		Expect(distance(pos, Vector3{5102.5096, 6123.01152, 6378.1363})).To(BeNumerically("<", 1e-3))
+		Expect(distance(vel, Vector3{-4.7432196, 0.7905366, 5.5337561})).To(BeNumerically("<", 1e-6))
+	})
+
+	It("should be undone by J2000ToTEME", func() {
+		j2000, j2000Vel := TEMEToJ2000(teme, temeVel, t)
+		pos, vel := J2000ToTEME(j2000, j2000Vel, t)
+		Expect(distance(pos, teme)).To(BeNumerically("<", 1e-8))
+		Expect(distance(vel, temeVel)).To(BeNumerically("<", 1e-11))
+	})
+})
+
+var _ = Describe("GAST", func() {
+	It("should match Meeus example 12.a", func() {
+		// 1987 April 10, 0h UT
+		jd := JDay{2446895.5, 0}
+		seconds := func(angle float64) float64 {
+			return angle / TWOPI * 86400
+		}
+		Expect(seconds(gstime(jd))).To(BeNumerically("~", 13*3600+10*60+46.3668, 1e-3))
+		Expect(seconds(GAST(jd))).To(BeNumerically("~", 13*3600+10*60+46.1351, 1e-3))
+		Expect(seconds(EquationOfEquinoxes(jd))).To(BeNumerically("~", -0.2317, 1e-3))
+	})
+
+	It("should follow the time scales of an instant", func() {
+		i := Instant{UTC: time.Date(1987, 4, 10, 0, 0, 0, 0, time.UTC)}
+		Expect(i.GAST()).To(BeNumerically("~", GAST(i.JDayUT1()), 1e-9))
+		Expect(math.Remainder(i.GAST()-i.GMST(), TWOPI)).To(BeNumerically("~", EquationOfEquinoxes(i.JDayTT()), 1e-15))
+	})
+})
+
+var _ = Describe("LocalSiderealTime", func() {
+	It("should add the longitude to GMST within a turn", func() {
+		jd := JDay{2446895.5, 0}
+		gmst := gstime(jd)
+		Expect(LocalSiderealTime(jd, 0)).To(Equal(Radians(gmst)))
+		Expect(LocalSiderealTime(jd, Degrees(12.65).Radians())).To(BeNumerically("~", gmst+12.65*DEG2RAD, 1e-12))
+		for _, lon := range []Radians{-math.Pi, -3, -1, 1, 3, math.Pi, 4 * math.Pi} {
+			lst := LocalSiderealTime(jd, lon)
+			Expect(lst).To(BeNumerically(">=", 0))
+			Expect(lst).To(BeNumerically("<", TWOPI))
+			Expect(math.Remainder(float64(lst-lon)-gmst, TWOPI)).To(BeNumerically("~", 0, 1e-12))
+		}
+	})
+})
@@ -0,0 +1,106 @@
+package satellite
+
+import "math"
+
+// Mean radius of the WGS84 ellipsoid in km, used for spherical distances
+const meanEarthRadius = 6371.0088
+
+// WGS84 flattening used by the ellipsoidal distances
+const wgs84F = 1 / 298.257223563
+
+// Returns the distance in km along the WGS84 ellipsoid between two points in radians.
+// Reference: Vincenty, T. (1975), Direct and inverse solutions of geodesics on the ellipsoid,
+// Survey Review 23(176). Nearly antipodal points where the iteration does not converge fall
+// back to the spherical distance.
+func (ll LatLong) DistanceTo(other LatLong) float64 {
+	d, _, ok := vincentyInverse(ll, other)
+	if !ok {
+		return ll.SphericalDistanceTo(other)
+	}
+	return d
+}
+
+// Returns the great circle distance in km between two points in radians on a sphere of
+// the mean earth radius. It is faster than DistanceTo and within 0.5% of it.
+func (ll LatLong) SphericalDistanceTo(other LatLong) float64 {
+	return centralAngle(ll, other) * meanEarthRadius
+}
+
+// Returns the initial bearing in radians clockwise from north, in the range 0 to 2pi, of the
+// shortest path on the WGS84 ellipsoid from the point to other
+func (ll LatLong) BearingTo(other LatLong) float64 {
+	_, bearing, ok := vincentyInverse(ll, other)
+	if !ok {
+		sinLat1, cosLat1 := math.Sincos(ll.Latitude)
+		sinLat2, cosLat2 := math.Sincos(other.Latitude)
+		sinLon, cosLon := math.Sincos(other.Longitude - ll.Longitude)
+		bearing = math.Atan2(sinLon*cosLat2, cosLat1*sinLat2-sinLat1*cosLat2*cosLon)
+	}
+	if bearing < 0 {
+		bearing += TWOPI
+	}
+	return bearing
+}
+
+// Returns the central angle in radians between two points on a sphere
+func centralAngle(a, b LatLong) float64 {
+	sinLat := math.Sin((b.Latitude - a.Latitude) / 2)
+	sinLon := math.Sin((b.Longitude - a.Longitude) / 2)
+	h := sinLat*sinLat + math.Cos(a.Latitude)*math.Cos(b.Latitude)*sinLon*sinLon
+	return 2 * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Solves the inverse geodesic problem on the WGS84 ellipsoid, returning the distance in km
+// and the initial azimuth in radians. Ok is false when the iteration does not converge.
+func vincentyInverse(p1, p2 LatLong) (dist, azimuth float64, ok bool) {
+	const a, f = wgs84A, wgs84F
+	b := a * (1 - f)
+
+	l := wrapPi(p2.Longitude - p1.Longitude)
+	u1 := math.Atan((1 - f) * math.Tan(p1.Latitude))
+	u2 := math.Atan((1 - f) * math.Tan(p2.Latitude))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM, sinLambda, cosLambda float64
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda = math.Sincos(lambda)
+		x := cosU2 * sinLambda
+		y := cosU1*sinU2 - sinU1*cosU2*cosLambda
+		sinSigma = math.Sqrt(x*x + y*y)
+		if sinSigma == 0 {
+			// Coincident points
+			return 0, 0, true
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			// Zero on the equator
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		c := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = l + (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return 0, 0, false
+	}
+
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	k1 := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	k2 := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := k2 * sinSigma * (cos2SigmaM + k2/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		k2/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	dist = b * k1 * (sigma - deltaSigma)
+	azimuth = math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+	return dist, azimuth, true
+}
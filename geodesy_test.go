@@ -0,0 +1,36 @@
+package satellite
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DistanceTo", func() {
+	dms := func(d, m, s float64) float64 {
+		return math.Copysign(math.Abs(d)+m/60+s/3600, d) * DEG2RAD
+	}
+	// Vincenty's example line from Flinders Peak to Buninyong
+	flinders := LatLong{dms(-37, 57, 3.72030), dms(144, 25, 29.52440)}
+	buninyong := LatLong{dms(-37, 39, 10.15610), dms(143, 55, 35.38390)}
+
+	It("should match the ellipsoidal distance and bearing of the reference line", func() {
+		Expect(flinders.DistanceTo(buninyong)).To(BeNumerically("~", 54.972271, 1e-6))
+		Expect(flinders.BearingTo(buninyong)).To(BeNumerically("~", dms(306, 52, 5.37), 1e-7))
+		Expect(flinders.SphericalDistanceTo(buninyong)).To(BeNumerically("~", 54.972271, 0.3))
+	})
+
+	It("should handle coincident, antimeridian and nearly antipodal points", func() {
+		Expect(flinders.DistanceTo(flinders)).To(Equal(0.0))
+
+		east, west := LatLong{0, 179.5 * DEG2RAD}, LatLong{0, -179.5 * DEG2RAD}
+		Expect(east.DistanceTo(west)).To(BeNumerically("~", 111.319, 1e-3))
+		Expect(east.BearingTo(west)).To(BeNumerically("~", math.Pi/2, 1e-9))
+		Expect(west.BearingTo(east)).To(BeNumerically("~", 3*math.Pi/2, 1e-9))
+
+		// Vincenty does not converge here and the spherical distance is returned
+		antipode := LatLong{0.5 * DEG2RAD, 179.7 * DEG2RAD}
+		Expect(LatLong{}.DistanceTo(antipode)).To(Equal(LatLong{}.SphericalDistanceTo(antipode)))
+	})
+})
@@ -0,0 +1,282 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// GeoJSON feature collection, see RFC 7946. It marshals with encoding/json.
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSON feature with a geometry and free form properties
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   GeoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSON geometry. Coordinates are longitude and latitude pairs in degrees nested as the
+// geometry type requires.
+type GeoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// Number of points on the ring of a footprint polygon
+const footprintPoints = 72
+
+func newFeatureCollection(features []GeoJSONFeature) GeoJSONFeatureCollection {
+	if features == nil {
+		features = []GeoJSONFeature{}
+	}
+	return GeoJSONFeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+func newFeature(kind string, coordinates interface{}, properties map[string]interface{}) GeoJSONFeature {
+	return GeoJSONFeature{
+		Type:       "Feature",
+		Geometry:   GeoJSONGeometry{Type: kind, Coordinates: coordinates},
+		Properties: properties,
+	}
+}
+
+// Returns the ground track of the satellite or ephemeris sampled every step between start and
+// stop, 30 seconds when zero, as LineString features split at the antimeridian. Each feature
+// carries the catalog number and the times of its first and last sample.
+func GroundTrackGeoJSON(sat Propagator, start, stop time.Time, step time.Duration) (GeoJSONFeatureCollection, error) {
+	if !stop.After(start) {
+		return GeoJSONFeatureCollection{}, errors.New("ground track stop time must be after start time")
+	}
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+	track, err := groundTrack(sat, sampleTimes(start, stop, step))
+	if err != nil {
+		return GeoJSONFeatureCollection{}, err
+	}
+	var features []GeoJSONFeature
+	for _, line := range track.lines() {
+		features = append(features, newFeature("LineString", line.coordinates(), map[string]interface{}{
+			"satnum": sat.CatalogNumber(),
+			"start":  line[0].Time,
+			"stop":   line[len(line)-1].Time,
+		}))
+	}
+	return newFeatureCollection(features), nil
+}
+
+// Returns the footprint at t of a nadir pointing sensor with the half angle in radians as a
+// Polygon feature, or a MultiPolygon split at the antimeridian. A zero half angle gives the
+// area from which the satellite is above the horizon. Footprints around a pole are closed
+// along the pole.
+func FootprintGeoJSON(sat Propagator, t time.Time, halfAngle float64) (GeoJSONFeatureCollection, error) {
+	sub, err := subSatellitePoint(sat, t)
+	if err != nil {
+		return GeoJSONFeatureCollection{}, err
+	}
+	radius := gravityOf(sat).radiusearthkm
+	angle := math.Acos(radius / (radius + sub.AltitudeKm))
+	if halfAngle > 0 {
+		angle = footprintAngle(sub.AltitudeKm, halfAngle, radius)
+	}
+
+	var polygons [][][][2]float64
+	ring := footprintRing(sub.LatLong, angle, footprintPoints)
+	for k := -2.0; k <= 2; k++ {
+		part := clipLongitude(clipLongitude(ring, 360*k-180, false), 360*k+180, true)
+		if len(part) < 3 {
+			continue
+		}
+		for i := range part {
+			part[i][0] -= 360 * k
+		}
+		polygons = append(polygons, [][][2]float64{append(part, part[0])})
+	}
+	properties := map[string]interface{}{
+		"satnum":    sat.CatalogNumber(),
+		"time":      t,
+		"halfAngle": halfAngle * RAD2DEG,
+	}
+	if len(polygons) == 1 {
+		return newFeatureCollection([]GeoJSONFeature{newFeature("Polygon", polygons[0], properties)}), nil
+	}
+	return newFeatureCollection([]GeoJSONFeature{newFeature("MultiPolygon", polygons, properties)}), nil
+}
+
+// Returns the pass geometry: the ground track from AOS to LOS at the track step of the pass as
+// LineString features split at the antimeridian, Point features at the sub-satellite points
+// at AOS and LOS and a Point feature at the observer
+func (p *Pass) GeoJSON() (GeoJSONFeatureCollection, error) {
+	if p.track == nil {
+		return GeoJSONFeatureCollection{}, errors.New("pass has no satellite to compute the geometry from")
+	}
+	sat := p.track.sat
+	track, err := groundTrack(sat, sampleTimes(p.AOS, p.LOS, p.track.step))
+	if err != nil {
+		return GeoJSONFeatureCollection{}, err
+	}
+	var features []GeoJSONFeature
+	for _, line := range track.lines() {
+		features = append(features, newFeature("LineString", line.coordinates(), map[string]interface{}{
+			"satnum": p.Satnum,
+			"start":  line[0].Time,
+			"stop":   line[len(line)-1].Time,
+		}))
+	}
+	for _, event := range []struct {
+		name    string
+		point   trackSample
+		azimuth float64
+	}{
+		{"AOS", track[0], p.AOSAzimuth},
+		{"LOS", track[len(track)-1], p.LOSAzimuth},
+	} {
+		features = append(features, newFeature("Point", event.point.coordinate(), map[string]interface{}{
+			"satnum":  p.Satnum,
+			"event":   event.name,
+			"time":    event.point.Time,
+			"azimuth": event.azimuth * RAD2DEG,
+		}))
+	}
+	obs := p.track.obs.LatLong
+	features = append(features, newFeature("Point", [2]float64{obs.Longitude * RAD2DEG, obs.Latitude * RAD2DEG}, map[string]interface{}{
+		"event":        "observer",
+		"maxElevation": p.MaxElevation * RAD2DEG,
+	}))
+	return newFeatureCollection(features), nil
+}
+
+// Sub-satellite point in radians at a moment of a ground track
+type trackSample struct {
+	Time time.Time
+	LatLongAlt
+}
+
+// Returns the longitude and latitude in degrees
+func (s trackSample) coordinate() [2]float64 {
+	return [2]float64{s.LatLong.Longitude * RAD2DEG, s.LatLong.Latitude * RAD2DEG}
+}
+
+// Sub-satellite points in time order
+type groundTrackSamples []trackSample
+
+// Returns the geodetic sub-satellite point of the satellite at t with the longitude in [-pi, pi)
+func subSatellitePoint(sat Propagator, t time.Time) (LatLongAlt, error) {
+	pos, _, err := stateECEF(sat, t)
+	if err != nil {
+		return LatLongAlt{}, err
+	}
+	alt, _, ll := ECIToLLA(pos, 0)
+	return LatLongAlt{LatLong: LatLong{Latitude: ll.Latitude, Longitude: wrapPi(ll.Longitude)}, AltitudeKm: alt}, nil
+}
+
+// Returns the sub-satellite points at the times
+func groundTrack(sat Propagator, times []time.Time) (groundTrackSamples, error) {
+	track := make(groundTrackSamples, 0, len(times))
+	for _, t := range times {
+		sub, err := subSatellitePoint(sat, t)
+		if err != nil {
+			return track, err
+		}
+		track = append(track, trackSample{Time: t, LatLongAlt: sub})
+	}
+	return track, nil
+}
+
+// Splits the track where it crosses the antimeridian. Both lines get a point on the
+// antimeridian interpolated between the samples around the crossing.
+func (track groundTrackSamples) lines() []groundTrackSamples {
+	var lines []groundTrackSamples
+	var line groundTrackSamples
+	for i, s := range track {
+		if i > 0 {
+			prev := track[i-1]
+			if d := s.LatLong.Longitude - prev.LatLong.Longitude; math.Abs(d) > math.Pi {
+				edge := math.Copysign(math.Pi, prev.LatLong.Longitude)
+				frac := (math.Pi - math.Abs(prev.LatLong.Longitude)) / (TWOPI - math.Abs(d))
+				at := trackSample{
+					Time: prev.Time.Add(time.Duration(frac * float64(s.Time.Sub(prev.Time)))),
+					LatLongAlt: LatLongAlt{
+						LatLong:    LatLong{Latitude: prev.LatLong.Latitude + frac*(s.LatLong.Latitude-prev.LatLong.Latitude), Longitude: edge},
+						AltitudeKm: prev.AltitudeKm + frac*(s.AltitudeKm-prev.AltitudeKm),
+					},
+				}
+				lines = append(lines, append(line, at))
+				at.LatLong.Longitude = -edge
+				line = groundTrackSamples{at}
+			}
+		}
+		line = append(line, s)
+	}
+	if len(line) > 1 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Returns the longitude and latitude pairs of the samples in degrees
+func (track groundTrackSamples) coordinates() [][2]float64 {
+	coords := make([][2]float64, len(track))
+	for i, s := range track {
+		coords[i] = s.coordinate()
+	}
+	return coords
+}
+
+// Returns n points in degrees, counterclockwise, on the circle of the central angle around the
+// center on a sphere. Longitudes are continuous and may leave [-180, 180]; a circle around a pole
+// is closed along the pole.
+func footprintRing(center LatLong, angle float64, n int) [][2]float64 {
+	sinLat, cosLat := math.Sincos(center.Latitude)
+	sinA, cosA := math.Sincos(angle)
+	ring := make([][2]float64, 0, n+3)
+	first, prev := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		sinB, cosB := math.Sincos(-TWOPI * float64(i) / float64(n))
+		lat := math.Asin(sinLat*cosA + cosLat*sinA*cosB)
+		dLon := math.Atan2(sinB*sinA*cosLat, cosA-sinLat*math.Sin(lat))
+		if i == 0 {
+			first = dLon
+		} else {
+			dLon = prev + math.Remainder(dLon-prev, TWOPI)
+		}
+		prev = dLon
+		ring = append(ring, [2]float64{(center.Longitude + dLon) * RAD2DEG, lat * RAD2DEG})
+	}
+	if wind := prev + math.Remainder(first-prev, TWOPI) - first; math.Abs(wind) > math.Pi {
+		pole := math.Copysign(90, center.Latitude)
+		end := ring[0][0] + wind*RAD2DEG
+		ring = append(ring, [2]float64{end, ring[0][1]}, [2]float64{end, pole}, [2]float64{ring[0][0], pole})
+	}
+	return ring
+}
+
+// Clips a ring in degrees to the longitudes below or above the bound
+func clipLongitude(ring [][2]float64, bound float64, below bool) [][2]float64 {
+	inside := func(p [2]float64) bool {
+		if below {
+			return p[0] <= bound
+		}
+		return p[0] >= bound
+	}
+	crossing := func(a, b [2]float64) [2]float64 {
+		return [2]float64{bound, a[1] + (b[1]-a[1])*(bound-a[0])/(b[0]-a[0])}
+	}
+	var out [][2]float64
+	for i, cur := range ring {
+		prev := ring[(i+len(ring)-1)%len(ring)]
+		switch {
+		case inside(cur) && !inside(prev):
+			out = append(out, crossing(prev, cur), cur)
+		case inside(cur):
+			out = append(out, cur)
+		case inside(prev):
+			out = append(out, crossing(prev, cur))
+		}
+	}
+	return out
+}
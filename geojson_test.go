@@ -0,0 +1,192 @@
+package satellite
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GroundTrackGeoJSON", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	It("should split the ground track at the antimeridian", func() {
+		sat := jobTestSatellites()[0]
+		fc, err := GroundTrackGeoJSON(&sat, start, start.Add(6*time.Hour), time.Minute)
+		Expect(err).To(BeNil())
+		Expect(fc.Type).To(Equal("FeatureCollection"))
+		// Three of the revolutions relative to the rotating Earth cross the antimeridian
+		Expect(fc.Features).To(HaveLen(4))
+		for i, f := range fc.Features {
+			Expect(f.Geometry.Type).To(Equal("LineString"))
+			coords := f.Geometry.Coordinates.([][2]float64)
+			for j := 1; j < len(coords); j++ {
+				Expect(math.Abs(coords[j][0] - coords[j-1][0])).To(BeNumerically("<", 180))
+			}
+			if i > 0 {
+				prev := fc.Features[i-1].Geometry.Coordinates.([][2]float64)
+				last := prev[len(prev)-1]
+				Expect(math.Abs(last[0])).To(Equal(180.0))
+				Expect(coords[0]).To(Equal([2]float64{-last[0], last[1]}))
+				Expect(f.Properties["start"]).To(Equal(fc.Features[i-1].Properties["stop"]))
+			}
+		}
+		Expect(fc.Features[0].Properties["start"]).To(Equal(start))
+		Expect(fc.Features[3].Properties["stop"]).To(Equal(start.Add(6 * time.Hour)))
+	})
+
+	It("should marshal to GeoJSON", func() {
+		sat := jobTestSatellites()[0]
+		fc, err := GroundTrackGeoJSON(&sat, start, start.Add(10*time.Minute), 0)
+		Expect(err).To(BeNil())
+		data, err := json.Marshal(fc)
+		Expect(err).To(BeNil())
+
+		var decoded struct {
+			Type     string
+			Features []struct {
+				Type     string
+				Geometry struct {
+					Type        string
+					Coordinates [][]float64
+				}
+				Properties map[string]interface{}
+			}
+		}
+		Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded.Type).To(Equal("FeatureCollection"))
+		Expect(decoded.Features).To(HaveLen(1))
+		Expect(decoded.Features[0].Type).To(Equal("Feature"))
+		Expect(decoded.Features[0].Geometry.Coordinates).To(HaveLen(21))
+		Expect(decoded.Features[0].Properties["satnum"]).To(Equal(25544.0))
+		Expect(decoded.Features[0].Properties["start"]).To(Equal("2008-09-20T00:00:00Z"))
+	})
+
+	It("should reject an empty range", func() {
+		sat := jobTestSatellites()[0]
+		_, err := GroundTrackGeoJSON(&sat, start, start, 0)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("FootprintGeoJSON", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	// Returns the first time from start at which the sub-satellite point satisfies ok
+	findTime := func(sat Propagator, ok func(LatLongAlt) bool) time.Time {
+		for t := start; t.Before(start.Add(24 * time.Hour)); t = t.Add(time.Minute) {
+			sub, err := subSatellitePoint(sat, t)
+			Expect(err).To(BeNil())
+			if ok(sub) {
+				return t
+			}
+		}
+		Fail("no matching sub-satellite point")
+		return start
+	}
+
+	It("should put the ring at the horizon angle around the sub-satellite point", func() {
+		sat := jobTestSatellites()[0]
+		t := findTime(&sat, func(sub LatLongAlt) bool { return math.Abs(sub.LatLong.Longitude) < 2 })
+		sub, err := subSatellitePoint(&sat, t)
+		Expect(err).To(BeNil())
+		fc, err := FootprintGeoJSON(&sat, t, 0)
+		Expect(err).To(BeNil())
+		Expect(fc.Features).To(HaveLen(1))
+		Expect(fc.Features[0].Geometry.Type).To(Equal("Polygon"))
+
+		ring := fc.Features[0].Geometry.Coordinates.([][][2]float64)[0]
+		Expect(ring).To(HaveLen(footprintPoints + 1))
+		Expect(ring[0]).To(Equal(ring[len(ring)-1]))
+		horizon := math.Acos(sat.Gravity.radiusearthkm / (sat.Gravity.radiusearthkm + sub.AltitudeKm))
+		for _, pt := range ring {
+			angle := centralAngle(sub.LatLong, LatLong{Latitude: pt[1] * DEG2RAD, Longitude: pt[0] * DEG2RAD})
+			Expect(angle).To(BeNumerically("~", horizon, 1e-9))
+		}
+
+		// Counterclockwise rings have a positive signed area
+		area := 0.0
+		for i := 1; i < len(ring); i++ {
+			area += ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]
+		}
+		Expect(area).To(BeNumerically(">", 0))
+
+		sensor, err := FootprintGeoJSON(&sat, t, 20*DEG2RAD)
+		Expect(err).To(BeNil())
+		pt := sensor.Features[0].Geometry.Coordinates.([][][2]float64)[0][0]
+		angle := centralAngle(sub.LatLong, LatLong{Latitude: pt[1] * DEG2RAD, Longitude: pt[0] * DEG2RAD})
+		Expect(angle).To(BeNumerically("~", footprintAngle(sub.AltitudeKm, 20*DEG2RAD, sat.Gravity.radiusearthkm), 1e-9))
+	})
+
+	It("should split footprints crossing the antimeridian", func() {
+		sat := jobTestSatellites()[0]
+		t := findTime(&sat, func(sub LatLongAlt) bool { return math.Abs(sub.LatLong.Longitude) > 175*DEG2RAD })
+		fc, err := FootprintGeoJSON(&sat, t, 0)
+		Expect(err).To(BeNil())
+		Expect(fc.Features[0].Geometry.Type).To(Equal("MultiPolygon"))
+		polygons := fc.Features[0].Geometry.Coordinates.([][][][2]float64)
+		Expect(polygons).To(HaveLen(2))
+		for _, p := range polygons {
+			for _, pt := range p[0] {
+				Expect(pt[0]).To(BeNumerically(">=", -180))
+				Expect(pt[0]).To(BeNumerically("<=", 180))
+			}
+		}
+	})
+
+	It("should close footprints around a pole along the pole", func() {
+		sat := jobTestSatellites()[3]
+		t := findTime(&sat, func(sub LatLongAlt) bool { return sub.LatLong.Latitude > 75*DEG2RAD })
+		fc, err := FootprintGeoJSON(&sat, t, 0)
+		Expect(err).To(BeNil())
+		var lats []float64
+		var polygons [][][][2]float64
+		if fc.Features[0].Geometry.Type == "Polygon" {
+			polygons = [][][][2]float64{fc.Features[0].Geometry.Coordinates.([][][2]float64)}
+		} else {
+			polygons = fc.Features[0].Geometry.Coordinates.([][][][2]float64)
+		}
+		minLon, maxLon := 180.0, -180.0
+		for _, p := range polygons {
+			for _, pt := range p[0] {
+				lats = append(lats, pt[1])
+				minLon, maxLon = math.Min(minLon, pt[0]), math.Max(maxLon, pt[0])
+			}
+		}
+		Expect(lats).To(ContainElement(90.0))
+		Expect(minLon).To(Equal(-180.0))
+		Expect(maxLon).To(Equal(180.0))
+	})
+})
+
+var _ = Describe("Pass.GeoJSON", func() {
+	It("should mark AOS, LOS and the observer", func() {
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+		sat := jobTestSatellites()[0]
+		passes, err := NextPasses(&sat, copenhagen, start, 1, PassOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+		p := passes[0]
+
+		fc, err := p.GeoJSON()
+		Expect(err).To(BeNil())
+		Expect(fc.Features).To(HaveLen(4))
+		Expect(fc.Features[0].Geometry.Type).To(Equal("LineString"))
+		line := fc.Features[0].Geometry.Coordinates.([][2]float64)
+
+		aos, los, obs := fc.Features[1], fc.Features[2], fc.Features[3]
+		Expect(aos.Properties["event"]).To(Equal("AOS"))
+		Expect(aos.Properties["time"]).To(Equal(p.AOS))
+		Expect(aos.Geometry.Coordinates).To(Equal(line[0]))
+		Expect(los.Properties["event"]).To(Equal("LOS"))
+		Expect(los.Properties["time"]).To(Equal(p.LOS))
+		Expect(los.Geometry.Coordinates).To(Equal(line[len(line)-1]))
+		Expect(obs.Geometry.Coordinates.([2]float64)[0]).To(BeNumerically("~", 12.65, 1e-9))
+		Expect(obs.Geometry.Coordinates.([2]float64)[1]).To(BeNumerically("~", 55.6167, 1e-9))
+
+		_, err = (&Pass{}).GeoJSON()
+		Expect(err).NotTo(BeNil())
+	})
+})
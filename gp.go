@@ -0,0 +1,92 @@
+package satellite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// General perturbations element set with the field names of the CCSDS Orbit Mean-Elements
+// Message, as published by CelesTrak and Space-Track in OMM based formats. Angles are in
+// degrees and mean motion in revolutions per day, as in a TLE.
+type GPElements struct {
+	ObjectName string
+
+	// International designator such as 1998-067A
+	ObjectID string
+
+	NoradCatID int64
+	Epoch      time.Time
+
+	MeanMotion      float64
+	Eccentricity    float64
+	Inclination     float64
+	RAOfAscNode     float64
+	ArgOfPericenter float64
+	MeanAnomaly     float64
+
+	EphemerisType      int
+	ClassificationType string
+	ElementSetNo       int64
+	RevAtEpoch         int64
+
+	// Provider of a supplemental (SupGP) element set derived from operator ephemerides,
+	// empty for standard GP elements
+	Source string
+
+	// Drag term in 1/earth radii, and the first derivative of the mean motion divided by
+	// two in rev/day² and the second derivative divided by six in rev/day³
+	Bstar          float64
+	MeanMotionDot  float64
+	MeanMotionDDot float64
+}
+
+// Parses an OMM epoch, UTC without a zone designator in calendar or day of year form
+func parseGPEpoch(s string) (time.Time, error) {
+	t, err := parseOEMTime(strings.TrimSpace(s))
+	if err != nil {
+		return t, fmt.Errorf("Error on parsing epoch %q: %v", s, err)
+	}
+	return t, nil
+}
+
+// Converts a general perturbations element set into a Satellite struct and runs sgp4init.
+// The epoch keeps its full year. Line1 and Line2 are left empty. Element sets of
+// ephemeris type 4 are fitted for SGP4-XP and are refused.
+func NewSatFromGP(gp GPElements, gravconst GravModel) (Satellite, error) {
+	var sat Satellite
+	if gp.EphemerisType == 4 {
+		return sat, newError(ErrUnsupportedEphemeris, "element set of %d has ephemeris type 4 (SGP4-XP)", gp.NoradCatID)
+	}
+	var err error
+	sat.Gravity, err = getGravConst(gravconst)
+	if err != nil {
+		return sat, fmt.Errorf("Error on getting gravconst: %w", err)
+	}
+
+	sat.Satnum = gp.NoradCatID
+	sat.Name = gp.ObjectName
+	sat.source = gp.Source
+	sat.classification = gp.ClassificationType
+	sat.designator = tleDesignator(gp.ObjectID)
+	sat.ephemerisType = gp.EphemerisType
+	sat.elementSetNo = gp.ElementSetNo
+	sat.revNumber = gp.RevAtEpoch
+	epoch := gp.Epoch.UTC()
+	year := epoch.Year()
+	sat.epochyr = int64(year % 100)
+	sat.epochdays = float64(epoch.YearDay()) + epoch.Sub(epoch.Truncate(24*time.Hour)).Hours()/24
+
+	sat.no = gp.MeanMotion
+	sat.ndot = gp.MeanMotionDot
+	sat.nddot = gp.MeanMotionDDot
+	sat.bstar = gp.Bstar
+	sat.ecco = gp.Eccentricity
+	sat.inclo = gp.Inclination
+	sat.nodeo = gp.RAOfAscNode
+	sat.argpo = gp.ArgOfPericenter
+	sat.mo = gp.MeanAnomaly
+
+	err = sat.initialize(int64(year))
+	return sat, err
+}
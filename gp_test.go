@@ -0,0 +1,140 @@
+package satellite
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const gpCSV = `OBJECT_NAME,OBJECT_ID,EPOCH,MEAN_MOTION,ECCENTRICITY,INCLINATION,RA_OF_ASC_NODE,ARG_OF_PERICENTER,MEAN_ANOMALY,EPHEMERIS_TYPE,CLASSIFICATION_TYPE,NORAD_CAT_ID,ELEMENT_SET_NO,REV_AT_EPOCH,BSTAR,MEAN_MOTION_DOT,MEAN_MOTION_DDOT
+"ISS (ZARYA)",1998-067A,2008-09-20T12:25:40.104192,15.72125391,.0006703,51.6416,247.4627,130.536,325.0288,0,U,25544,292,56353,-.11606E-4,-.2182E-4,0
+"NOAA 19, N-PRIME",2009-005A,2008-09-20T11:45:27.556992,14.12079902,.0013054,99.0394,120.216,232.8317,127.1662,0,U,33591,999,37833,.66998E-4,.77E-6,0
+`
+
+var _ = Describe("GP elements", func() {
+	It("should initialize the same satellite as the TLE", func() {
+		gps, err := ReadGPCSV(strings.NewReader(gpCSV))
+		Expect(err).To(BeNil())
+		Expect(gps).To(HaveLen(2))
+		Expect(gps[1].ObjectName).To(Equal("NOAA 19, N-PRIME"))
+		Expect(gps[0].ObjectID).To(Equal("1998-067A"))
+		Expect(gps[0].ElementSetNo).To(Equal(int64(292)))
+		Expect(gps[0].RevAtEpoch).To(Equal(int64(56353)))
+		Expect(gps[0].ClassificationType).To(Equal("U"))
+
+		sats := jobTestSatellites()
+		for i, tle := range []Satellite{sats[0], sats[3]} {
+			gp := gps[i]
+			sat, err := NewSatFromGP(gp, "wgs72")
+			Expect(err).To(BeNil())
+			Expect(sat.Satnum).To(Equal(tle.Satnum))
+			Expect(sat.jdsatepoch.Single()).To(BeNumerically("~", tle.jdsatepoch.Single(), 1e-8))
+			for _, dt := range []time.Duration{0, 6 * time.Hour, 72 * time.Hour} {
+				t := tle.jdsatepoch.ToTime().Add(dt)
+				want, _, _ := tle.propagateAt(t)
+				got, _, err := sat.propagateAt(t)
+				Expect(err).To(BeNil())
+				Expect(distance(want, got)).To(BeNumerically("<", 1e-3))
+			}
+		}
+	})
+
+	It("should keep full epoch years beyond the two digit pivot", func() {
+		gps, err := ReadGPCSV(strings.NewReader(gpCSV))
+		Expect(err).To(BeNil())
+		gps[0].Epoch = time.Date(2061, 3, 1, 0, 0, 0, 0, time.UTC)
+		sat, err := NewSatFromGP(gps[0], "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sat.jdsatepoch.ToTime()).To(BeTemporally("~", gps[0].Epoch, time.Millisecond))
+	})
+
+	It("should refuse SGP4-XP element sets", func() {
+		gps, err := ReadGPCSV(strings.NewReader(gpCSV))
+		Expect(err).To(BeNil())
+		gps[0].EphemerisType = 4
+		_, err = NewSatFromGP(gps[0], "wgs72")
+		Expect(errors.Is(err, ErrUnsupportedEphemeris)).To(BeTrue())
+	})
+
+	It("should map columns by header and convert units", func() {
+		csv := "NORAD_CAT_ID,MEAN_ANOMALY [rad],inclination [rad],RA_OF_ASC_NODE,ARG_OF_PERICENTER,ECCENTRICITY,MEAN_MOTION [rad/min],EPOCH,EXTRA\n" +
+			"25544,1, 0.9,247.4627,130.536,0.0006703,0.0686,2008-09-20T12:25:40.104192Z,x\n"
+		gps, err := ReadGPCSV(strings.NewReader(csv))
+		Expect(err).To(BeNil())
+		Expect(gps).To(HaveLen(1))
+		Expect(gps[0].MeanAnomaly).To(BeNumerically("~", RAD2DEG, 1e-12))
+		Expect(gps[0].Inclination).To(BeNumerically("~", 0.9*RAD2DEG, 1e-12))
+		Expect(gps[0].MeanMotion).To(BeNumerically("~", 0.0686*1440/(2*math.Pi), 1e-12))
+		Expect(gps[0].Epoch).To(Equal(time.Date(2008, 9, 20, 12, 25, 40, 104192000, time.UTC)))
+	})
+
+	It("should report missing columns, unknown units and bad rows", func() {
+		_, err := ReadGPCSV(strings.NewReader("NORAD_CAT_ID,EPOCH\n25544,2008-09-20T12:25:40\n"))
+		Expect(err).To(MatchError(ContainSubstring("MEAN_MOTION")))
+
+		_, err = ReadGPCSV(strings.NewReader(strings.Replace(gpCSV, "INCLINATION", "INCLINATION [grad]", 1)))
+		Expect(err).To(MatchError(ContainSubstring("grad")))
+
+		_, err = ReadGPCSV(strings.NewReader(strings.Replace(gpCSV, "51.6416", "51.6x16", 1)))
+		Expect(err).To(MatchError(ContainSubstring("row 2")))
+	})
+
+	It("should stream satellites from GP JSON", func() {
+		sats, err := ReadGPJSON(strings.NewReader(ommJSON), "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sats).To(HaveLen(2))
+		tles := jobTestSatellites()
+		for i, tle := range []Satellite{tles[0], tles[3]} {
+			Expect(sats[i].Satnum).To(Equal(tle.Satnum))
+			t := tle.jdsatepoch.ToTime().Add(6 * time.Hour)
+			want, _, _ := tle.propagateAt(t)
+			got, _, err := sats[i].propagateAt(t)
+			Expect(err).To(BeNil())
+			Expect(distance(got, want)).To(BeNumerically("<", 1e-3))
+		}
+
+		sats, err = ReadGPJSON(strings.NewReader(" "), "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sats).To(BeEmpty())
+	})
+
+	It("should move on past a bad GP JSON record", func() {
+		doc := strings.Replace(ommJSON, `"INCLINATION":"51.6416"`, `"INCLINATION":"x"`, 1)
+		d := NewGPJSONDecoder(strings.NewReader(doc), "wgs72")
+		_, err := d.Next()
+		Expect(err).To(MatchError(HavePrefix("GP JSON record 1: Error on parsing INCLINATION")))
+		var entry *GPEntryError
+		Expect(errors.As(err, &entry)).To(BeTrue())
+		Expect(entry.Record).To(Equal(1))
+		sat, err := d.Next()
+		Expect(err).To(BeNil())
+		Expect(sat.Satnum).To(Equal(int64(33591)))
+		_, err = d.Next()
+		Expect(err).To(Equal(io.EOF))
+
+		_, err = ReadGPJSON(strings.NewReader(`{"NORAD_CAT_ID":25544}`), "wgs72")
+		Expect(err).To(MatchError("GP JSON: expected an array of element sets"))
+	})
+
+	It("should read CSV without a header into satellites", func() {
+		body := gpCSV[strings.Index(gpCSV, "\n")+1:]
+		sats, err := ReadGPCSVSatellites(strings.NewReader(body), "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sats).To(HaveLen(2))
+		Expect(sats[0].Satnum).To(Equal(int64(25544)))
+		Expect(sats[1].Satnum).To(Equal(int64(33591)))
+
+		withHeader, err := ReadGPCSVSatellites(strings.NewReader(gpCSV), "wgs72")
+		Expect(err).To(BeNil())
+		Expect(withHeader).To(HaveLen(2))
+		Expect(withHeader[1].Elements()).To(Equal(sats[1].Elements()))
+
+		_, err = ReadGPCSV(strings.NewReader(strings.Replace(body, "51.6416", "51.6x16", 1)))
+		Expect(err).To(MatchError(ContainSubstring("row 1")))
+	})
+})
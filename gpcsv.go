@@ -0,0 +1,198 @@
+package satellite
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Columns of the CSV format served by CelesTrak, assumed for input without a header row
+var gpCSVColumns = []string{"OBJECT_NAME", "OBJECT_ID", "EPOCH", "MEAN_MOTION", "ECCENTRICITY", "INCLINATION", "RA_OF_ASC_NODE", "ARG_OF_PERICENTER", "MEAN_ANOMALY", "EPHEMERIS_TYPE", "CLASSIFICATION_TYPE", "NORAD_CAT_ID", "ELEMENT_SET_NO", "REV_AT_EPOCH", "BSTAR", "MEAN_MOTION_DOT", "MEAN_MOTION_DDOT"}
+
+// Reads general perturbations element sets in the CSV format served by CelesTrak with
+// FORMAT=csv. Columns are mapped by their OMM keyword headers in any order and case;
+// unknown columns are ignored. Input whose first row names no OMM keyword has no header
+// and is read in the CelesTrak column order. Supplemental element sets may name their provider in a
+// SOURCE column. A header may carry its unit in brackets, such as
+// "MEAN_MOTION [rad/min]" or "INCLINATION [rad]", which is converted to the OMM units.
+func ReadGPCSV(r io.Reader) ([]GPElements, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]int, len(header))
+	scale := make(map[string]float64, len(header))
+	names := make([]string, len(header))
+	units := make([]string, len(header))
+	known := false
+	for i, h := range header {
+		name, unit, _ := strings.Cut(strings.TrimPrefix(h, "\ufeff"), "[")
+		names[i] = strings.ToUpper(strings.TrimSpace(name))
+		units[i] = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(unit), "]"))
+		known = known || slices.Contains(ommRequired, names[i])
+	}
+	// Without any OMM keyword the first row is data in the CelesTrak column order
+	var first []string
+	if !known {
+		first = header
+		names, units = gpCSVColumns, make([]string, len(gpCSVColumns))
+	}
+	for i, name := range names {
+		f, err := gpUnitScale(name, units[i])
+		if err != nil {
+			return nil, err
+		}
+		cols[name], scale[name] = i, f
+	}
+	for _, name := range []string{"EPOCH", "MEAN_MOTION", "ECCENTRICITY", "INCLINATION", "RA_OF_ASC_NODE", "ARG_OF_PERICENTER", "MEAN_ANOMALY", "NORAD_CAT_ID"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("GP CSV header lacks column %s", name)
+		}
+	}
+
+	var out []GPElements
+	row := 2
+	if first != nil {
+		row = 1
+	}
+	for ; ; row++ {
+		var rec []string
+		if first != nil {
+			rec, first = first, nil
+		} else if rec, err = cr.Read(); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return out, err
+		}
+		if len(rec) == 1 && strings.TrimSpace(rec[0]) == "" {
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		var perr error
+		float := func(name string) float64 {
+			s := field(name)
+			if s == "" {
+				return 0
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil && perr == nil {
+				perr = fmt.Errorf("Error on parsing %s: %v", name, err)
+			}
+			return v * scale[name]
+		}
+		integer := func(name string) int64 {
+			s := field(name)
+			if s == "" {
+				return 0
+			}
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err != nil && perr == nil {
+				perr = fmt.Errorf("Error on parsing %s: %v", name, err)
+			}
+			return v
+		}
+
+		gp := GPElements{
+			ObjectName:         field("OBJECT_NAME"),
+			ObjectID:           field("OBJECT_ID"),
+			NoradCatID:         integer("NORAD_CAT_ID"),
+			MeanMotion:         float("MEAN_MOTION"),
+			Eccentricity:       float("ECCENTRICITY"),
+			Inclination:        float("INCLINATION"),
+			RAOfAscNode:        float("RA_OF_ASC_NODE"),
+			ArgOfPericenter:    float("ARG_OF_PERICENTER"),
+			MeanAnomaly:        float("MEAN_ANOMALY"),
+			EphemerisType:      int(integer("EPHEMERIS_TYPE")),
+			ClassificationType: field("CLASSIFICATION_TYPE"),
+			ElementSetNo:       integer("ELEMENT_SET_NO"),
+			RevAtEpoch:         integer("REV_AT_EPOCH"),
+			Source:             field("SOURCE"),
+			Bstar:              float("BSTAR"),
+			MeanMotionDot:      float("MEAN_MOTION_DOT"),
+			MeanMotionDDot:     float("MEAN_MOTION_DDOT"),
+		}
+		if perr == nil {
+			gp.Epoch, perr = parseGPEpoch(field("EPOCH"))
+		}
+		if perr != nil {
+			return out, fmt.Errorf("GP CSV row %d: %w", row, perr)
+		}
+		out = append(out, gp)
+	}
+}
+
+// Returns the factor converting a column from the given unit to the OMM unit
+func gpUnitScale(column, unit string) (float64, error) {
+	unit = strings.ToLower(unit)
+	if unit == "" {
+		return 1, nil
+	}
+	switch column {
+	case "INCLINATION", "RA_OF_ASC_NODE", "ARG_OF_PERICENTER", "MEAN_ANOMALY":
+		switch unit {
+		case "deg":
+			return 1, nil
+		case "rad":
+			return RAD2DEG, nil
+		}
+	case "MEAN_MOTION":
+		switch unit {
+		case "rev/day":
+			return 1, nil
+		case "rad/min":
+			return XPDOTP, nil
+		case "deg/day":
+			return 1.0 / 360, nil
+		}
+	case "MEAN_MOTION_DOT":
+		switch unit {
+		case "rev/day**2", "rev/day^2", "rev/day²":
+			return 1, nil
+		}
+	case "MEAN_MOTION_DDOT":
+		switch unit {
+		case "rev/day**3", "rev/day^3", "rev/day³":
+			return 1, nil
+		}
+	case "BSTAR":
+		switch unit {
+		case "1/er", "er**-1", "1/earth radii":
+			return 1, nil
+		}
+	default:
+		return 1, nil
+	}
+	return 0, fmt.Errorf("unsupported unit %s of GP CSV column %s", unit, column)
+}
+
+// Reads GP CSV element sets into initialized satellites, stopping at the first row that fails
+func ReadGPCSVSatellites(r io.Reader, gravconst GravModel) ([]Satellite, error) {
+	gps, err := ReadGPCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	sats := make([]Satellite, 0, len(gps))
+	for i, gp := range gps {
+		sat, err := NewSatFromGP(gp, gravconst)
+		if err != nil {
+			return sats, fmt.Errorf("GP CSV element set %d: %w", i+1, err)
+		}
+		sats = append(sats, sat)
+	}
+	return sats, nil
+}
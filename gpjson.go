@@ -0,0 +1,103 @@
+package satellite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Streaming decoder of general perturbations element sets in the JSON format served by
+// CelesTrak with FORMAT=json: an array of objects keyed by the OMM keywords. Records are
+// decoded one at a time, so whole catalog downloads are never held in memory.
+type GPJSONDecoder struct {
+	dec       *json.Decoder
+	gravconst GravModel
+	started   bool
+	n         int
+}
+
+// Failure of one record of a GP JSON array. The decoder moves on to the next record after it.
+type GPEntryError struct {
+	// Position of the record in the array, counted from 1
+	Record int
+
+	Err error
+}
+
+func (e *GPEntryError) Error() string {
+	return fmt.Sprintf("GP JSON record %d: %v", e.Record, e.Err)
+}
+
+func (e *GPEntryError) Unwrap() error {
+	return e.Err
+}
+
+// Returns a decoder reading the array from r and initializing satellites with the gravity model
+func NewGPJSONDecoder(r io.Reader, gravconst GravModel) *GPJSONDecoder {
+	return &GPJSONDecoder{dec: json.NewDecoder(r), gravconst: gravconst}
+}
+
+// Returns the element set of the next record. A record failing to convert returns a
+// *GPEntryError and the next call moves on to the following record. io.EOF is returned after the last one
+// and for empty input.
+func (d *GPJSONDecoder) NextElements() (GPElements, error) {
+	if !d.started {
+		tok, err := d.dec.Token()
+		if err == io.EOF {
+			return GPElements{}, io.EOF
+		}
+		if err != nil {
+			return GPElements{}, fmt.Errorf("GP JSON: %w", err)
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return GPElements{}, fmt.Errorf("GP JSON: expected an array of element sets")
+		}
+		d.started = true
+	}
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return GPElements{}, fmt.Errorf("GP JSON: %w", err)
+		}
+		return GPElements{}, io.EOF
+	}
+	d.n++
+	var rec map[string]json.RawMessage
+	if err := d.dec.Decode(&rec); err != nil {
+		return GPElements{}, fmt.Errorf("GP JSON record %d: %w", d.n, err)
+	}
+	gp, err := gpFromJSON(rec)
+	if err != nil {
+		return gp, &GPEntryError{Record: d.n, Err: err}
+	}
+	return gp, nil
+}
+
+// Returns the initialized satellite of the next record, with the error handling of NextElements
+func (d *GPJSONDecoder) Next() (Satellite, error) {
+	gp, err := d.NextElements()
+	if err != nil {
+		return Satellite{}, err
+	}
+	sat, err := NewSatFromGP(gp, d.gravconst)
+	if err != nil {
+		return sat, &GPEntryError{Record: d.n, Err: err}
+	}
+	return sat, nil
+}
+
+// Reads every element set of a GP JSON array into initialized satellites, stopping at the
+// first record that fails
+func ReadGPJSON(r io.Reader, gravconst GravModel) ([]Satellite, error) {
+	d := NewGPJSONDecoder(r, gravconst)
+	var sats []Satellite
+	for {
+		sat, err := d.Next()
+		if err == io.EOF {
+			return sats, nil
+		}
+		if err != nil {
+			return sats, err
+		}
+		sats = append(sats, sat)
+	}
+}
@@ -3,17 +3,88 @@ package satellite
 import (
 	"fmt"
 	"math"
+	"sync"
 )
 
 // Holds variables that are dependent upon selected gravity model
 type GravConst struct {
 	mu, radiusearthkm, xke, tumin, j2, j3, j4, j3oj2, f float64
+
+	// Name, rotation rate and prime meridian angle at J2000 of a central body other than
+	// the Earth, see CentralBody
+	body                   string
+	rotationRate, meridian float64
+}
+
+// Name of a gravity model accepted by NewSatFromTLE and the other element set readers
+type GravModel string
+
+// Gravity models of the SGP4 reference implementation. WGS72 is the model element sets are
+// fitted with and the usual choice.
+const (
+	WGS72Old GravModel = "wgs72old"
+	WGS72    GravModel = "wgs72"
+	WGS84    GravModel = "wgs84"
+)
+
+var gravModels sync.Map
+
+// Makes a custom gravity model available under name to NewSatFromTLE and the other element
+// set readers, replacing any model registered under the same name before. The names of the
+// built in models cannot be taken.
+func RegisterGravModel(name GravModel, grav GravConst) error {
+	switch name {
+	case WGS72Old, WGS72, WGS84:
+		return fmt.Errorf("gravity model %s is built in", name)
+	}
+	gravModels.Store(name, grav)
+	return nil
+}
+
+// Returns a gravity model of the Earth or another body from its gravitational parameter in
+// km^3/s^2, equatorial radius in km, zonal harmonics and flattening
+func NewGravConst(mu, radiusKm, j2, j3, j4, flattening float64) GravConst {
+	grav := GravConst{mu: mu, radiusearthkm: radiusKm, j2: j2, j3: j3, j4: j4, f: flattening}
+	grav.xke = 60.0 / math.Sqrt(radiusKm*radiusKm*radiusKm/mu)
+	grav.tumin = 1.0 / grav.xke
+	if j2 != 0 {
+		grav.j3oj2 = j3 / j2
+	}
+	return grav
+}
+
+// Returns the gravitational parameter in km^3/s^2
+func (g GravConst) Mu() float64 {
+	return g.mu
+}
+
+// Returns the equatorial radius in km
+func (g GravConst) RadiusKm() float64 {
+	return g.radiusearthkm
+}
+
+// Returns the second, third and fourth zonal harmonics
+func (g GravConst) Zonals() (j2, j3, j4 float64) {
+	return g.j2, g.j3, g.j4
+}
+
+// Returns the flattening of the reference ellipsoid
+func (g GravConst) Flattening() float64 {
+	return g.f
+}
+
+// Returns a copy of the satellite initialized with another gravity model
+func (sat Satellite) WithGravity(grav GravConst) (Satellite, error) {
+	sat.Gravity = grav
+	sat.no = sat.noKozai
+	_, _, err := sat.sgp4init(sat.jdsatepoch.Subtract(2433281.5))
+	return sat, err
 }
 
 // Returns a GravConst with correct information on requested model provided through the name parameter
-func getGravConst(name string) (grav GravConst, err error) {
+func getGravConst(name GravModel) (grav GravConst, err error) {
 	switch name {
-	case "wgs72old":
+	case WGS72Old:
 		grav.mu = 398600.79964
 		grav.radiusearthkm = 6378.135
 		grav.xke = 0.0743669161
@@ -23,28 +94,15 @@ func getGravConst(name string) (grav GravConst, err error) {
 		grav.j4 = -0.00000165597
 		grav.j3oj2 = grav.j3 / grav.j2
 		grav.f = 1 / 298.26
-	case "wgs72":
-		grav.mu = 398600.8
-		grav.radiusearthkm = 6378.135
-		grav.xke = 60.0 / math.Sqrt(grav.radiusearthkm*grav.radiusearthkm*grav.radiusearthkm/grav.mu)
-		grav.tumin = 1.0 / grav.xke
-		grav.j2 = 0.001082616
-		grav.j3 = -0.00000253881
-		grav.j4 = -0.00000165597
-		grav.j3oj2 = grav.j3 / grav.j2
-		grav.f = 1 / 298.26
-	case "wgs84":
-		grav.mu = 398600.5
-		grav.radiusearthkm = 6378.137
-		grav.xke = 60.0 / math.Sqrt(grav.radiusearthkm*grav.radiusearthkm*grav.radiusearthkm/grav.mu)
-		grav.tumin = 1.0 / grav.xke
-		grav.j2 = 0.00108262998905
-		grav.j3 = -0.00000253215306
-		grav.j4 = -0.00000161098761
-		grav.j3oj2 = grav.j3 / grav.j2
-		grav.f = 1 / 298.257223563
+	case WGS72:
+		grav = NewGravConst(398600.8, 6378.135, 0.001082616, -0.00000253881, -0.00000165597, 1/298.26)
+	case WGS84:
+		grav = NewGravConst(398600.5, 6378.137, 0.00108262998905, -0.00000253215306, -0.00000161098761, 1/298.257223563)
 	default:
-		err = fmt.Errorf("%s is not a valid gravity model", name)
+		if g, ok := gravModels.Load(name); ok {
+			return g.(GravConst), nil
+		}
+		err = newError(ErrUnknownGravModel, "%s is not a valid gravity model", name)
 	}
 
 	return
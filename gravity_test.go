@@ -0,0 +1,66 @@
+package satellite
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Gravity models", func() {
+	const (
+		line1 = "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+		line2 = "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+	)
+
+	It("should build the built in models from their constants", func() {
+		grav, err := getGravConst(WGS84)
+		Expect(err).To(BeNil())
+		Expect(grav.Mu()).To(Equal(398600.5))
+		Expect(grav.RadiusKm()).To(Equal(6378.137))
+		j2, _, _ := grav.Zonals()
+		Expect(j2).To(Equal(0.00108262998905))
+		Expect(grav.Flattening()).To(Equal(1 / 298.257223563))
+	})
+
+	It("should propagate with a registered custom model", func() {
+		wgs72, err := getGravConst(WGS72)
+		Expect(err).To(BeNil())
+		j2, j3, j4 := wgs72.Zonals()
+		Expect(RegisterGravModel("wgs72copy", NewGravConst(wgs72.Mu(), wgs72.RadiusKm(), j2, j3, j4, wgs72.Flattening()))).To(Succeed())
+		Expect(RegisterGravModel(WGS72, wgs72)).To(MatchError("gravity model wgs72 is built in"))
+
+		want, err := NewSatFromTLE(line1, line2, WGS72)
+		Expect(err).To(BeNil())
+		got, err := NewSatFromTLE(line1, line2, "wgs72copy")
+		Expect(err).To(BeNil())
+		t := want.EpochTime().Add(24 * time.Hour)
+		wantPos, _, _ := want.Propagate(t)
+		gotPos, _, err := got.Propagate(t)
+		Expect(err).To(BeNil())
+		Expect(gotPos).To(Equal(wantPos))
+
+		_, err = NewSatFromTLE(line1, line2, "egm2008")
+		Expect(errors.Is(err, ErrUnknownGravModel)).To(BeTrue())
+	})
+
+	It("should reinitialize a satellite with another model", func() {
+		sat, err := NewSatFromTLE(line1, line2, WGS72)
+		Expect(err).To(BeNil())
+		wgs84, err := getGravConst(WGS84)
+		Expect(err).To(BeNil())
+		other, err := sat.WithGravity(wgs84)
+		Expect(err).To(BeNil())
+		want, err := NewSatFromTLE(line1, line2, WGS84)
+		Expect(err).To(BeNil())
+
+		t := sat.EpochTime().Add(6 * time.Hour)
+		wantPos, _, _ := want.Propagate(t)
+		gotPos, _, err := other.Propagate(t)
+		Expect(err).To(BeNil())
+		Expect(distance(gotPos, wantPos)).To(BeNumerically("<", 1e-9))
+		orig, _, _ := sat.Propagate(t)
+		Expect(distance(orig, wantPos)).To(BeNumerically(">", 1e-3))
+	})
+})
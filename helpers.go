@@ -34,31 +34,77 @@ type LookAngles struct {
 	Az, El, Rg float64
 }
 
+// Holds look angles and their rates of change: the range rate in km/s, positive when the
+// satellite recedes, and the azimuth and elevation rates in rad/s
+type LookAnglesState struct {
+	LookAngles
+	RangeRate                  float64
+	AzimuthRate, ElevationRate float64
+}
+
+// Holds an azimuth and elevation in degrees and range in km
+type LookAnglesDeg struct {
+	Az, El Degrees
+	Rg     Kilometers
+}
+
+// Returns the look angles with azimuth and elevation converted to degrees
+func (la LookAngles) Deg() LookAnglesDeg {
+	return LookAnglesDeg{
+		Az: Radians(la.Az).Degrees(),
+		El: Radians(la.El).Degrees(),
+		Rg: Kilometers(la.Rg),
+	}
+}
+
+func (la LookAngles) String() string {
+	return la.Deg().String()
+}
+
+func (la LookAnglesDeg) String() string {
+	return fmt.Sprintf("Az %.1f° El %.1f° Rng %.0f km", float64(la.Az), float64(la.El), float64(la.Rg))
+}
+
 type JDay struct {
 	Day, Fraction float64
 }
 
-// Parses a two line element dataset into a Satellite struct
+// Parses a two line element dataset into a Satellite struct. Trailing white space and
+// line breaks are ignored.
 func ParseTLE(line1, line2 string) (sat Satellite, err error) {
+	line1, line2 = strings.TrimRight(line1, " \t\r\n"), strings.TrimRight(line2, " \t\r\n")
 
 	if len(line1) != 69 {
-		return sat, fmt.Errorf("Line1 length should be 69 but was %d", len(line1))
+		return sat, newError(ErrBadLineLength, "Line1 length should be 69 but was %d", len(line1))
 	}
 
 	if len(line2) != 69 {
-		return sat, fmt.Errorf("Line2 length should be 69 but was %d", len(line2))
+		return sat, newError(ErrBadLineLength, "Line2 length should be 69 but was %d", len(line2))
 	}
 
 	sat.Line1 = line1
 	sat.Line2 = line2
 
 	// LINE 1 BEGIN
-	sat.Satnum, err = parseInt(strings.TrimSpace(line1[2:7]))
+	sat.Satnum, err = ParseAlpha5(line1[2:7])
 	if err != nil {
 		err = fmt.Errorf("Error on parsing line1[2:7]: %v", err)
 		return
 	}
 
+	// Metadata fields are informational and left zero when blank or malformed
+	sat.classification = strings.TrimSpace(line1[7:8])
+	sat.designator = strings.TrimSpace(line1[9:17])
+	if v, err := strconv.Atoi(strings.TrimSpace(line1[62:63])); err == nil {
+		sat.ephemerisType = v
+	}
+	if v, err := parseInt(strings.TrimSpace(line1[64:68])); err == nil {
+		sat.elementSetNo = v
+	}
+	if v, err := parseInt(strings.TrimSpace(line2[63:68])); err == nil {
+		sat.revNumber = v
+	}
+
 	sat.epochyr, err = parseInt(line1[18:20])
 	if err != nil {
 		err = fmt.Errorf("Error on parsing line1[18:20]: %v", err)
@@ -123,8 +169,71 @@ func ParseTLE(line1, line2 string) (sat Satellite, err error) {
 	return
 }
 
+// Parses a two line element dataset like ParseTLE after checking the line numbers and the
+// modulo 10 checksums in column 69 of both lines
+func ParseTLEStrict(line1, line2 string) (Satellite, error) {
+	for i, line := range []string{line1, line2} {
+		line = strings.TrimRight(line, " \t\r\n")
+		if len(line) != 69 {
+			return Satellite{}, newError(ErrBadLineLength, "Line%d length should be 69 but was %d", i+1, len(line))
+		}
+		if line[0] != byte('1'+i) || line[1] != ' ' {
+			return Satellite{}, fmt.Errorf("Line%d should start with \"%d \"", i+1, i+1)
+		}
+		if sum := TLEChecksum(line); int(line[68]-'0') != sum {
+			return Satellite{}, newError(ErrChecksum, "Line%d checksum should be %d but was %c", i+1, sum, line[68])
+		}
+	}
+	return ParseTLE(line1, line2)
+}
+
+// Returns the modulo 10 checksum of the first 68 columns of a TLE line: the sum of the
+// digits with each minus sign counting as one
+func TLEChecksum(line string) int {
+	sum := 0
+	for i := 0; i < len(line) && i < 68; i++ {
+		switch c := line[i]; {
+		case c >= '0' && c <= '9':
+			sum += int(c - '0')
+		case c == '-':
+			sum++
+		}
+	}
+	return sum % 10
+}
+
+// Parses a three line element dataset, whose name line may carry the "0 " prefix of
+// Space-Track, into a Satellite struct
+func Parse3LE(line0, line1, line2 string) (Satellite, error) {
+	sat, err := ParseTLE(line1, line2)
+	sat.Name = tleName(line0)
+	return sat, err
+}
+
+// Converts a three line element data set into a Satellite struct and runs sgp4init
+func NewSatFrom3LE(line0, line1, line2 string, gravconst GravModel) (Satellite, error) {
+	sat, err := NewSatFromTLE(line1, line2, gravconst)
+	sat.Name = tleName(line0)
+	return sat, err
+}
+
+// Returns the object name of a three line element set name line
+func tleName(line0 string) string {
+	name := strings.TrimSpace(line0)
+	if rest, ok := strings.CutPrefix(name, "0 "); ok {
+		name = strings.TrimSpace(rest)
+	}
+	return name
+}
+
 // Converts a two line element data set into a Satellite struct and runs sgp4init
-func NewSatFromTLE(line1, line2 string, gravconst string) (Satellite, error) {
+func NewSatFromTLE(line1, line2 string, gravconst GravModel) (Satellite, error) {
+	return NewSatFromTLEWithOptions(line1, line2, gravconst, TLEOptions{})
+}
+
+// Converts a two line element data set into a Satellite struct like NewSatFromTLE, reading
+// the epoch year with the given options
+func NewSatFromTLEWithOptions(line1, line2 string, gravconst GravModel, opts TLEOptions) (Satellite, error) {
 	sat, err := ParseTLE(line1, line2)
 
 	if err != nil {
@@ -133,10 +242,18 @@ func NewSatFromTLE(line1, line2 string, gravconst string) (Satellite, error) {
 
 	sat.Gravity, err = getGravConst(gravconst)
 	if err != nil {
-		return sat, fmt.Errorf("Error on getting gravconst: %v", err)
+		return sat, fmt.Errorf("Error on getting gravconst: %w", err)
 	}
 
+	err = sat.initialize(epochYear(sat.epochyr, opts.pivot()))
+	return sat, err
+}
+
+// Converts the parsed elements from TLE units to radians and rad/min, sets the epoch in
+// the given full year and runs sgp4init
+func (sat *Satellite) initialize(year int64) error {
 	sat.no = sat.no / XPDOTP
+	sat.noKozai = sat.no
 	sat.ndot = sat.ndot / (XPDOTP * 1440.0)
 	sat.nddot = sat.nddot / (XPDOTP * 1440.0 * 1440)
 
@@ -145,20 +262,13 @@ func NewSatFromTLE(line1, line2 string, gravconst string) (Satellite, error) {
 	sat.argpo = sat.argpo * DEG2RAD
 	sat.mo = sat.mo * DEG2RAD
 
-	var year int64 = 0
-	if sat.epochyr < 57 {
-		year = sat.epochyr + 2000
-	} else {
-		year = sat.epochyr + 1900
-	}
-
-	mon, day, hr, min, sec := days2mdhms(year, sat.epochdays)
+	sat.setEpoch(year, sat.epochdays)
 
-	sat.jdsatepoch = NewJDay(int(year), int(mon), int(day), int(hr), int(min), sec)
-
-	_, _, err = sat.sgp4init(sat.jdsatepoch.Subtract(2433281.5))
-
-	return sat, err
+	_, _, err := sat.sgp4init(sat.jdsatepoch.Subtract(2433281.5))
+	if err != nil {
+		Logger().Warn("sgp4 initialization failed", "satnum", sat.Satnum, "err", err)
+	}
+	return err
 }
 
 func NewLatLongAlt(latitudeDeg, longitudeDeg, altitudeKm float64) LatLongAlt {
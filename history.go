@@ -0,0 +1,65 @@
+package satellite
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Orders element sets of one object by epoch. Sets with an identical epoch are ordered by
+// element set number and then by their lines, so conflicts resolve the same way whatever
+// the input order.
+func compareEpochs(a, b *Satellite) int {
+	if c := cmp.Compare(a.jdsatepoch.Single(), b.jdsatepoch.Single()); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.elementSetNo, b.elementSetNo); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Line1+a.Line2, b.Line1+b.Line2)
+}
+
+// Groups element sets by catalog number into histories ordered by epoch. Sets sharing an
+// epoch with another set of the same object are collapsed into the later one in the
+// order of compareEpochs, which also drops exact duplicates from overlapping sources.
+func Histories(sats []Satellite) map[int64][]Satellite {
+	histories := make(map[int64][]Satellite)
+	for _, sat := range sats {
+		histories[sat.Satnum] = append(histories[sat.Satnum], sat)
+	}
+	for satnum, h := range histories {
+		slices.SortStableFunc(h, func(a, b Satellite) int { return compareEpochs(&a, &b) })
+		out := h[:0]
+		for i := range h {
+			if len(out) > 0 && out[len(out)-1].jdsatepoch.Single() == h[i].jdsatepoch.Single() {
+				out[len(out)-1] = h[i]
+				continue
+			}
+			out = append(out, h[i])
+		}
+		histories[satnum] = out
+	}
+	return histories
+}
+
+// Returns the latest element set of every object ordered by catalog number
+func LatestEpochs(sats []Satellite) []Satellite {
+	latest := make(map[int64]int)
+	for i := range sats {
+		j, ok := latest[sats[i].Satnum]
+		if !ok || compareEpochs(&sats[j], &sats[i]) < 0 {
+			latest[sats[i].Satnum] = i
+		}
+	}
+	out := make([]Satellite, 0, len(latest))
+	for _, i := range latest {
+		out = append(out, sats[i])
+	}
+	slices.SortFunc(out, func(a, b Satellite) int { return cmp.Compare(a.Satnum, b.Satnum) })
+	return out
+}
+
+// Creates a catalog holding the latest element set of every object
+func NewLatestCatalog(sats []Satellite) *Catalog {
+	return NewCatalog(LatestEpochs(sats)...)
+}
@@ -0,0 +1,60 @@
+package satellite
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Histories", func() {
+	// Same epoch as issTLE.after(1) but a later element set number
+	reissued := func() Satellite {
+		sat := issTLE.after(1).node(248).sat()
+		line1 := sat.Line1[:64] + " 293" + sat.Line1[68:]
+		sat, err := NewSatFromTLE(line1, sat.Line2, "wgs72")
+		Expect(err).To(BeNil())
+		return sat
+	}
+
+	It("should order each history by epoch and drop duplicates", func() {
+		noaa := jobTestSatellites()[3]
+		sats := []Satellite{
+			issTLE.after(2).node(249).sat(), noaa, issTLE.node(247).sat(),
+			issTLE.after(1).node(248).sat(), issTLE.node(247).sat(), noaa,
+		}
+		histories := Histories(sats)
+		Expect(histories).To(HaveLen(2))
+		Expect(histories[33591]).To(HaveLen(1))
+
+		iss := histories[25544]
+		Expect(iss).To(HaveLen(3))
+		for i := 1; i < len(iss); i++ {
+			Expect(iss[i].jdsatepoch.Single()).To(BeNumerically(">", iss[i-1].jdsatepoch.Single()))
+		}
+	})
+
+	It("should resolve identical epoch conflicts whatever the input order", func() {
+		a := Histories([]Satellite{issTLE.after(1).node(248).sat(), reissued()})[25544]
+		b := Histories([]Satellite{reissued(), issTLE.after(1).node(248).sat()})[25544]
+		Expect(a).To(HaveLen(1))
+		Expect(a[0].Line1).To(Equal(b[0].Line1))
+		Expect(a[0].ElementSetNumber()).To(Equal(int64(293)))
+	})
+})
+
+var _ = Describe("LatestEpochs", func() {
+	It("should keep the latest element set of every object", func() {
+		noaa := jobTestSatellites()[3]
+		sats := []Satellite{noaa, issTLE.after(2).node(249).sat(), issTLE.after(5).node(252).sat(), issTLE.node(247).sat(), noaa}
+		latest := LatestEpochs(sats)
+		Expect(latest).To(HaveLen(2))
+		Expect(latest[0].Satnum).To(Equal(int64(25544)))
+		Expect(latest[0].nodeo * RAD2DEG).To(BeNumerically("~", 252, 1e-6))
+		Expect(latest[1].Satnum).To(Equal(int64(33591)))
+
+		catalog := NewLatestCatalog(sats)
+		Expect(catalog.Len()).To(Equal(2))
+		sat, ok := catalog.Get(25544)
+		Expect(ok).To(BeTrue())
+		Expect(sat.Line1).To(Equal(latest[0].Line1))
+	})
+})
@@ -0,0 +1,83 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Returns the geometric dip of the true horizon below the astronomical horizon in radians
+// for an observer altitudeKm above the Earth surface
+func HorizonDip(altitudeKm float64, gravConst GravConst) float64 {
+	if altitudeKm <= 0 {
+		return 0
+	}
+	return math.Acos(gravConst.radiusearthkm / (gravConst.radiusearthkm + altitudeKm))
+}
+
+// Returns the minimum elevation for the observer, lowered by the horizon dip when dip is set
+func observerMinElevation(minElevation Radians, obs LatLongAlt, dip bool, gravConst GravConst) float64 {
+	if !dip {
+		return float64(minElevation)
+	}
+	return float64(minElevation) - HorizonDip(obs.AltitudeKm, gravConst)
+}
+
+// Point of a horizon profile: the elevation of the local horizon in radians at an azimuth
+// in radians measured clockwise from north
+type HorizonPoint struct {
+	Az, El float64
+}
+
+// Elevation mask of an observer as a function of azimuth, for stations whose view is
+// blocked by terrain or buildings. The elevation is interpolated linearly between the
+// profile points, wrapping around north.
+type HorizonMask struct {
+	points []HorizonPoint
+}
+
+// Returns the mask through the given profile points, which may come in any order
+func NewHorizonMask(points []HorizonPoint) (*HorizonMask, error) {
+	if len(points) == 0 {
+		return nil, errors.New("horizon mask needs at least one point")
+	}
+	m := &HorizonMask{points: make([]HorizonPoint, len(points))}
+	for i, p := range points {
+		if p.El < -math.Pi/2 || p.El > math.Pi/2 || math.IsNaN(p.Az) {
+			return nil, fmt.Errorf("horizon point %d has elevation %g outside -pi/2 to pi/2", i, p.El)
+		}
+		m.points[i] = HorizonPoint{Az: float64(Radians(p.Az).Normalize()), El: p.El}
+	}
+	sort.SliceStable(m.points, func(i, j int) bool { return m.points[i].Az < m.points[j].Az })
+	return m, nil
+}
+
+// Returns the mask through profile points given as azimuth, elevation pairs in degrees
+func NewHorizonMaskDeg(pairs [][2]float64) (*HorizonMask, error) {
+	points := make([]HorizonPoint, len(pairs))
+	for i, p := range pairs {
+		points[i] = HorizonPoint{Az: p[0] * DEG2RAD, El: p[1] * DEG2RAD}
+	}
+	return NewHorizonMask(points)
+}
+
+// Returns the elevation of the horizon in radians at an azimuth in radians
+func (m *HorizonMask) Elevation(az float64) float64 {
+	pts := m.points
+	az = float64(Radians(az).Normalize())
+	i := sort.Search(len(pts), func(i int) bool { return pts[i].Az > az })
+	lo, hi := pts[(i+len(pts)-1)%len(pts)], pts[i%len(pts)]
+	span := hi.Az - lo.Az
+	off := az - lo.Az
+	if span <= 0 {
+		span += 2 * math.Pi
+	}
+	if off < 0 {
+		off += 2 * math.Pi
+	}
+	if span >= 2*math.Pi {
+		return lo.El
+	}
+	return lo.El + (hi.El-lo.El)*off/span
+}
@@ -0,0 +1,74 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HorizonDip", func() {
+	It("should return the geometric dip for elevated observers", func() {
+		grav := jobTestSatellites()[0].Gravity
+		Expect(HorizonDip(0, grav)).To(Equal(0.0))
+		Expect(HorizonDip(-0.1, grav)).To(Equal(0.0))
+		// About 3.2 deg from a 10 km flight level
+		Expect(HorizonDip(10, grav) * RAD2DEG).To(BeNumerically("~", 3.2, 0.02))
+	})
+
+	It("should open access windows below the nominal horizon", func() {
+		sat := jobTestSatellites()[0]
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		aircraft := NewLatLongAlt(55.6167, 12.6500, 10)
+		dip := HorizonDip(aircraft.AltitudeKm, sat.Gravity)
+
+		nominal, err := NextAccesses(&sat, aircraft, start, 1, AccessOptions{})
+		Expect(err).To(BeNil())
+		dipped, err := NextAccesses(&sat, aircraft, start, 1, AccessOptions{HorizonDip: true})
+		Expect(err).To(BeNil())
+
+		Expect(dipped[0].Start).To(BeTemporally("<", nominal[0].Start))
+		Expect(dipped[0].Stop).To(BeTemporally(">", nominal[0].Stop))
+		Expect(dipped[0].StartAngles.El).To(BeNumerically("~", -dip, 0.001))
+		Expect(dipped[0].StopAngles.El).To(BeNumerically("~", -dip, 0.001))
+	})
+})
+
+var _ = Describe("HorizonMask", func() {
+	It("should interpolate the profile around north", func() {
+		mask, err := NewHorizonMaskDeg([][2]float64{{90, 10}, {0, 0}, {270, 20}})
+		Expect(err).To(BeNil())
+		Expect(mask.Elevation(45*DEG2RAD) * RAD2DEG).To(BeNumerically("~", 5, 1e-9))
+		Expect(mask.Elevation(180*DEG2RAD) * RAD2DEG).To(BeNumerically("~", 15, 1e-9))
+		Expect(mask.Elevation(315*DEG2RAD) * RAD2DEG).To(BeNumerically("~", 10, 1e-9))
+		Expect(mask.Elevation(-45*DEG2RAD) * RAD2DEG).To(BeNumerically("~", 10, 1e-9))
+
+		flat, err := NewHorizonMaskDeg([][2]float64{{123, 7}})
+		Expect(err).To(BeNil())
+		Expect(flat.Elevation(1) * RAD2DEG).To(BeNumerically("~", 7, 1e-9))
+
+		_, err = NewHorizonMask(nil)
+		Expect(err).To(HaveOccurred())
+		_, err = NewHorizonMaskDeg([][2]float64{{0, 95}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should shorten passes rising or setting behind obstructions", func() {
+		sat := jobTestSatellites()[0]
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+		open, err := NextPasses(&sat, copenhagen, start, 1, PassOptions{})
+		Expect(err).To(BeNil())
+
+		// A 15 deg wall in the quarter the pass rises in
+		rise := open[0].AOSAzimuth * RAD2DEG
+		mask, err := NewHorizonMaskDeg([][2]float64{{rise - 45, 15}, {rise + 45, 15}, {rise + 46, 0}, {rise - 46, 0}})
+		Expect(err).To(BeNil())
+		masked, err := NextPasses(&sat, copenhagen, start, 1, PassOptions{Mask: mask})
+		Expect(err).To(BeNil())
+		Expect(masked[0].AOS).To(BeTemporally(">", open[0].AOS))
+		Expect(masked[0].TCA).To(BeTemporally("~", open[0].TCA, 2*time.Second))
+		Expect(masked[0].MaxElevation).To(BeNumerically(">", 15*DEG2RAD))
+		Expect(masked[0].LOS).To(BeTemporally("~", open[0].LOS, time.Second))
+	})
+})
@@ -0,0 +1,87 @@
+package satellite
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// Opaque token returned by a canceled catalog-scale job. Passing it back through
+// the job options resumes the job after the last completed work item. The zero
+// value starts a job from the beginning.
+type Checkpoint string
+
+type checkpointState struct {
+	Job         string `json:"job"`
+	Fingerprint uint64 `json:"fp"`
+	Next        int    `json:"next"`
+}
+
+// Encodes the position of a job so it can be resumed from work item next
+func newCheckpoint(job string, fingerprint uint64, next int) Checkpoint {
+	b, _ := json.Marshal(checkpointState{Job: job, Fingerprint: fingerprint, Next: next})
+	return Checkpoint(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// Returns the work item a job should resume from
+func (c Checkpoint) resume(job string, fingerprint uint64) (int, error) {
+	if c == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return 0, newError(ErrBadCheckpoint, "Error on decoding checkpoint: %v", err)
+	}
+	var st checkpointState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return 0, newError(ErrBadCheckpoint, "Error on decoding checkpoint: %v", err)
+	}
+	if st.Job != job {
+		return 0, newError(ErrBadCheckpoint, "checkpoint belongs to job %q, not %q", st.Job, job)
+	}
+	if st.Fingerprint != fingerprint {
+		return 0, newError(ErrBadCheckpoint, "checkpoint was created for different %s inputs", job)
+	}
+	return st.Next, nil
+}
+
+// Hashes job inputs so a checkpoint can only resume the job it was created for
+type fingerprint struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+func newFingerprint() *fingerprint {
+	return &fingerprint{h: fnv.New64a()}
+}
+
+func (f *fingerprint) sum() uint64 {
+	return f.h.Sum64()
+}
+
+func (f *fingerprint) float(v float64) *fingerprint {
+	binary.LittleEndian.PutUint64(f.buf[:], math.Float64bits(v))
+	f.h.Write(f.buf[:])
+	return f
+}
+
+func (f *fingerprint) int(v int64) *fingerprint {
+	binary.LittleEndian.PutUint64(f.buf[:], uint64(v))
+	f.h.Write(f.buf[:])
+	return f
+}
+
+func (f *fingerprint) time(t time.Time) *fingerprint {
+	return f.int(t.UnixNano())
+}
+
+func (f *fingerprint) satellites(sats []Satellite) *fingerprint {
+	for i := range sats {
+		f.int(sats[i].Satnum).float(sats[i].jdsatepoch.Day).float(sats[i].jdsatepoch.Fraction)
+	}
+	return f
+}
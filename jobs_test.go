@@ -0,0 +1,188 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Lines of a test element set, rewritten field by field to build element set histories
+type tleLines [2]string
+
+// ISS and sun-synchronous element sets of jobTestSatellites
+var (
+	issTLE = tleLines{"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"}
+	ssoTLE = tleLines{"1 33591U 09005A   08264.48990228  .00000077  00000-0  66998-4 0  9990",
+		"2 33591  99.0394 120.2160 0013054 232.8317 127.1662 14.12079902378332"}
+)
+
+// Returns the lines with the epoch moved by days
+func (l tleLines) after(days float64) tleLines {
+	epoch, err := strconv.ParseFloat(l[0][18:32], 64)
+	Expect(err).To(BeNil())
+	l[0] = l[0][:18] + fmt.Sprintf("%014.8f", epoch+days) + l[0][32:]
+	return l
+}
+
+// Returns the lines with the right ascension of the node set in degrees
+func (l tleLines) node(deg float64) tleLines {
+	l[1] = l[1][:17] + fmt.Sprintf("%8.4f", math.Mod(deg+360, 360)) + l[1][25:]
+	return l
+}
+
+// Returns the lines with the BSTAR field replaced, in TLE notation such as " 10000-3"
+func (l tleLines) bstar(field string) tleLines {
+	l[0] = l[0][:53] + field + l[0][61:]
+	return l
+}
+
+// Returns the satellite of the lines with the WGS72 model
+func (l tleLines) sat() Satellite {
+	sat, err := NewSatFromTLE(l[0], l[1], "wgs72")
+	Expect(err).To(BeNil())
+	return sat
+}
+
+func jobTestSatellites() []Satellite {
+	tles := []tleLines{
+		issTLE,
+		{"1 25545U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25545  51.6416 257.4627 0006703 130.5360 320.0288 15.72125391563537"},
+		{"1 25546U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25546  51.6416 237.4627 0006703 130.5360 330.0288 15.72125391563537"},
+		ssoTLE,
+	}
+	sats := make([]Satellite, len(tles))
+	for i, tle := range tles {
+		sats[i] = tle.sat()
+	}
+	return sats
+}
+
+var _ = Describe("Jobs", func() {
+	start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+	stop := start.Add(6 * time.Hour)
+
+	Describe("Screen", func() {
+		It("should find close approaches between crossing orbits", func() {
+			conjunctions, next, err := Screen(context.Background(), jobTestSatellites(), ScreeningOptions{
+				Start: start, Stop: stop, Threshold: 1000,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(next).To(Equal(Checkpoint("")))
+			Expect(conjunctions).To(Not(BeEmpty()))
+			for _, c := range conjunctions {
+				Expect(c.MissDistance).To(BeNumerically("<", 1000))
+				Expect(c.TCA).To(BeTemporally(">=", start))
+				Expect(c.TCA).To(BeTemporally("<=", stop))
+			}
+		})
+
+		It("should resume a canceled job where it stopped", func() {
+			opts := ScreeningOptions{Start: start, Stop: stop, Threshold: 1000}
+			full, _, err := Screen(context.Background(), jobTestSatellites(), opts)
+			Expect(err).To(BeNil())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			opts.Progress = func(p Progress, found []Conjunction) {
+				if p.Done == 1 {
+					cancel()
+				}
+			}
+			partial, next, err := Screen(ctx, jobTestSatellites(), opts)
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(next).To(Not(BeEmpty()))
+
+			opts.Progress = nil
+			opts.Resume = next
+			rest, next, err := Screen(context.Background(), jobTestSatellites(), opts)
+			Expect(err).To(BeNil())
+			Expect(next).To(BeEmpty())
+			Expect(append(partial, rest...)).To(Equal(full))
+		})
+
+		It("should reject a checkpoint from another job", func() {
+			_, next, err := Coverage(canceledContext(), jobTestSatellites(), CoverageGrid(0, 10, 0, 10, 5), CoverageOptions{Start: start, Stop: stop})
+			Expect(err).To(Equal(context.Canceled))
+
+			_, _, err = Screen(context.Background(), jobTestSatellites(), ScreeningOptions{Start: start, Stop: stop, Threshold: 10, Resume: next})
+			Expect(errors.Is(err, ErrBadCheckpoint)).To(BeTrue())
+		})
+	})
+
+	Describe("Coverage", func() {
+		It("should resume a canceled job and report coverage statistics", func() {
+			grid := CoverageGrid(-60, 60, 0, 40, 20)
+			opts := CoverageOptions{Start: start, Stop: stop, Step: 30 * time.Second, MinElevation: Degrees(10).Radians()}
+			full, _, err := Coverage(context.Background(), jobTestSatellites(), grid, opts)
+			Expect(err).To(BeNil())
+			Expect(full).To(HaveLen(len(grid)))
+
+			covered := 0
+			for _, c := range full {
+				Expect(c.Covered + c.MaxGap).To(BeNumerically("<=", stop.Sub(start)))
+				if c.Covered > 0 {
+					covered++
+					Expect(c.Accesses).To(BeNumerically(">", 0))
+				}
+			}
+			Expect(covered).To(BeNumerically(">", 0))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			opts.Progress = func(p Progress, found []CoverageCell) {
+				Expect(found).To(HaveLen(1))
+				if p.Done == 5 {
+					cancel()
+				}
+			}
+			partial, next, err := Coverage(ctx, jobTestSatellites(), grid, opts)
+			Expect(err).To(Equal(context.Canceled))
+			Expect(partial).To(HaveLen(5))
+
+			opts.Progress = nil
+			opts.Resume = next
+			rest, _, err := Coverage(context.Background(), jobTestSatellites(), grid, opts)
+			Expect(err).To(BeNil())
+			Expect(append(partial, rest...)).To(Equal(full))
+		})
+
+		It("should agree with the access windows above the geodetic horizon", func() {
+			sat := jobTestSatellites()[0]
+			point := NewLatLongFromDeg(55.6167, 12.65)
+			minEl := Degrees(10).Radians()
+			end := start.Add(24 * time.Hour)
+			step := 10 * time.Second
+			cells, _, err := Coverage(context.Background(), []Satellite{sat}, []LatLong{point},
+				CoverageOptions{Start: start, Stop: end, Step: step, MinElevation: minEl})
+			Expect(err).To(BeNil())
+
+			windows, err := NextAccesses(&sat, LatLongAlt{LatLong: point}, start, 10, AccessOptions{MinElevation: minEl})
+			Expect(err).To(BeNil())
+			var want time.Duration
+			accesses := 0
+			for _, w := range windows {
+				if w.Start.Before(end) {
+					accesses++
+					want += w.Duration()
+				}
+			}
+			Expect(accesses).To(BeNumerically(">", 1))
+			Expect(cells[0].Accesses).To(Equal(accesses))
+			Expect(cells[0].Covered).To(BeNumerically("~", want, time.Duration(accesses)*step))
+		})
+	})
+})
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
@@ -0,0 +1,140 @@
+package satellite
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How KML viewers interpret the altitude of coordinates
+type KMLAltitudeMode string
+
+const (
+	// Ignore the altitude and draw on the terrain
+	KMLClampToGround KMLAltitudeMode = "clampToGround"
+
+	// Altitude above the terrain
+	KMLRelativeToGround KMLAltitudeMode = "relativeToGround"
+
+	// Altitude above sea level
+	KMLAbsolute KMLAltitudeMode = "absolute"
+)
+
+// Observer site drawn as a placemark
+type KMLSite struct {
+	Name     string
+	Position LatLongAlt
+}
+
+// Options for WriteKML
+type KMLOptions struct {
+	// Document name, the catalog number of the satellite when empty
+	Name string
+
+	// Altitude mode of the ground track and the sites, KMLClampToGround when empty. With
+	// KMLAbsolute the track is drawn at the orbit altitude.
+	AltitudeMode KMLAltitudeMode
+
+	// Sampling step of the ground track, 30 seconds when zero
+	Step time.Duration
+
+	// Observer sites with their positions in radians
+	Sites []KMLSite
+}
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"kml"`
+	Namespace  string         `xml:"xmlns,attr"`
+	Name       string         `xml:"Document>name"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name       string         `xml:"name"`
+	TimeSpan   *kmlTimeSpan   `xml:"TimeSpan,omitempty"`
+	LineString *kmlLineString `xml:"LineString,omitempty"`
+	Point      *kmlPoint      `xml:"Point,omitempty"`
+}
+
+type kmlTimeSpan struct {
+	Begin string `xml:"begin"`
+	End   string `xml:"end"`
+}
+
+type kmlLineString struct {
+	Tessellate   int             `xml:"tessellate"`
+	AltitudeMode KMLAltitudeMode `xml:"altitudeMode"`
+	Coordinates  string          `xml:"coordinates"`
+}
+
+type kmlPoint struct {
+	AltitudeMode KMLAltitudeMode `xml:"altitudeMode"`
+	Coordinates  string          `xml:"coordinates"`
+}
+
+// Writes a KML document with the ground track of the satellite or ephemeris between start and
+// stop and the observer sites of the options. The track is split at the antimeridian into
+// placemarks with the time span they cover, so viewers can animate it.
+func WriteKML(w io.Writer, sat Propagator, start, stop time.Time, opts KMLOptions) error {
+	if !stop.After(start) {
+		return errors.New("KML stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+	mode := opts.AltitudeMode
+	if mode == "" {
+		mode = KMLClampToGround
+	}
+	name := opts.Name
+	if name == "" {
+		name = strconv.FormatInt(sat.CatalogNumber(), 10)
+	}
+
+	track, err := groundTrack(sat, sampleTimes(start, stop, step))
+	if err != nil {
+		return err
+	}
+	doc := kmlDocument{Namespace: "http://www.opengis.net/kml/2.2", Name: name}
+	for _, line := range track.lines() {
+		coords := make([]string, len(line))
+		for i, s := range line {
+			coords[i] = kmlCoordinate(s.LatLongAlt)
+		}
+		doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+			Name: name,
+			TimeSpan: &kmlTimeSpan{
+				Begin: line[0].Time.UTC().Format(time.RFC3339),
+				End:   line[len(line)-1].Time.UTC().Format(time.RFC3339),
+			},
+			LineString: &kmlLineString{Tessellate: 1, AltitudeMode: mode, Coordinates: strings.Join(coords, " ")},
+		})
+	}
+	for _, site := range opts.Sites {
+		doc.Placemarks = append(doc.Placemarks, kmlPlacemark{
+			Name:  site.Name,
+			Point: &kmlPoint{AltitudeMode: mode, Coordinates: kmlCoordinate(site.Position)},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// Formats a position in radians and km as a KML longitude, latitude and altitude in meters
+func kmlCoordinate(lla LatLongAlt) string {
+	return fmt.Sprintf("%.6f,%.6f,%.1f", lla.LatLong.Longitude*RAD2DEG, lla.LatLong.Latitude*RAD2DEG, lla.AltitudeKm*1000)
+}
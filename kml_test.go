@@ -0,0 +1,68 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WriteKML", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	sat := jobTestSatellites()[0]
+
+	It("should write the ground track and the sites", func() {
+		var buf bytes.Buffer
+		err := WriteKML(&buf, &sat, start, start.Add(6*time.Hour), KMLOptions{
+			Name:         "ISS",
+			AltitudeMode: KMLAbsolute,
+			Sites:        []KMLSite{{Name: "Copenhagen", Position: NewLatLongAlt(55.6167, 12.6500, 0.005)}},
+		})
+		Expect(err).To(BeNil())
+		Expect(buf.String()).To(HavePrefix(xml.Header + `<kml xmlns="http://www.opengis.net/kml/2.2">`))
+
+		var doc kmlDocument
+		Expect(xml.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Name).To(Equal("ISS"))
+		Expect(doc.Placemarks).To(HaveLen(5))
+
+		track, site := doc.Placemarks[:4], doc.Placemarks[4]
+		Expect(track[0].TimeSpan.Begin).To(Equal("2008-09-20T00:00:00Z"))
+		Expect(track[3].TimeSpan.End).To(Equal("2008-09-20T06:00:00Z"))
+		for i, p := range track {
+			Expect(p.LineString.AltitudeMode).To(Equal(KMLAbsolute))
+			if i > 0 {
+				Expect(p.TimeSpan.Begin).To(Equal(track[i-1].TimeSpan.End))
+			}
+			for _, c := range strings.Fields(p.LineString.Coordinates) {
+				Expect(strings.Split(c, ",")).To(HaveLen(3))
+			}
+		}
+		first := strings.Split(strings.Fields(track[0].LineString.Coordinates)[0], ",")
+		alt, _, _ := strings.Cut(first[2], ".")
+		Expect(len(alt)).To(Equal(6))
+
+		Expect(site.Name).To(Equal("Copenhagen"))
+		Expect(site.TimeSpan).To(BeNil())
+		Expect(site.Point.AltitudeMode).To(Equal(KMLAbsolute))
+		Expect(site.Point.Coordinates).To(Equal("12.650000,55.616700,5.0"))
+	})
+
+	It("should default to the catalog number and the ground", func() {
+		var buf bytes.Buffer
+		Expect(WriteKML(&buf, &sat, start, start.Add(10*time.Minute), KMLOptions{})).To(Succeed())
+		var doc kmlDocument
+		Expect(xml.Unmarshal(buf.Bytes(), &doc)).To(Succeed())
+		Expect(doc.Name).To(Equal("25544"))
+		Expect(doc.Placemarks).To(HaveLen(1))
+		Expect(doc.Placemarks[0].LineString.AltitudeMode).To(Equal(KMLClampToGround))
+		Expect(strings.Fields(doc.Placemarks[0].LineString.Coordinates)).To(HaveLen(21))
+	})
+
+	It("should reject an empty range", func() {
+		Expect(WriteKML(&bytes.Buffer{}, &sat, start, start, KMLOptions{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,95 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+)
+
+// Returns the inverse of a square matrix by Gauss-Jordan elimination with partial pivoting
+func invertMatrix(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	m := make([][]float64, n)
+	inv := make([][]float64, n)
+	for i := range a {
+		m[i] = append([]float64(nil), a[i]...)
+		inv[i] = make([]float64, n)
+		inv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if m[pivot][col] == 0 || math.IsNaN(m[pivot][col]) {
+			return nil, errors.New("matrix is singular")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		p := m[col][col]
+		for j := 0; j < n; j++ {
+			m[col][j] /= p
+			inv[col][j] /= p
+		}
+		for r := 0; r < n; r++ {
+			if r == col || m[r][col] == 0 {
+				continue
+			}
+			f := m[r][col]
+			for j := 0; j < n; j++ {
+				m[r][j] -= f * m[col][j]
+				inv[r][j] -= f * inv[col][j]
+			}
+		}
+	}
+	return inv, nil
+}
+
+// Returns the product of a matrix and a vector
+func mulMatVec(a [][]float64, v []float64) []float64 {
+	out := make([]float64, len(a))
+	for i := range a {
+		for j := range v {
+			out[i] += a[i][j] * v[j]
+		}
+	}
+	return out
+}
+
+// Returns a new rows by cols matrix of zeros
+func newMatrix(rows, cols int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+// Returns the matrix product a b
+func mulMat(a, b [][]float64) [][]float64 {
+	out := newMatrix(len(a), len(b[0]))
+	for i := range a {
+		for k := range b {
+			if a[i][k] == 0 {
+				continue
+			}
+			for j := range b[k] {
+				out[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return out
+}
+
+// Returns the transpose of a matrix
+func transpose(a [][]float64) [][]float64 {
+	out := newMatrix(len(a[0]), len(a))
+	for i := range a {
+		for j := range a[i] {
+			out[j][i] = a[i][j]
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Boltzmann constant in dBW/K/Hz
+const boltzmannDB = -228.5991
+
+// Radio link between a satellite and a ground station, in either direction
+type Link struct {
+	// Carrier frequency in Hz
+	Frequency float64
+
+	// Transmit power in dBW and the transmit and receive antenna gains in dBi
+	TransmitPower, TransmitGain, ReceiveGain float64
+
+	// System noise temperature of the receiver in kelvin
+	NoiseTemperature float64
+
+	// Further losses in dB such as atmospheric absorption, polarization and pointing losses
+	Losses float64
+
+	// C/N0 in dB-Hz the receiver needs, the margin is reported against it
+	RequiredCN0 float64
+}
+
+// Link performance at one moment of a pass
+type LinkPoint struct {
+	Time   time.Time
+	Angles LookAngles
+
+	// Free space path loss in dB, received power in dBW, carrier to noise density ratio in
+	// dB-Hz and the margin over the required C/N0 in dB
+	PathLoss, ReceivedPower, CN0, Margin float64
+}
+
+// Returns the free space path loss in dB over rangeKm at freq Hz
+func FreeSpacePathLoss(rangeKm, freq float64) float64 {
+	return 20 * math.Log10(4*math.Pi*rangeKm*freq/speedOfLight)
+}
+
+// Returns the link performance at a range in km
+func (l Link) at(rangeKm float64) (pathLoss, received, cn0, margin float64) {
+	pathLoss = FreeSpacePathLoss(rangeKm, l.Frequency)
+	received = l.TransmitPower + l.TransmitGain + l.ReceiveGain - pathLoss - l.Losses
+	cn0 = received - 10*math.Log10(l.NoiseTemperature) - boltzmannDB
+	return pathLoss, received, cn0, cn0 - l.RequiredCN0
+}
+
+// Returns the link performance along the track of a pass
+func (l Link) Budget(p *Pass) ([]LinkPoint, error) {
+	if l.Frequency <= 0 || l.NoiseTemperature <= 0 {
+		return nil, errors.New("link needs a positive frequency and noise temperature")
+	}
+	track, err := p.Track()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]LinkPoint, len(track))
+	for i, tp := range track {
+		pt := LinkPoint{Time: tp.Time, Angles: tp.Angles}
+		pt.PathLoss, pt.ReceivedPower, pt.CN0, pt.Margin = l.at(tp.Angles.Rg)
+		points[i] = pt
+	}
+	return points, nil
+}
@@ -0,0 +1,44 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Link", func() {
+	It("should compute the free space path loss", func() {
+		// 2 GHz over 1000 km
+		Expect(FreeSpacePathLoss(1000, 2e9)).To(BeNumerically("~", 158.46, 0.01))
+	})
+
+	It("should follow the range profile of a pass", func() {
+		sat := jobTestSatellites()[0]
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+		passes, err := PredictPasses(&sat, NewLatLongAlt(55.6167, 12.6500, 0.005), start, start.Add(12*time.Hour), PassOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+		Expect(passes).NotTo(BeEmpty())
+
+		// 2 W UHF downlink into a yagi
+		link := Link{Frequency: 437e6, TransmitPower: 3, ReceiveGain: 12, NoiseTemperature: 500, RequiredCN0: 50}
+		points, err := link.Budget(&passes[0])
+		Expect(err).To(BeNil())
+		Expect(points).NotTo(BeEmpty())
+
+		best := points[0]
+		for _, pt := range points {
+			Expect(pt.CN0 - pt.Margin).To(BeNumerically("~", 50, 1e-9))
+			Expect(pt.ReceivedPower).To(BeNumerically("~", 15-FreeSpacePathLoss(pt.Angles.Rg, 437e6), 1e-9))
+			if pt.CN0 > best.CN0 {
+				best = pt
+			}
+		}
+		// The strongest signal comes with the shortest range, near culmination
+		Expect(best.Time).To(BeTemporally("~", passes[0].TCA, 10*time.Second))
+		Expect(best.CN0).To(BeNumerically(">", points[0].CN0))
+
+		_, err = Link{}.Budget(&passes[0])
+		Expect(err).NotTo(BeNil())
+	})
+})
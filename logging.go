@@ -0,0 +1,34 @@
+package satellite
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+var pkgLogger atomic.Pointer[slog.Logger]
+
+// Sets the logger used for progress and anomaly logging by the package and its
+// subsystems. Logging is disabled until a logger is set; passing nil disables it again.
+func SetLogger(l *slog.Logger) {
+	pkgLogger.Store(l)
+}
+
+// Returns the logger set by SetLogger or a logger discarding everything when none is set.
+// Subpackages such as celestrak and spacetrack log through it.
+func Logger() *slog.Logger {
+	if l := pkgLogger.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+var discardLogger = slog.New(discardHandler{})
+
+// slog.Handler that drops all records
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
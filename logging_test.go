@@ -0,0 +1,36 @@
+package satellite
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+	AfterEach(func() {
+		SetLogger(nil)
+	})
+
+	It("should discard records when no logger is set", func() {
+		Expect(Logger().Enabled(context.Background(), slog.LevelError)).To(BeFalse())
+	})
+
+	It("should log propagation failures to the configured logger", func() {
+		var buf bytes.Buffer
+		SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+		sat, err := NewSatFromTLE(
+			"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+			"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+			"wgs72")
+		Expect(err).To(BeNil())
+
+		_, _, err = sat.PropagateJDay(JDay{Day: sat.jdsatepoch.Day + 1e7/1440, Fraction: sat.jdsatepoch.Fraction})
+		Expect(err).To(Not(BeNil()))
+		Expect(buf.String()).To(ContainSubstring("propagation failed"))
+		Expect(buf.String()).To(ContainSubstring("satnum=6251"))
+	})
+})
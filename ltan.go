@@ -0,0 +1,120 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Mean motion of the mean sun in right ascension in rad/day
+const meanSunRate = 0.9856474 * DEG2RAD
+
+// Returns the right ascension of the mean sun in radians for a julian date
+func meanSunRightAscension(jday float64) float64 {
+	return float64(Radians((280.460 + 0.9856474*(jday-2451545.0)) * DEG2RAD).Normalize())
+}
+
+// Returns the mean local time of a node with the given right ascension in hours
+func localTimeOfNode(raan, jday float64) float64 {
+	return float64(Radians(raan-meanSunRightAscension(jday)+math.Pi).Normalize()) * 12 / math.Pi
+}
+
+// Local time of the ascending node at a moment
+type LTANSample struct {
+	Time time.Time
+
+	// Mean local solar time in hours in the range [0, 24)
+	Hours float64
+}
+
+// Returns the mean local time of the ascending node in hours at the element set epoch
+func (sat *Satellite) LTAN() float64 {
+	return localTimeOfNode(sat.nodeo, sat.jdsatepoch.Single())
+}
+
+// Returns the mean local time of the ascending node in hours predicted for t from the
+// J2 nodal precession
+func (sat *Satellite) PredictLTAN(t time.Time) float64 {
+	return localTimeOfNode(sat.PredictRAAN(t), NewJDayFromTime(t.UTC()).Single())
+}
+
+// Returns the drift of the local time of the ascending node implied by J2 in minutes per year
+func (sat *Satellite) LTANDriftRate() float64 {
+	return ltanMinutesPerYear(sat.NodalPrecessionRate() - meanSunRate)
+}
+
+// Converts a node drift relative to the mean sun from rad/day to minutes of local time per year
+func ltanMinutesPerYear(radPerDay float64) float64 {
+	return radPerDay * 12 / math.Pi * 60 * 365.25
+}
+
+// Local time of ascending node drift observed in an element set history
+type LTANDrift struct {
+	// Epochs of the first and last element sets and the number of sets used
+	Start, Stop time.Time
+	Samples     int
+
+	// Local time of the ascending node at each element set epoch in epoch order
+	History []LTANSample
+
+	// Least squares drift fitted to the history and the mean J2 drift predicted from
+	// the element sets, both in minutes per year
+	Observed, Predicted float64
+
+	// Local time predicted for the forecast time from the latest element set
+	Forecast LTANSample
+}
+
+// Tracks the local time of the ascending node of a sun-synchronous satellite over a
+// history of element sets and predicts it forward to forecast from the latest set
+func MonitorLTAN(history []Satellite, forecast time.Time) (LTANDrift, error) {
+	if len(history) < 2 {
+		return LTANDrift{}, errors.New("LTAN monitoring needs at least two element sets")
+	}
+	sets := append([]Satellite(nil), history...)
+	sort.Slice(sets, func(i, j int) bool { return sets[i].jdsatepoch.Single() < sets[j].jdsatepoch.Single() })
+
+	first := sets[0].jdsatepoch
+	days := make([]float64, len(sets))
+	hours := make([]float64, len(sets))
+	samples := make([]LTANSample, len(sets))
+	predicted := 0.0
+	for i := range sets {
+		samples[i] = LTANSample{Time: sets[i].jdsatepoch.ToTime(), Hours: sets[i].LTAN()}
+		days[i] = sets[i].jdsatepoch.SubtractDay(first) / 1440
+		hours[i] = samples[i].Hours
+		if i > 0 {
+			// Unwrap so the fitted line does not jump at midnight
+			hours[i] = hours[i-1] + math.Remainder(hours[i]-hours[i-1], 24)
+		}
+		predicted += sets[i].LTANDriftRate()
+	}
+	predicted /= float64(len(sets))
+
+	slope, ok := linearSlope(days, hours)
+	if !ok {
+		return LTANDrift{}, errors.New("element sets must span more than one epoch")
+	}
+	latest := &sets[len(sets)-1]
+	return LTANDrift{
+		Start:     samples[0].Time,
+		Stop:      samples[len(samples)-1].Time,
+		Samples:   len(sets),
+		History:   samples,
+		Observed:  slope * 60 * 365.25,
+		Predicted: predicted,
+		Forecast:  LTANSample{Time: forecast, Hours: latest.PredictLTAN(forecast)},
+	}, nil
+}
+
+// Returns the solar beta angle in radians at t: the angle between the orbit plane and the
+// sun vector, positive when the sun is on the side of the orbit normal. The orbit plane is
+// taken from the mean inclination and the node predicted for t from the J2 nodal precession.
+func (sat *Satellite) BetaAngle(t time.Time) float64 {
+	sinNode, cosNode := math.Sincos(sat.PredictRAAN(t))
+	sinIncl, cosIncl := math.Sincos(sat.inclo)
+	normal := Vector3{X: sinIncl * sinNode, Y: -sinIncl * cosNode, Z: cosIncl}
+	sun := SunPositionECI(NewJDayFromTime(t.UTC()))
+	return math.Asin(math.Max(-1, math.Min(1, dot(normal, sun)/sun.Magnitude())))
+}
@@ -0,0 +1,73 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LTAN", func() {
+	It("should compute the local time of the ascending node at epoch", func() {
+		sat := jobTestSatellites()[3]
+		// Mean sun is near 179.8 deg in late September, the node at 120.2 deg
+		Expect(sat.LTAN()).To(BeNumerically("~", 8.03, 0.01))
+	})
+
+	It("should keep the local time nearly fixed for a sun-synchronous orbit", func() {
+		sat := jobTestSatellites()[3]
+		t := sat.jdsatepoch.ToTime().AddDate(1, 0, 0)
+		drift := sat.LTANDriftRate()
+		Expect(math.Abs(drift)).To(BeNumerically("<", 360))
+		Expect(math.Remainder(sat.PredictLTAN(t)-sat.LTAN(), 24) * 60).To(BeNumerically("~", drift, 2))
+	})
+
+	It("should fit the drift of an element set history", func() {
+		// Node advancing 2.5 deg/year faster than the mean sun drifts 10 min/year
+		var history []Satellite
+		for d := 0.0; d <= 96; d += 8 {
+			history = append(history, ssoTLE.after(d).node(120.2160+(0.9856474+2.5/365.25)*d).sat())
+		}
+		history[2], history[7] = history[7], history[2]
+
+		drift, err := MonitorLTAN(history, history[0].jdsatepoch.ToTime().AddDate(2, 0, 0))
+		Expect(err).To(BeNil())
+		Expect(drift.Samples).To(Equal(13))
+		Expect(drift.Observed).To(BeNumerically("~", 10, 0.1))
+		Expect(drift.Predicted).To(BeNumerically("~", history[0].LTANDriftRate(), 1e-6))
+		Expect(drift.History[0].Hours).To(BeNumerically("~", 8.03, 0.01))
+		Expect(drift.Stop.After(drift.Start)).To(BeTrue())
+
+		latest := drift.History[len(drift.History)-1]
+		want := latest.Hours + drift.Predicted/60*drift.Forecast.Time.Sub(latest.Time).Hours()/24/365.25
+		Expect(math.Remainder(drift.Forecast.Hours-want, 24)).To(BeNumerically("~", 0, 0.05))
+	})
+
+	It("should reject a single element set", func() {
+		_, err := MonitorLTAN(jobTestSatellites()[3:], jobTestSatellites()[3].jdsatepoch.ToTime())
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("BetaAngle", func() {
+	It("should follow the local time of the node near the equinox", func() {
+		noon := ssoTLE.node(178.6).sat()
+		Expect(noon.BetaAngle(noon.jdsatepoch.ToTime()) * RAD2DEG).To(BeNumerically("~", 0, 1.5))
+
+		dusk := ssoTLE.node(268.6).sat()
+		Expect(dusk.BetaAngle(dusk.jdsatepoch.ToTime()) * RAD2DEG).To(BeNumerically("~", 80.96, 1.5))
+	})
+
+	It("should match the osculating orbit normal", func() {
+		for _, sat := range jobTestSatellites() {
+			t := sat.jdsatepoch.ToTime().Add(36 * time.Hour)
+			s, err := sat.StateAt(t)
+			Expect(err).To(BeNil())
+			h := cross(s.Position, s.Velocity)
+			sun := SunPositionECI(NewJDayFromTime(t))
+			want := math.Asin(dot(h, sun) / (h.Magnitude() * sun.Magnitude()))
+			Expect(sat.BetaAngle(t)).To(BeNumerically("~", want, 0.5*DEG2RAD))
+		}
+	})
+})
@@ -0,0 +1,128 @@
+// Package mqtt publishes satellite positions and pass events to an MQTT broker.
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Destination of published messages. Client implements it; adapters around other MQTT
+// client libraries can be used with Publisher as well. An error wrapping
+// ErrConnectionLost stops the Publisher, any other error is reported and publishing goes on.
+type Broker interface {
+	Publish(topic string, payload []byte) error
+}
+
+// Returned by Client.Publish once the connection to the broker is lost and not being
+// restored
+var ErrConnectionLost = errors.New("mqtt: connection lost")
+
+// Connection settings for Dial
+type Options struct {
+	ClientID           string
+	Username, Password string
+
+	// Interval of keep alive pings, 30 seconds when zero
+	KeepAlive time.Duration
+
+	// Quality of service of published messages, 0, 1 or 2
+	QoS byte
+
+	// Reconnects after a lost connection instead of failing every later publish with
+	// ErrConnectionLost. Publishes made while reconnecting return an error.
+	AutoReconnect bool
+}
+
+// MQTT 3.1.1 client publishing through the Eclipse Paho client
+type Client struct {
+	client    paho.Client
+	qos       byte
+	reconnect bool
+
+	mu   sync.Mutex
+	lost error
+}
+
+// Connects to the broker at the given URL, for example tcp://localhost:1883 or
+// ssl://broker.example.com:8883
+func Dial(ctx context.Context, broker string, opts Options) (*Client, error) {
+	if opts.QoS > 2 {
+		return nil, fmt.Errorf("mqtt: invalid QoS %d", opts.QoS)
+	}
+	c := &Client{qos: opts.QoS, reconnect: opts.AutoReconnect}
+
+	po := paho.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(opts.ClientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetProtocolVersion(4).
+		SetCleanSession(true).
+		SetAutoReconnect(opts.AutoReconnect).
+		SetConnectRetry(false).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			if opts.AutoReconnect {
+				return
+			}
+			c.mu.Lock()
+			c.lost = fmt.Errorf("%w: %v", ErrConnectionLost, err)
+			c.mu.Unlock()
+		})
+	if opts.KeepAlive > 0 {
+		po.SetKeepAlive(opts.KeepAlive)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		po.SetConnectTimeout(time.Until(deadline))
+	}
+	c.client = paho.NewClient(po)
+
+	if err := wait(ctx, c.client.Connect()); err != nil {
+		c.client.Disconnect(0)
+		return nil, fmt.Errorf("mqtt connect: %w", err)
+	}
+	return c, nil
+}
+
+// Publishes a message at the QoS of the options, waiting for the broker to acknowledge
+// it above QoS 0
+func (c *Client) Publish(topic string, payload []byte) error {
+	c.mu.Lock()
+	lost := c.lost
+	c.mu.Unlock()
+	if lost != nil {
+		return lost
+	}
+	if err := wait(context.Background(), c.client.Publish(topic, c.qos, false, payload)); err != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.lost != nil {
+			return c.lost
+		}
+		if !c.reconnect && !c.client.IsConnected() {
+			return fmt.Errorf("%w: %v", ErrConnectionLost, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Disconnects from the broker
+func (c *Client) Close() error {
+	c.client.Disconnect(250)
+	return nil
+}
+
+// Waits for the token to complete or the context to be done
+func wait(ctx context.Context, t paho.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,265 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type message struct {
+	topic   string
+	payload []byte
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	messages []message
+}
+
+func (r *recorder) Publish(topic string, payload []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, message{topic, payload})
+	return nil
+}
+
+// Packet received by testBroker, the first header byte and the body
+type testPacket struct {
+	typ  byte
+	body []byte
+}
+
+// Accepts one connection, answers CONNECT with the given return code and forwards every
+// packet it reads until the connection is closed
+func testBroker(code byte) (net.Listener, <-chan testPacket, <-chan net.Conn) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(BeNil())
+	packets := make(chan testPacket, 16)
+	conns := make(chan net.Conn, 1)
+	go func() {
+		defer close(packets)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conns <- conn
+		r := bufio.NewReader(conn)
+		for {
+			typ, body, err := readTestPacket(r)
+			if err != nil {
+				return
+			}
+			packets <- testPacket{typ, body}
+			if typ&0xf0 == 1<<4 {
+				conn.Write([]byte{2 << 4, 2, 0, code})
+			}
+		}
+	}()
+	return ln, packets, conns
+}
+
+// Reads one MQTT packet
+func readTestPacket(r *bufio.Reader) (byte, []byte, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, shift := 0, 0
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		shift += 7
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	return typ, body, err
+}
+
+// Returns the length prefixed string at the start of b and the rest of b
+func testString(b []byte) (string, []byte) {
+	n := binary.BigEndian.Uint16(b)
+	return string(b[2 : 2+n]), b[2+n:]
+}
+
+var _ = Describe("Client", func() {
+	It("should connect and publish at QoS 0", func() {
+		ln, packets, _ := testBroker(0)
+		defer ln.Close()
+
+		c, err := Dial(context.Background(), "tcp://"+ln.Addr().String(), Options{ClientID: "rotator", Username: "u", Password: "p"})
+		Expect(err).To(BeNil())
+		Expect(c.Publish("satellite/25544/position", []byte(`{"satnum":25544}`))).To(Succeed())
+		Expect(c.Close()).To(Succeed())
+
+		var received []testPacket
+		for p := range packets {
+			received = append(received, p)
+		}
+		Expect(received).To(HaveLen(3))
+
+		connect := received[0].body
+		protocol, rest := testString(connect)
+		Expect(protocol).To(Equal("MQTT"))
+		Expect(rest[0]).To(Equal(byte(4)))
+		Expect(rest[1]).To(Equal(byte(0xc2)))
+		clientID, rest := testString(rest[4:])
+		Expect(clientID).To(Equal("rotator"))
+		username, rest := testString(rest)
+		Expect(username).To(Equal("u"))
+		password, _ := testString(rest)
+		Expect(password).To(Equal("p"))
+
+		Expect(received[1].typ).To(Equal(byte(3 << 4)))
+		topic, payload := testString(received[1].body)
+		Expect(topic).To(Equal("satellite/25544/position"))
+		Expect(string(payload)).To(Equal(`{"satnum":25544}`))
+		Expect(received[2].typ).To(Equal(byte(14 << 4)))
+	})
+
+	It("should report a refused connection", func() {
+		ln, _, _ := testBroker(5)
+		defer ln.Close()
+		_, err := Dial(context.Background(), "tcp://"+ln.Addr().String(), Options{ClientID: "x"})
+		Expect(err).To(MatchError(ContainSubstring("mqtt connect")))
+	})
+
+	It("should reject an invalid QoS", func() {
+		_, err := Dial(context.Background(), "tcp://127.0.0.1:1", Options{QoS: 3})
+		Expect(err).To(MatchError("mqtt: invalid QoS 3"))
+	})
+
+	It("should fail publishing once the connection is lost", func() {
+		ln, packets, conns := testBroker(0)
+		defer ln.Close()
+
+		c, err := Dial(context.Background(), "tcp://"+ln.Addr().String(), Options{ClientID: "rotator"})
+		Expect(err).To(BeNil())
+		defer c.Close()
+		Expect(c.Publish("t", []byte("1"))).To(Succeed())
+
+		(<-conns).Close()
+		for range packets {
+		}
+		Eventually(func() error { return c.Publish("t", []byte("2")) }).Should(MatchError(ErrConnectionLost))
+	})
+})
+
+var _ = Describe("Publisher", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := satellite.NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should publish positions and pass events", func() {
+		iss, err := satellite.NewSatFromTLE(
+			"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		Expect(err).To(BeNil())
+
+		rec := &recorder{}
+		ticks := 0
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		p := &Publisher{
+			Broker:     rec,
+			Observer:   copenhagen,
+			Satellites: []satellite.Satellite{iss},
+			Topics:     Topics{Pass: "station/passes"},
+			Rate:       time.Microsecond,
+			OnError:    func(err error) { Fail(err.Error()) },
+		}
+		p.Now = func() time.Time {
+			ticks++
+			if ticks > 8*360 {
+				cancel()
+			}
+			return start.Add(time.Duration(ticks) * 10 * time.Second)
+		}
+		Expect(p.Run(ctx)).To(MatchError(context.Canceled))
+
+		want, err := satellite.PredictPasses(&iss, copenhagen, start.Add(10*time.Second), start.Add(8*time.Hour), satellite.PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(want).NotTo(BeEmpty())
+
+		var positions int
+		var events []PassEvent
+		for _, m := range rec.messages {
+			switch m.topic {
+			case "satellite/25544/position":
+				var pm PositionMessage
+				Expect(json.Unmarshal(m.payload, &pm)).To(Succeed())
+				Expect(pm.Satnum).To(Equal(int64(25544)))
+				positions++
+			case "station/passes":
+				var e PassEvent
+				Expect(json.Unmarshal(m.payload, &e)).To(Succeed())
+				events = append(events, e)
+			default:
+				Fail("unexpected topic " + m.topic)
+			}
+		}
+		Expect(positions).To(BeNumerically(">=", 8*360))
+		Expect(events).To(HaveLen(3 * len(want)))
+		for i, ps := range want {
+			Expect(events[3*i].Event).To(Equal(EventAOS))
+			Expect(events[3*i].Time).To(BeTemporally("~", ps.AOS, time.Millisecond))
+			Expect(events[3*i].Azimuth).To(BeNumerically("~", float64(satellite.Radians(ps.AOSAzimuth).Degrees()), 1e-3))
+			Expect(events[3*i].Elevation).To(BeNumerically("~", 0, 1e-2))
+			Expect(events[3*i+1].Event).To(Equal(EventTCA))
+			Expect(events[3*i+1].Elevation).To(BeNumerically("~", float64(satellite.Radians(ps.MaxElevation).Degrees()), 1e-6))
+			Expect(events[3*i+2].Event).To(Equal(EventLOS))
+			Expect(events[3*i+2].LOS).To(BeTemporally("~", ps.LOS, time.Millisecond))
+			Expect(events[3*i+2].Azimuth).To(BeNumerically("~", float64(satellite.Radians(ps.LOSAzimuth).Degrees()), 1e-3))
+		}
+	})
+	It("should stop when the broker connection is lost", func() {
+		iss, err := satellite.NewSatFromTLE(
+			"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		Expect(err).To(BeNil())
+
+		lost := fmt.Errorf("%w: EOF", ErrConnectionLost)
+		broker := &failingBroker{after: 3, err: lost}
+		var reported []error
+		p := &Publisher{
+			Broker:     broker,
+			Observer:   copenhagen,
+			Satellites: []satellite.Satellite{iss},
+			Rate:       time.Microsecond,
+			OnError:    func(err error) { reported = append(reported, err) },
+			Now:        func() time.Time { return start },
+		}
+		Expect(p.Run(context.Background())).To(Equal(lost))
+		Expect(broker.published).To(Equal(3))
+		Expect(reported).To(BeEmpty())
+	})
+})
+
+// Broker failing with err after the given number of publishes
+type failingBroker struct {
+	after, published int
+	err              error
+}
+
+func (b *failingBroker) Publish(topic string, payload []byte) error {
+	if b.published == b.after {
+		return b.err
+	}
+	b.published++
+	return nil
+}
@@ -0,0 +1,235 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+)
+
+// Topic templates of published messages, {satnum} is replaced by the catalog number
+type Topics struct {
+	Position string
+	Pass     string
+}
+
+// Topics used when a Publisher template is empty
+var DefaultTopics = Topics{
+	Position: "satellite/{satnum}/position",
+	Pass:     "satellite/{satnum}/pass",
+}
+
+// Position message, angles in degrees, distances in km and rates in km/s
+type PositionMessage struct {
+	Satnum    int64     `json:"satnum"`
+	Time      time.Time `json:"time"`
+	Latitude  float64   `json:"lat"`
+	Longitude float64   `json:"lon"`
+	Altitude  float64   `json:"alt"`
+	Azimuth   float64   `json:"az"`
+	Elevation float64   `json:"el"`
+	Range     float64   `json:"range"`
+	RangeRate float64   `json:"range_rate"`
+}
+
+// Pass event kinds
+const (
+	EventAOS = "aos"
+	EventTCA = "tca"
+	EventLOS = "los"
+)
+
+// Pass event message sent at acquisition, closest approach and loss of signal, angles in degrees
+type PassEvent struct {
+	Event  string    `json:"event"`
+	Satnum int64     `json:"satnum"`
+	Time   time.Time `json:"time"`
+
+	Azimuth   float64 `json:"az"`
+	Elevation float64 `json:"el"`
+
+	AOS          time.Time `json:"aos"`
+	TCA          time.Time `json:"tca"`
+	LOS          time.Time `json:"los"`
+	MaxElevation float64   `json:"max_el"`
+}
+
+// Publishes positions of satellites seen from an observer at a fixed rate, and pass
+// events as they happen
+type Publisher struct {
+	Broker     Broker
+	Observer   satellite.LatLongAlt
+	Satellites []satellite.Satellite
+
+	// Topic templates, DefaultTopics for empty fields
+	Topics Topics
+
+	// Interval between position messages, one second when zero
+	Rate time.Duration
+
+	// Pass search settings
+	Passes satellite.PassOptions
+
+	// Called with propagation and publishing errors, which do not stop the publisher. A
+	// lost broker connection is not reported here, Run returns it.
+	OnError func(error)
+
+	// Source of the current time, time.Now when nil
+	Now func() time.Time
+}
+
+// Length of the pass predictions, refreshed when half has elapsed
+const passHorizon = 24 * time.Hour
+
+// Runs until the context is done or the broker connection is lost and returns the
+// context error or the error wrapping ErrConnectionLost
+func (p *Publisher) Run(ctx context.Context) error {
+	rate := p.Rate
+	if rate <= 0 {
+		rate = time.Second
+	}
+	now := p.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	trackers := make([]*satellite.Tracker, len(p.Satellites))
+	for i := range p.Satellites {
+		trackers[i] = satellite.NewTracker(&p.Satellites[i], p.Observer)
+	}
+	passes := make([][]satellite.Pass, len(p.Satellites))
+	var refresh time.Time
+	var last time.Time
+
+	ticker := time.NewTicker(rate)
+	defer ticker.Stop()
+	for {
+		t := now()
+		if last.IsZero() {
+			last = t
+		}
+		if !t.Before(refresh) {
+			for i := range p.Satellites {
+				// Starting at the previous tick keeps a pass in progress from reporting a second AOS
+				found, err := satellite.PredictPasses(&p.Satellites[i], p.Observer, last, t.Add(passHorizon), p.Passes)
+				if err != nil {
+					p.fail(err)
+				}
+				passes[i] = found
+			}
+			refresh = t.Add(passHorizon / 2)
+		}
+
+		for i, tr := range trackers {
+			u, err := tr.Update(t)
+			if err != nil {
+				p.fail(err)
+				continue
+			}
+			err = p.publish(p.topic(p.Topics.Position, DefaultTopics.Position, u.Satnum), PositionMessage{
+				Satnum:    u.Satnum,
+				Time:      t.UTC(),
+				Latitude:  degrees(u.Position.LatLong.Latitude),
+				Longitude: degrees(u.Position.LatLong.Longitude),
+				Altitude:  u.Position.AltitudeKm,
+				Azimuth:   degrees(u.Angles.Az),
+				Elevation: degrees(u.Angles.El),
+				Range:     u.Angles.Rg,
+				RangeRate: u.RangeRate,
+			})
+			if err != nil {
+				return err
+			}
+			events, err := passEvents(tr, passes[i], last, t)
+			if err != nil {
+				p.fail(err)
+			}
+			for _, e := range events {
+				if err := p.publish(p.topic(p.Topics.Pass, DefaultTopics.Pass, e.Satnum), e); err != nil {
+					return err
+				}
+			}
+		}
+		last = t
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the pass events after from up to and including to, in time order, with the look
+// angles the tracker computes at each event. Events that fail to propagate are left out.
+func passEvents(tr *satellite.Tracker, passes []satellite.Pass, from, to time.Time) ([]PassEvent, error) {
+	var out []PassEvent
+	var errs []error
+	for _, ps := range passes {
+		base := PassEvent{
+			Satnum:       ps.Satnum,
+			AOS:          ps.AOS.UTC(),
+			TCA:          ps.TCA.UTC(),
+			LOS:          ps.LOS.UTC(),
+			MaxElevation: degrees(ps.MaxElevation),
+		}
+		for _, e := range []struct {
+			kind string
+			t    time.Time
+		}{
+			{EventAOS, ps.AOS},
+			{EventTCA, ps.TCA},
+			{EventLOS, ps.LOS},
+		} {
+			if !e.t.After(from) || e.t.After(to) {
+				continue
+			}
+			u, err := tr.Update(e.t)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ev := base
+			ev.Event, ev.Time, ev.Azimuth, ev.Elevation = e.kind, e.t.UTC(), degrees(u.Angles.Az), degrees(u.Angles.El)
+			out = append(out, ev)
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// Publishes the message as JSON and returns an error only when the connection is lost,
+// other errors are reported to OnError
+func (p *Publisher) publish(topic string, msg any) error {
+	b, err := json.Marshal(msg)
+	if err == nil {
+		err = p.Broker.Publish(topic, b)
+	}
+	if errors.Is(err, ErrConnectionLost) {
+		return err
+	}
+	if err != nil {
+		p.fail(err)
+	}
+	return nil
+}
+
+func (p *Publisher) fail(err error) {
+	if p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+func (p *Publisher) topic(template, fallback string, satnum int64) string {
+	if template == "" {
+		template = fallback
+	}
+	return strings.ReplaceAll(template, "{satnum}", strconv.FormatInt(satnum, 10))
+}
+
+func degrees(rad float64) float64 {
+	return float64(satellite.Radians(rad).Degrees())
+}
@@ -0,0 +1,289 @@
+package satellite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Contents of a CCSDS Navigation Data Message, the XML container combining messages
+// of different kinds. Messages are kept in document order within each kind.
+type NDM struct {
+	OMM []GPElements
+	OEM []OEMSegment
+	CDM []ConjunctionMessage
+}
+
+// CCSDS Conjunction Data Message between two objects
+type ConjunctionMessage struct {
+	MessageID string
+	Created   time.Time
+
+	// Time of closest approach, miss distance in km and relative speed in km/s
+	TCA           time.Time
+	MissDistance  float64
+	RelativeSpeed float64
+
+	// Probability of collision, zero when not given
+	CollisionProbability float64
+
+	Object1, Object2 CDMObject
+}
+
+// Identification of an object of a conjunction data message
+type CDMObject struct {
+	// Catalog number and international designator
+	Designator              int64
+	InternationalDesignator string
+	Name                    string
+}
+
+// Returns the conjunction between the two objects of the message
+func (m *ConjunctionMessage) Conjunction() Conjunction {
+	return Conjunction{
+		Primary:       m.Object1.Designator,
+		Secondary:     m.Object2.Designator,
+		TCA:           m.TCA,
+		MissDistance:  m.MissDistance,
+		RelativeSpeed: m.RelativeSpeed,
+	}
+}
+
+// XML layout of the messages, only the parts that are kept
+type (
+	xmlOMM struct {
+		Segments []struct {
+			Metadata struct {
+				ObjectName string `xml:"OBJECT_NAME"`
+				ObjectID   string `xml:"OBJECT_ID"`
+			} `xml:"metadata"`
+			Data struct {
+				Mean struct {
+					Epoch           string  `xml:"EPOCH"`
+					MeanMotion      float64 `xml:"MEAN_MOTION"`
+					Eccentricity    float64 `xml:"ECCENTRICITY"`
+					Inclination     float64 `xml:"INCLINATION"`
+					RAOfAscNode     float64 `xml:"RA_OF_ASC_NODE"`
+					ArgOfPericenter float64 `xml:"ARG_OF_PERICENTER"`
+					MeanAnomaly     float64 `xml:"MEAN_ANOMALY"`
+				} `xml:"meanElements"`
+				TLE struct {
+					EphemerisType      int     `xml:"EPHEMERIS_TYPE"`
+					ClassificationType string  `xml:"CLASSIFICATION_TYPE"`
+					NoradCatID         int64   `xml:"NORAD_CAT_ID"`
+					ElementSetNo       int64   `xml:"ELEMENT_SET_NO"`
+					RevAtEpoch         int64   `xml:"REV_AT_EPOCH"`
+					Bstar              float64 `xml:"BSTAR"`
+					MeanMotionDot      float64 `xml:"MEAN_MOTION_DOT"`
+					MeanMotionDDot     float64 `xml:"MEAN_MOTION_DDOT"`
+				} `xml:"tleParameters"`
+			} `xml:"data"`
+		} `xml:"body>segment"`
+	}
+
+	xmlOEM struct {
+		Segments []struct {
+			Metadata struct {
+				ObjectName          string `xml:"OBJECT_NAME"`
+				ObjectID            string `xml:"OBJECT_ID"`
+				CenterName          string `xml:"CENTER_NAME"`
+				RefFrame            string `xml:"REF_FRAME"`
+				TimeSystem          string `xml:"TIME_SYSTEM"`
+				UseableStart        string `xml:"USEABLE_START_TIME"`
+				UseableStop         string `xml:"USEABLE_STOP_TIME"`
+				Interpolation       string `xml:"INTERPOLATION"`
+				InterpolationDegree int    `xml:"INTERPOLATION_DEGREE"`
+			} `xml:"metadata"`
+			States []struct {
+				Epoch string  `xml:"EPOCH"`
+				X     float64 `xml:"X"`
+				Y     float64 `xml:"Y"`
+				Z     float64 `xml:"Z"`
+				XDot  float64 `xml:"X_DOT"`
+				YDot  float64 `xml:"Y_DOT"`
+				ZDot  float64 `xml:"Z_DOT"`
+			} `xml:"data>stateVector"`
+		} `xml:"body>segment"`
+	}
+
+	xmlCDM struct {
+		MessageID string `xml:"header>MESSAGE_ID"`
+		Created   string `xml:"header>CREATION_DATE"`
+		Relative  struct {
+			TCA                  string  `xml:"TCA"`
+			MissDistance         float64 `xml:"MISS_DISTANCE"`
+			RelativeSpeed        float64 `xml:"RELATIVE_SPEED"`
+			CollisionProbability float64 `xml:"COLLISION_PROBABILITY"`
+		} `xml:"body>relativeMetadataData"`
+		Segments []struct {
+			Object                  string `xml:"metadata>OBJECT"`
+			Designator              string `xml:"metadata>OBJECT_DESIGNATOR"`
+			InternationalDesignator string `xml:"metadata>INTERNATIONAL_DESIGNATOR"`
+			Name                    string `xml:"metadata>OBJECT_NAME"`
+		} `xml:"body>segment"`
+	}
+)
+
+// Reads a Navigation Data Message and dispatches the embedded OMM, OEM and CDM messages.
+// A document holding a single message of one of these kinds without the ndm container
+// is accepted as well. Other message kinds are skipped.
+// Reference: CCSDS 505.0-B-2, XML Specification for Navigation Data Messages.
+func ReadNDM(r io.Reader) (*NDM, error) {
+	ndm := &NDM{}
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return ndm, nil
+		}
+		if err != nil {
+			return ndm, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(start.Name.Local) {
+		case "ndm":
+			// Container, its children are read by the loop
+		case "omm":
+			var m xmlOMM
+			if err := dec.DecodeElement(&m, &start); err != nil {
+				return ndm, fmt.Errorf("NDM omm: %w", err)
+			}
+			if err := ndm.addOMM(&m); err != nil {
+				return ndm, err
+			}
+		case "oem":
+			var m xmlOEM
+			if err := dec.DecodeElement(&m, &start); err != nil {
+				return ndm, fmt.Errorf("NDM oem: %w", err)
+			}
+			if err := ndm.addOEM(&m); err != nil {
+				return ndm, err
+			}
+		case "cdm":
+			var m xmlCDM
+			if err := dec.DecodeElement(&m, &start); err != nil {
+				return ndm, fmt.Errorf("NDM cdm: %w", err)
+			}
+			if err := ndm.addCDM(&m); err != nil {
+				return ndm, err
+			}
+		default:
+			if err := dec.Skip(); err != nil {
+				return ndm, err
+			}
+		}
+	}
+}
+
+func (ndm *NDM) addOMM(m *xmlOMM) error {
+	for _, seg := range m.Segments {
+		mean, tle := &seg.Data.Mean, &seg.Data.TLE
+		epoch, err := parseGPEpoch(mean.Epoch)
+		if err != nil {
+			return fmt.Errorf("NDM omm %s: %w", seg.Metadata.ObjectName, err)
+		}
+		ndm.OMM = append(ndm.OMM, GPElements{
+			ObjectName:         strings.TrimSpace(seg.Metadata.ObjectName),
+			ObjectID:           strings.TrimSpace(seg.Metadata.ObjectID),
+			NoradCatID:         tle.NoradCatID,
+			Epoch:              epoch,
+			MeanMotion:         mean.MeanMotion,
+			Eccentricity:       mean.Eccentricity,
+			Inclination:        mean.Inclination,
+			RAOfAscNode:        mean.RAOfAscNode,
+			ArgOfPericenter:    mean.ArgOfPericenter,
+			MeanAnomaly:        mean.MeanAnomaly,
+			EphemerisType:      tle.EphemerisType,
+			ClassificationType: strings.TrimSpace(tle.ClassificationType),
+			ElementSetNo:       tle.ElementSetNo,
+			RevAtEpoch:         tle.RevAtEpoch,
+			Bstar:              tle.Bstar,
+			MeanMotionDot:      tle.MeanMotionDot,
+			MeanMotionDDot:     tle.MeanMotionDDot,
+		})
+	}
+	return nil
+}
+
+func (ndm *NDM) addOEM(m *xmlOEM) error {
+	for _, seg := range m.Segments {
+		md := &seg.Metadata
+		out := OEMSegment{
+			ObjectName: strings.TrimSpace(md.ObjectName),
+			ObjectID:   strings.TrimSpace(md.ObjectID),
+			CenterName: strings.TrimSpace(md.CenterName),
+			RefFrame:   strings.TrimSpace(md.RefFrame),
+			TimeSystem: strings.TrimSpace(md.TimeSystem),
+
+			Interpolation:       strings.TrimSpace(md.Interpolation),
+			InterpolationDegree: md.InterpolationDegree,
+		}
+		var err error
+		if s := strings.TrimSpace(md.UseableStart); s != "" {
+			if out.UseableStart, err = parseOEMTime(s); err != nil {
+				return fmt.Errorf("NDM oem %s: %w", out.ObjectName, err)
+			}
+		}
+		if s := strings.TrimSpace(md.UseableStop); s != "" {
+			if out.UseableStop, err = parseOEMTime(s); err != nil {
+				return fmt.Errorf("NDM oem %s: %w", out.ObjectName, err)
+			}
+		}
+		for _, sv := range seg.States {
+			t, err := parseOEMTime(strings.TrimSpace(sv.Epoch))
+			if err != nil {
+				return fmt.Errorf("NDM oem %s: %w", out.ObjectName, err)
+			}
+			out.States = append(out.States, State{
+				Time:     t,
+				Position: Vector3{sv.X, sv.Y, sv.Z},
+				Velocity: Vector3{sv.XDot, sv.YDot, sv.ZDot},
+			})
+		}
+		ndm.OEM = append(ndm.OEM, out)
+	}
+	return nil
+}
+
+func (ndm *NDM) addCDM(m *xmlCDM) error {
+	out := ConjunctionMessage{
+		MessageID:            strings.TrimSpace(m.MessageID),
+		MissDistance:         m.Relative.MissDistance / 1000,
+		RelativeSpeed:        m.Relative.RelativeSpeed / 1000,
+		CollisionProbability: m.Relative.CollisionProbability,
+	}
+	var err error
+	if out.TCA, err = parseOEMTime(strings.TrimSpace(m.Relative.TCA)); err != nil {
+		return fmt.Errorf("NDM cdm %s: %w", out.MessageID, err)
+	}
+	if s := strings.TrimSpace(m.Created); s != "" {
+		if out.Created, err = parseOEMTime(s); err != nil {
+			return fmt.Errorf("NDM cdm %s: %w", out.MessageID, err)
+		}
+	}
+	for _, seg := range m.Segments {
+		obj := CDMObject{
+			InternationalDesignator: strings.TrimSpace(seg.InternationalDesignator),
+			Name:                    strings.TrimSpace(seg.Name),
+		}
+		if d := strings.TrimSpace(seg.Designator); d != "" {
+			if obj.Designator, err = strconv.ParseInt(d, 10, 64); err != nil {
+				return fmt.Errorf("NDM cdm %s: bad object designator %q", out.MessageID, d)
+			}
+		}
+		switch strings.ToUpper(strings.TrimSpace(seg.Object)) {
+		case "OBJECT1":
+			out.Object1 = obj
+		case "OBJECT2":
+			out.Object2 = obj
+		}
+	}
+	ndm.CDM = append(ndm.CDM, out)
+	return nil
+}
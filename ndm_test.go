@@ -0,0 +1,116 @@
+package satellite
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const ndmXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ndm>
+  <omm id="CCSDS_OMM_VERS" version="2.0">
+    <header><CREATION_DATE>2008-09-20T13:00:00</CREATION_DATE><ORIGINATOR>18 SPCS</ORIGINATOR></header>
+    <body><segment>
+      <metadata>
+        <OBJECT_NAME>ISS (ZARYA)</OBJECT_NAME><OBJECT_ID>1998-067A</OBJECT_ID>
+        <CENTER_NAME>EARTH</CENTER_NAME><REF_FRAME>TEME</REF_FRAME><TIME_SYSTEM>UTC</TIME_SYSTEM>
+        <MEAN_ELEMENT_THEORY>SGP4</MEAN_ELEMENT_THEORY>
+      </metadata>
+      <data>
+        <meanElements>
+          <EPOCH>2008-09-20T12:25:40.104192</EPOCH>
+          <MEAN_MOTION>15.72125391</MEAN_MOTION><ECCENTRICITY>.0006703</ECCENTRICITY>
+          <INCLINATION>51.6416</INCLINATION><RA_OF_ASC_NODE>247.4627</RA_OF_ASC_NODE>
+          <ARG_OF_PERICENTER>130.5360</ARG_OF_PERICENTER><MEAN_ANOMALY>325.0288</MEAN_ANOMALY>
+        </meanElements>
+        <tleParameters>
+          <EPHEMERIS_TYPE>0</EPHEMERIS_TYPE><CLASSIFICATION_TYPE>U</CLASSIFICATION_TYPE>
+          <NORAD_CAT_ID>25544</NORAD_CAT_ID><ELEMENT_SET_NO>292</ELEMENT_SET_NO>
+          <REV_AT_EPOCH>56353</REV_AT_EPOCH><BSTAR>-.11606E-4</BSTAR>
+          <MEAN_MOTION_DOT>-.2182E-4</MEAN_MOTION_DOT><MEAN_MOTION_DDOT>0</MEAN_MOTION_DDOT>
+        </tleParameters>
+      </data>
+    </segment></body>
+  </omm>
+  <opm><header/><body/></opm>
+  <oem id="CCSDS_OEM_VERS" version="2.0">
+    <header><CREATION_DATE>2008-264T13:00:00</CREATION_DATE></header>
+    <body><segment>
+      <metadata>
+        <OBJECT_NAME>ISS</OBJECT_NAME><OBJECT_ID>1998-067A</OBJECT_ID><CENTER_NAME>EARTH</CENTER_NAME>
+        <REF_FRAME>TEME</REF_FRAME><TIME_SYSTEM>UTC</TIME_SYSTEM>
+        <START_TIME>2008-09-20T12:00:00</START_TIME><USEABLE_START_TIME>2008-09-20T12:00:00</USEABLE_START_TIME>
+        <USEABLE_STOP_TIME>2008-09-20T12:01:00</USEABLE_STOP_TIME><STOP_TIME>2008-09-20T12:01:00</STOP_TIME>
+      </metadata>
+      <data>
+        <stateVector><EPOCH>2008-09-20T12:00:00</EPOCH><X units="km">-4000.5</X><Y>3000.25</Y><Z>4300</Z><X_DOT>-2.5</X_DOT><Y_DOT>-6.25</Y_DOT><Z_DOT>2</Z_DOT></stateVector>
+        <stateVector><EPOCH>2008-264T12:01:00</EPOCH><X>-4140</X><Y>2620</Y><Z>4400</Z><X_DOT>-2.1</X_DOT><Y_DOT>-6.4</Y_DOT><Z_DOT>1.6</Z_DOT></stateVector>
+      </data>
+    </segment></body>
+  </oem>
+  <cdm id="CCSDS_CDM_VERS" version="1.0">
+    <header><CREATION_DATE>2008-09-20T14:00:00</CREATION_DATE><MESSAGE_ID>201 2008 0001</MESSAGE_ID></header>
+    <body>
+      <relativeMetadataData>
+        <TCA>2008-09-21T03:04:05.5</TCA><MISS_DISTANCE units="m">715</MISS_DISTANCE>
+        <RELATIVE_SPEED units="m/s">14762</RELATIVE_SPEED><COLLISION_PROBABILITY>4.835E-05</COLLISION_PROBABILITY>
+      </relativeMetadataData>
+      <segment><metadata><OBJECT>OBJECT1</OBJECT><OBJECT_DESIGNATOR>25544</OBJECT_DESIGNATOR><INTERNATIONAL_DESIGNATOR>1998-067A</INTERNATIONAL_DESIGNATOR><OBJECT_NAME>ISS (ZARYA)</OBJECT_NAME></metadata></segment>
+      <segment><metadata><OBJECT>OBJECT2</OBJECT><OBJECT_DESIGNATOR>30000</OBJECT_DESIGNATOR><OBJECT_NAME>DEBRIS</OBJECT_NAME></metadata></segment>
+    </body>
+  </cdm>
+</ndm>`
+
+var _ = Describe("ReadNDM", func() {
+	It("should dispatch the embedded messages", func() {
+		ndm, err := ReadNDM(strings.NewReader(ndmXML))
+		Expect(err).To(BeNil())
+
+		Expect(ndm.OMM).To(HaveLen(1))
+		gp := ndm.OMM[0]
+		Expect(gp.ObjectName).To(Equal("ISS (ZARYA)"))
+		Expect(gp.NoradCatID).To(Equal(int64(25544)))
+		Expect(gp.Bstar).To(Equal(-.11606e-4))
+		sat, err := NewSatFromGP(gp, "wgs72")
+		Expect(err).To(BeNil())
+		tle := jobTestSatellites()[0]
+		t := tle.jdsatepoch.ToTime().Add(time.Hour)
+		want, _, _ := tle.propagateAt(t)
+		got, _, _ := sat.propagateAt(t)
+		Expect(distance(got, want)).To(BeNumerically("<", 1e-3))
+
+		Expect(ndm.OEM).To(HaveLen(1))
+		seg := ndm.OEM[0]
+		Expect(seg.RefFrame).To(Equal("TEME"))
+		Expect(seg.UseableStop).To(Equal(time.Date(2008, 9, 20, 12, 1, 0, 0, time.UTC)))
+		Expect(seg.States).To(HaveLen(2))
+		Expect(seg.States[0].Position).To(Equal(Vector3{-4000.5, 3000.25, 4300}))
+		Expect(seg.States[1].Time).To(Equal(seg.UseableStop))
+		Expect(seg.States[1].Velocity).To(Equal(Vector3{-2.1, -6.4, 1.6}))
+
+		Expect(ndm.CDM).To(HaveLen(1))
+		cdm := ndm.CDM[0]
+		Expect(cdm.MessageID).To(Equal("201 2008 0001"))
+		Expect(cdm.CollisionProbability).To(Equal(4.835e-5))
+		Expect(cdm.Object2.Name).To(Equal("DEBRIS"))
+		c := cdm.Conjunction()
+		Expect(c.Primary).To(Equal(int64(25544)))
+		Expect(c.Secondary).To(Equal(int64(30000)))
+		Expect(c.TCA).To(Equal(time.Date(2008, 9, 21, 3, 4, 5, 500000000, time.UTC)))
+		Expect(c.MissDistance).To(BeNumerically("~", 0.715, 1e-12))
+		Expect(c.RelativeSpeed).To(BeNumerically("~", 14.762, 1e-12))
+	})
+
+	It("should accept a bare message and report bad epochs", func() {
+		i, j := strings.Index(ndmXML, "<cdm"), strings.Index(ndmXML, "</cdm>")
+		ndm, err := ReadNDM(strings.NewReader(ndmXML[i : j+len("</cdm>")]))
+		Expect(err).To(BeNil())
+		Expect(ndm.CDM).To(HaveLen(1))
+		Expect(ndm.OMM).To(BeEmpty())
+
+		_, err = ReadNDM(strings.NewReader(strings.Replace(ndmXML, "2008-09-20T12:25:40.104192", "yesterday", 1)))
+		Expect(err).To(MatchError(ContainSubstring("yesterday")))
+	})
+})
@@ -0,0 +1,106 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Measurement of a satellite from a ground station
+type Observation struct {
+	Time    time.Time
+	Station LatLongAlt
+
+	// Azimuth and elevation in radians
+	Az, El float64
+
+	// Topocentric right ascension and declination in radians in the inertial (TEME) frame
+	RightAscension, Declination float64
+
+	// Range in km and range rate in km/s, positive when receding
+	Range, RangeRate float64
+}
+
+// Per measurement error magnitudes, angles in radians, range in km, range rate in km/s
+type ObservationErrors struct {
+	Angle, Range, RangeRate float64
+}
+
+// Generates synthetic observations of a truth satellite from a station
+type ObservationSimulator struct {
+	Station LatLongAlt
+
+	// Standard deviation of the Gaussian noise and constant bias added to each measurement
+	Noise, Bias ObservationErrors
+
+	// Minimum elevation in radians for a measurement to be made
+	MinElevation Radians
+
+	// Source of the noise, a generator seeded with 1 when nil so runs are reproducible
+	Rand *rand.Rand
+}
+
+// Observes sat every step from start to stop, leaving out times below the minimum elevation
+func (s *ObservationSimulator) Simulate(sat *Satellite, start, stop time.Time, step time.Duration) ([]Observation, error) {
+	if !stop.After(start) {
+		return nil, errors.New("simulation stop time must be after start time")
+	}
+	if step <= 0 {
+		return nil, errors.New("simulation step must be positive")
+	}
+	if s.Rand == nil {
+		s.Rand = rand.New(rand.NewSource(1))
+	}
+	obsECEF := LLAToECEF(s.Station, sat.Gravity)
+
+	var out []Observation
+	for _, t := range sampleTimes(start, stop, step) {
+		o, err := observe(sat, s.Station, obsECEF, t)
+		if err != nil {
+			return out, err
+		}
+		if o.El < float64(s.MinElevation) {
+			continue
+		}
+
+		noise := func(sigma, bias float64) float64 {
+			return bias + sigma*s.Rand.NormFloat64()
+		}
+		o.Az = float64(Radians(o.Az + noise(s.Noise.Angle, s.Bias.Angle)).Normalize())
+		o.El += noise(s.Noise.Angle, s.Bias.Angle)
+		o.RightAscension = float64(Radians(o.RightAscension + noise(s.Noise.Angle, s.Bias.Angle)).Normalize())
+		o.Declination += noise(s.Noise.Angle, s.Bias.Angle)
+		o.Range += noise(s.Noise.Range, s.Bias.Range)
+		o.RangeRate += noise(s.Noise.RangeRate, s.Bias.RangeRate)
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// Returns the noise free observation of sat at t from a station at obsECEF
+func observe(sat *Satellite, station LatLongAlt, obsECEF Vector3, t time.Time) (Observation, error) {
+	eci, vel, err := sat.propagateAt(t)
+	if err != nil {
+		return Observation{}, err
+	}
+	return observeState(State{Time: t, Position: eci, Velocity: vel}, station, obsECEF), nil
+}
+
+// Returns the noise free observation of an inertial state from a station at obsECEF
+func observeState(s State, station LatLongAlt, obsECEF Vector3) Observation {
+	gmst := gmstAt(s.Time)
+	pos, ecfVel := ECIToECEFState(s.Position, s.Velocity, gmst)
+	la := ecefLookAngles(pos, obsECEF, station)
+	rho := ECEFToECI(Vector3{pos.X - obsECEF.X, pos.Y - obsECEF.Y, pos.Z - obsECEF.Z}, gmst)
+	return Observation{
+		Time:           s.Time,
+		Station:        station,
+		Az:             la.Az,
+		El:             la.El,
+		RightAscension: math.Atan2(rho.Y, rho.X),
+		Declination:    math.Asin(rho.Z / la.Rg),
+		Range:          la.Rg,
+		RangeRate:      topocentricRangeRate(pos, ecfVel, obsECEF),
+	}
+}
@@ -0,0 +1,71 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ObservationSimulator", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should produce exact measurements without noise", func() {
+		sat := jobTestSatellites()[0]
+		sim := &ObservationSimulator{Station: copenhagen, MinElevation: Degrees(10).Radians()}
+		obs, err := sim.Simulate(&sat, start, start.Add(12*time.Hour), 10*time.Second)
+		Expect(err).To(BeNil())
+		Expect(obs).NotTo(BeEmpty())
+
+		stationECEF := LLAToECEF(copenhagen, sat.Gravity)
+		for _, o := range obs {
+			Expect(o.El).To(BeNumerically(">=", 10*DEG2RAD))
+			pos, vel, err := sat.PropagateECEF(o.Time)
+			Expect(err).To(BeNil())
+			la := ecefLookAngles(pos, stationECEF, copenhagen)
+			Expect(o.Az).To(BeNumerically("~", la.Az, 1e-9))
+			Expect(o.Range).To(BeNumerically("~", la.Rg, 1e-6))
+			Expect(o.RangeRate).To(BeNumerically("~", topocentricRangeRate(pos, vel, stationECEF), 1e-9))
+
+			// Right ascension and declination point along the same line of sight
+			sinDec, cosDec := math.Sincos(o.Declination)
+			los := ECIToECEF(Vector3{cosDec * math.Cos(o.RightAscension), cosDec * math.Sin(o.RightAscension), sinDec}, gmstAt(o.Time))
+			Expect(elevationOf(Vector3{stationECEF.X + los.X*o.Range, stationECEF.Y + los.Y*o.Range, stationECEF.Z + los.Z*o.Range}, stationECEF, copenhagen)).
+				To(BeNumerically("~", o.El, 1e-9))
+		}
+	})
+
+	It("should add biased Gaussian noise reproducibly", func() {
+		sat := jobTestSatellites()[0]
+		noisy := func() []Observation {
+			sim := &ObservationSimulator{
+				Station:      copenhagen,
+				MinElevation: Degrees(10).Radians(),
+				Noise:        ObservationErrors{Angle: 0.01 * DEG2RAD, Range: 0.05, RangeRate: 0.001},
+				Bias:         ObservationErrors{Range: 0.2},
+			}
+			obs, err := sim.Simulate(&sat, start, start.Add(48*time.Hour), time.Second)
+			Expect(err).To(BeNil())
+			return obs
+		}
+		truth, err := (&ObservationSimulator{Station: copenhagen, MinElevation: Degrees(10).Radians()}).Simulate(&sat, start, start.Add(48*time.Hour), time.Second)
+		Expect(err).To(BeNil())
+		obs := noisy()
+
+		var mean, sq float64
+		for i := range obs {
+			Expect(obs[i].Time).To(Equal(truth[i].Time))
+			d := obs[i].Range - truth[i].Range
+			mean += d
+			sq += d * d
+		}
+		n := float64(len(obs))
+		mean /= n
+		Expect(mean).To(BeNumerically("~", 0.2, 0.005))
+		Expect(math.Sqrt(sq/n - mean*mean)).To(BeNumerically("~", 0.05, 0.005))
+
+		Expect(noisy()[10]).To(Equal(obs[10]))
+	})
+})
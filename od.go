@@ -0,0 +1,236 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Options for FitOrbit
+type ODOptions struct {
+	// Standard deviations of the measurements in radians, km and km/s. A measurement
+	// type takes part in the fit only when its deviation is positive, and the residuals
+	// are weighted by the inverse deviations.
+	Sigma ObservationErrors
+
+	// Also fit the BSTAR drag term
+	FitBstar bool
+
+	// Iteration limit, 20 when zero, and the change of the weighted RMS, relative to the
+	// RMS or one when smaller, below which the fit has converged, 1e-4 when zero
+	MaxIterations int
+	Tolerance     float64
+}
+
+// Difference between an observation and the fitted orbit, observed minus computed
+type ObservationResidual struct {
+	Time time.Time
+
+	// Azimuth and elevation in radians, range in km and range rate in km/s. The azimuth
+	// residual is scaled by the cosine of the elevation. Unused measurements are zero.
+	Az, El, Range, RangeRate float64
+}
+
+// Outcome of FitOrbit
+type ODResult struct {
+	// Satellite initialized from the fitted mean elements at the a priori epoch
+	Satellite Satellite
+
+	Iterations int
+	Converged  bool
+
+	// Root mean square of the weighted residuals, near one for a fit matching the
+	// measurement deviations, and the post-fit residuals
+	RMS       float64
+	Residuals []ObservationResidual
+
+	// Covariance of the fitted parameters in the order inclination, node, eccentricity,
+	// argument of perigee, mean anomaly (radians), Kozai mean motion (rad/min) and,
+	// when fitted, BSTAR
+	Covariance [][]float64
+}
+
+// Fits the mean elements of an element set to observations from one or more stations by
+// batch least squares differential correction, starting from the a priori satellite.
+// Partial derivatives of the measurements are computed by central differences.
+func FitOrbit(apriori *Satellite, obs []Observation, opts ODOptions) (ODResult, error) {
+	sigma := opts.Sigma
+	if sigma.Angle <= 0 && sigma.Range <= 0 && sigma.RangeRate <= 0 {
+		return ODResult{}, errors.New("orbit determination needs at least one measurement deviation")
+	}
+	maxIter := opts.MaxIterations
+	if maxIter <= 0 {
+		maxIter = 20
+	}
+	tol := opts.Tolerance
+	if tol <= 0 {
+		tol = 1e-4
+	}
+
+	x := apriori.meanElements().vector(opts.FitBstar)
+	res := ODResult{}
+	prevRMS := math.Inf(1)
+	for res.Iterations = 1; res.Iterations <= maxIter; res.Iterations++ {
+		sat, r, err := odResiduals(apriori, x, obs, sigma)
+		if err != nil {
+			return res, err
+		}
+		res.Satellite = sat
+		res.RMS = math.Sqrt(dotSlice(r, r) / float64(len(r)))
+		if len(r) <= len(x) {
+			return res, errors.New("orbit determination needs more measurements than parameters")
+		}
+
+		// Jacobian of the weighted residuals
+		j := newMatrix(len(r), len(x))
+		for k := range x {
+			h := odStep(k, x[k])
+			xp := append([]float64(nil), x...)
+			xm := append([]float64(nil), x...)
+			xp[k] += h
+			xm[k] -= h
+			_, rp, err := odResiduals(apriori, xp, obs, sigma)
+			if err != nil {
+				return res, err
+			}
+			_, rm, err := odResiduals(apriori, xm, obs, sigma)
+			if err != nil {
+				return res, err
+			}
+			for i := range r {
+				j[i][k] = (rp[i] - rm[i]) / (2 * h)
+			}
+		}
+
+		// Normal equations
+		n := newMatrix(len(x), len(x))
+		b := make([]float64, len(x))
+		for i := range r {
+			for p := range x {
+				b[p] += j[i][p] * r[i]
+				for q := range x {
+					n[p][q] += j[i][p] * j[i][q]
+				}
+			}
+		}
+		cov, err := invertMatrix(n)
+		if err != nil {
+			return res, errors.New("observations do not determine the orbit")
+		}
+		res.Covariance = cov
+
+		if math.Abs(prevRMS-res.RMS) <= tol*math.Max(res.RMS, 1) {
+			res.Converged = true
+			break
+		}
+		prevRMS = res.RMS
+
+		dx := mulMatVec(cov, b)
+		for k := range x {
+			x[k] -= dx[k]
+		}
+		// Keep the eccentricity physical
+		x[2] = math.Max(x[2], 1e-7)
+	}
+	if res.Iterations > maxIter {
+		res.Iterations = maxIter
+	}
+	res.Residuals = odObservationResiduals(&res.Satellite, obs, sigma)
+	return res, nil
+}
+
+// Returns the parameter vector of the fit
+func (el meanElements) vector(bstar bool) []float64 {
+	x := []float64{el.inclo, el.nodeo, el.ecco, el.argpo, el.mo, el.no}
+	if bstar {
+		x = append(x, el.bstar)
+	}
+	return x
+}
+
+// Returns the finite difference step of parameter k
+func odStep(k int, value float64) float64 {
+	switch k {
+	case 2:
+		return math.Max(1e-9, math.Min(1e-6, value/2))
+	case 5:
+		return 1e-8
+	case 6:
+		return 1e-6
+	}
+	return 1e-5
+}
+
+// Initializes a satellite from the parameter vector and returns it with its weighted residuals
+func odResiduals(apriori *Satellite, x []float64, obs []Observation, sigma ObservationErrors) (Satellite, []float64, error) {
+	el := apriori.meanElements()
+	el.inclo, el.nodeo, el.ecco, el.argpo, el.mo, el.no = x[0], x[1], x[2], x[3], x[4], x[5]
+	if len(x) > 6 {
+		el.bstar = x[6]
+	}
+	sat, err := apriori.withMeanElements(el)
+	if err != nil {
+		return sat, nil, err
+	}
+	r := make([]float64, 0, 4*len(obs))
+	for i := range obs {
+		o := &obs[i]
+		c, err := observe(&sat, o.Station, LLAToECEF(o.Station, sat.Gravity), o.Time)
+		if err != nil {
+			return sat, nil, err
+		}
+		d := observationDifference(o, &c)
+		if sigma.Angle > 0 {
+			r = append(r, d.Az/sigma.Angle, d.El/sigma.Angle)
+		}
+		if sigma.Range > 0 {
+			r = append(r, d.Range/sigma.Range)
+		}
+		if sigma.RangeRate > 0 {
+			r = append(r, d.RangeRate/sigma.RangeRate)
+		}
+	}
+	return sat, r, nil
+}
+
+// Returns the unweighted residuals of the used measurement types
+func odObservationResiduals(sat *Satellite, obs []Observation, sigma ObservationErrors) []ObservationResidual {
+	out := make([]ObservationResidual, 0, len(obs))
+	for i := range obs {
+		c, err := observe(sat, obs[i].Station, LLAToECEF(obs[i].Station, sat.Gravity), obs[i].Time)
+		if err != nil {
+			continue
+		}
+		d := observationDifference(&obs[i], &c)
+		if sigma.Angle <= 0 {
+			d.Az, d.El = 0, 0
+		}
+		if sigma.Range <= 0 {
+			d.Range = 0
+		}
+		if sigma.RangeRate <= 0 {
+			d.RangeRate = 0
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// Returns the observed minus computed measurements
+func observationDifference(o, c *Observation) ObservationResidual {
+	return ObservationResidual{
+		Time:      o.Time,
+		Az:        wrapPi(o.Az-c.Az) * math.Cos(c.El),
+		El:        o.El - c.El,
+		Range:     o.Range - c.Range,
+		RangeRate: o.RangeRate - c.RangeRate,
+	}
+}
+
+func dotSlice(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FitOrbit", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	stations := []LatLongAlt{
+		NewLatLongAlt(55.6167, 12.6500, 0.005),
+		NewLatLongAlt(-33.9, 18.4, 0.1),
+		NewLatLongAlt(40.4, -3.7, 0.6),
+	}
+
+	simulate := func(truth *Satellite, noise ObservationErrors) []Observation {
+		var obs []Observation
+		for i, st := range stations {
+			sim := &ObservationSimulator{Station: st, Noise: noise, MinElevation: Degrees(10).Radians()}
+			o, err := sim.Simulate(truth, start, start.Add(24*time.Hour), time.Duration(30+i)*time.Second)
+			Expect(err).To(BeNil())
+			obs = append(obs, o...)
+		}
+		return obs
+	}
+
+	perturbed := func(sat Satellite) Satellite {
+		el := sat.meanElements()
+		el.nodeo += 0.05 * DEG2RAD
+		el.mo -= 0.2 * DEG2RAD
+		el.no *= 1 + 1e-5
+		p, err := sat.withMeanElements(el)
+		Expect(err).To(BeNil())
+		return p
+	}
+
+	It("should reinitialize a satellite from its own mean elements", func() {
+		sat := jobTestSatellites()[0]
+		again, err := sat.withMeanElements(sat.meanElements())
+		Expect(err).To(BeNil())
+		for _, t := range []time.Time{start, start.Add(6 * time.Hour)} {
+			want, _, _ := sat.propagateAt(t)
+			got, _, _ := again.propagateAt(t)
+			Expect(distance(want, got)).To(BeNumerically("<", 1e-6))
+		}
+	})
+
+	It("should recover the truth from noise free observations", func() {
+		truth := jobTestSatellites()[0]
+		obs := simulate(&truth, ObservationErrors{})
+		apriori := perturbed(truth)
+
+		before, _, _ := apriori.propagateAt(start.Add(12 * time.Hour))
+		want, _, _ := truth.propagateAt(start.Add(12 * time.Hour))
+		Expect(distance(before, want)).To(BeNumerically(">", 10))
+
+		res, err := FitOrbit(&apriori, obs, ODOptions{Sigma: ObservationErrors{Angle: 1e-4, Range: 0.01}})
+		Expect(err).To(BeNil())
+		Expect(res.Converged).To(BeTrue())
+		Expect(res.RMS).To(BeNumerically("<", 1e-3))
+		got, _, _ := res.Satellite.propagateAt(start.Add(12 * time.Hour))
+		Expect(distance(got, want)).To(BeNumerically("<", 0.01))
+		Expect(res.Residuals).To(HaveLen(len(obs)))
+		Expect(res.Residuals[0].RangeRate).To(BeZero())
+	})
+
+	It("should report residuals and covariance matching the measurement noise", func() {
+		truth := jobTestSatellites()[0]
+		noise := ObservationErrors{Angle: 0.005 * DEG2RAD, Range: 0.02, RangeRate: 0.0005}
+		obs := simulate(&truth, noise)
+		apriori := perturbed(truth)
+
+		res, err := FitOrbit(&apriori, obs, ODOptions{Sigma: noise, FitBstar: true})
+		Expect(err).To(BeNil())
+		Expect(res.Converged).To(BeTrue())
+		Expect(res.RMS).To(BeNumerically("~", 1, 0.1))
+		Expect(res.Covariance).To(HaveLen(7))
+
+		el := truth.meanElements().vector(true)
+		fit := res.Satellite.meanElements().vector(true)
+		for k := range el {
+			Expect(res.Covariance[k][k]).To(BeNumerically(">", 0))
+			Expect(math.Abs(fit[k] - el[k])).To(BeNumerically("<", 5*math.Sqrt(res.Covariance[k][k])))
+		}
+	})
+
+	It("should reject fits without measurement deviations", func() {
+		sat := jobTestSatellites()[0]
+		_, err := FitOrbit(&sat, simulate(&sat, ObservationErrors{}), ODOptions{})
+		Expect(err).NotTo(BeNil())
+	})
+})
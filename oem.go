@@ -0,0 +1,248 @@
+package satellite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// One segment of a CCSDS Orbit Ephemeris Message: metadata and the states that follow it
+type OEMSegment struct {
+	ObjectName, ObjectID string
+	CenterName           string
+
+	// Reference frame such as "TEME", "EME2000" or "ITRF" and time system such as "UTC"
+	RefFrame, TimeSystem string
+
+	// Usable time span, zero when not given
+	UseableStart, UseableStop time.Time
+
+	// Recommended interpolation method such as "LAGRANGE" or "HERMITE" and its degree,
+	// empty and zero when not given
+	Interpolation       string
+	InterpolationDegree int
+
+	// Positions in km and velocities in km/s in RefFrame, times in TimeSystem
+	States []State
+}
+
+// Reads the segments of an Orbit Ephemeris Message in the keyword value notation (KVN).
+// Covariance sections and accelerations are skipped.
+// Reference: CCSDS 502.0-B-2, Orbit Data Messages.
+func ReadOEM(r io.Reader) ([]OEMSegment, error) {
+	var segments []OEMSegment
+	var seg *OEMSegment
+	inMeta, inCovariance := false, false
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "COMMENT"):
+		case line == "META_START":
+			segments = append(segments, OEMSegment{})
+			seg = &segments[len(segments)-1]
+			inMeta = true
+		case line == "META_STOP":
+			inMeta = false
+		case line == "COVARIANCE_START":
+			inCovariance = true
+		case line == "COVARIANCE_STOP":
+			inCovariance = false
+		case inCovariance:
+		case inMeta:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("OEM line %d: expected keyword = value", n)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch key {
+			case "OBJECT_NAME":
+				seg.ObjectName = value
+			case "OBJECT_ID":
+				seg.ObjectID = value
+			case "CENTER_NAME":
+				seg.CenterName = value
+			case "REF_FRAME":
+				seg.RefFrame = value
+			case "TIME_SYSTEM":
+				seg.TimeSystem = value
+			case "INTERPOLATION":
+				seg.Interpolation = value
+			case "INTERPOLATION_DEGREE":
+				d, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("OEM line %d: bad interpolation degree %q", n, value)
+				}
+				seg.InterpolationDegree = d
+			case "USEABLE_START_TIME", "USEABLE_STOP_TIME":
+				t, err := parseOEMTime(value)
+				if err != nil {
+					return nil, fmt.Errorf("OEM line %d: %w", n, err)
+				}
+				if key == "USEABLE_START_TIME" {
+					seg.UseableStart = t
+				} else {
+					seg.UseableStop = t
+				}
+			}
+		case strings.Contains(line, "="):
+			// Header keywords such as CCSDS_OEM_VERS and CREATION_DATE
+		case seg != nil:
+			state, err := parseOEMState(line)
+			if err != nil {
+				return nil, fmt.Errorf("OEM line %d: %w", n, err)
+			}
+			seg.States = append(seg.States, state)
+		default:
+			return nil, fmt.Errorf("OEM line %d: data before metadata", n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no OEM segments")
+	}
+	return segments, nil
+}
+
+// Returns an ephemeris table interpolating the states of a segment so that the ephemeris can
+// be used wherever a Propagator is accepted. States must be given in the TEME frame in UTC or
+// GPS time. Lagrange interpolation uses the degree of the segment metadata; Hermite or unset
+// interpolation uses cubic Hermite polynomials. Satnum is the catalog number the table reports.
+func NewEphemerisTableFromOEM(seg OEMSegment, satnum int64) (*EphemerisTable, error) {
+	if frame := strings.ToUpper(seg.RefFrame); frame != "TEME" {
+		return nil, fmt.Errorf("OEM %s: reference frame %q is not TEME", seg.ObjectName, seg.RefFrame)
+	}
+	states := seg.States
+	switch ts := strings.ToUpper(seg.TimeSystem); ts {
+	case "UTC", "":
+	case "GPS":
+		states = make([]State, len(seg.States))
+		for i, s := range seg.States {
+			s.Time = gpsToUTC(s.Time)
+			states[i] = s
+		}
+	default:
+		return nil, fmt.Errorf("OEM %s: unsupported time system %q", seg.ObjectName, seg.TimeSystem)
+	}
+
+	degree := 0
+	switch method := strings.ToUpper(seg.Interpolation); method {
+	case "LAGRANGE":
+		degree = max(seg.InterpolationDegree, 1)
+	case "HERMITE", "":
+	default:
+		return nil, fmt.Errorf("OEM %s: unsupported interpolation %q", seg.ObjectName, seg.Interpolation)
+	}
+	table, err := NewEphemerisTableFromStates(satnum, states, degree)
+	if err != nil {
+		return nil, fmt.Errorf("OEM %s: %w", seg.ObjectName, err)
+	}
+	return table, nil
+}
+
+// Propagator over every segment of an Orbit Ephemeris Message. Each segment is interpolated on
+// its own, so states are never interpolated across the maneuvers that usually separate them.
+type OEMEphemeris struct {
+	Satnum int64
+
+	// Tables of the segments in time order
+	Segments []*EphemerisTable
+}
+
+// Builds a propagator from the segments of an OEM in time order, see NewEphemerisTableFromOEM
+func NewOEMEphemeris(segments []OEMSegment, satnum int64) (*OEMEphemeris, error) {
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no OEM segments")
+	}
+	e := &OEMEphemeris{Satnum: satnum, Segments: make([]*EphemerisTable, len(segments))}
+	for i, seg := range segments {
+		table, err := NewEphemerisTableFromOEM(seg, satnum)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && table.Start().Before(e.Segments[i-1].Start()) {
+			return nil, fmt.Errorf("OEM %s: segment %d starts before the segment preceding it", seg.ObjectName, i+1)
+		}
+		e.Segments[i] = table
+	}
+	return e, nil
+}
+
+// Reads an Orbit Ephemeris Message in KVN into a propagator over all of its segments
+func ReadOEMEphemeris(r io.Reader, satnum int64) (*OEMEphemeris, error) {
+	segments, err := ReadOEM(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewOEMEphemeris(segments, satnum)
+}
+
+// Returns the catalog number of the ephemeris
+func (e *OEMEphemeris) CatalogNumber() int64 {
+	return e.Satnum
+}
+
+// Returns the state at t interpolated within the segment covering t. Where segments meet the
+// later one is used. Times in gaps between segments are out of range.
+func (e *OEMEphemeris) StateAt(t time.Time) (State, error) {
+	for i := len(e.Segments) - 1; i >= 0; i-- {
+		seg := e.Segments[i]
+		if !t.Before(seg.Start()) && !t.After(seg.Stop()) {
+			return seg.StateAt(t)
+		}
+	}
+	return State{}, newError(ErrOutOfRange, "time %s outside the OEM segments", t.Format(time.RFC3339))
+}
+
+// Returns the start of the first segment
+func (e *OEMEphemeris) Start() time.Time {
+	return e.Segments[0].Start()
+}
+
+// Returns the latest stop of the segments
+func (e *OEMEphemeris) Stop() time.Time {
+	stop := e.Segments[0].Stop()
+	for _, seg := range e.Segments[1:] {
+		if seg.Stop().After(stop) {
+			stop = seg.Stop()
+		}
+	}
+	return stop
+}
+
+// Parses an ephemeris data line of epoch, position and velocity
+func parseOEMState(line string) (State, error) {
+	f := strings.Fields(line)
+	if len(f) < 7 {
+		return State{}, fmt.Errorf("expected epoch, position and velocity")
+	}
+	t, err := parseOEMTime(f[0])
+	if err != nil {
+		return State{}, err
+	}
+	var v [6]float64
+	for i := range v {
+		if v[i], err = strconv.ParseFloat(f[i+1], 64); err != nil {
+			return State{}, err
+		}
+	}
+	return State{Time: t, Position: Vector3{v[0], v[1], v[2]}, Velocity: Vector3{v[3], v[4], v[5]}}, nil
+}
+
+// Parses an OEM epoch in calendar or day of year form
+func parseOEMTime(s string) (time.Time, error) {
+	s = strings.TrimSuffix(s, "Z")
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-002T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("bad OEM epoch %q", s)
+}
@@ -0,0 +1,242 @@
+package satellite
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Keywords every OMM element set must carry
+var ommRequired = []string{"EPOCH", "MEAN_MOTION", "ECCENTRICITY", "INCLINATION", "RA_OF_ASC_NODE", "ARG_OF_PERICENTER", "MEAN_ANOMALY", "NORAD_CAT_ID"}
+
+// Reads the element sets of Orbit Mean-Elements Messages in the keyword value notation (KVN),
+// XML or JSON, told apart by the first character of the document. KVN input may concatenate
+// several messages, each starting with CCSDS_OMM_VERS. JSON input is an object or an array
+// of objects keyed by the OMM keywords, as served by CelesTrak and Space-Track, with numbers
+// given as JSON numbers or strings. XML input is read by ReadNDM.
+// Reference: CCSDS 502.0-B-2, Orbit Data Messages.
+func ReadOMM(r io.Reader) ([]GPElements, error) {
+	br := bufio.NewReader(r)
+	first, err := firstByte(br)
+	if err == io.EOF {
+		return nil, fmt.Errorf("no OMM element sets")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []GPElements
+	switch first {
+	case '<':
+		ndm, err := ReadNDM(br)
+		if err != nil {
+			return nil, err
+		}
+		sets = ndm.OMM
+	case '{', '[':
+		sets, err = readOMMJSON(br)
+	default:
+		sets, err = readOMMKVN(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("no OMM element sets")
+	}
+	return sets, nil
+}
+
+// Converts the single element set of an Orbit Mean-Elements Message in KVN, XML or JSON into
+// a Satellite struct and runs sgp4init. Messages holding several element sets are refused;
+// read those with ReadOMM and NewSatFromGP.
+func NewSatFromOMM(r io.Reader, gravconst GravModel) (Satellite, error) {
+	sets, err := ReadOMM(r)
+	if err != nil {
+		return Satellite{}, err
+	}
+	if len(sets) > 1 {
+		return Satellite{}, fmt.Errorf("OMM holds %d element sets, expected one", len(sets))
+	}
+	return NewSatFromGP(sets[0], gravconst)
+}
+
+// Returns the first byte past leading white space and a byte order mark without consuming it
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n':
+			br.ReadByte()
+		case b[0] == 0xef:
+			bom, err := br.Peek(3)
+			if err != nil || !bytes.Equal(bom, []byte("\ufeff")) {
+				return b[0], nil
+			}
+			br.Discard(3)
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// Reads KVN messages, starting a new element set at every CCSDS_OMM_VERS keyword
+func readOMMKVN(r io.Reader) ([]GPElements, error) {
+	var sets []GPElements
+	var kv map[string]string
+	start := 0
+	flush := func() error {
+		if kv == nil {
+			return nil
+		}
+		gp, err := gpFromKeywords(kv)
+		if err != nil {
+			return fmt.Errorf("OMM line %d: %w", start, err)
+		}
+		sets = append(sets, gp)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "COMMENT") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("OMM line %d: expected keyword = value", n)
+		}
+		key = strings.TrimSpace(key)
+		// Values may carry their unit in brackets, such as "15.72 [rev/day]"
+		if i := strings.IndexByte(value, '['); i >= 0 {
+			value = value[:i]
+		}
+		if key == "CCSDS_OMM_VERS" || kv == nil {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			kv, start = map[string]string{}, n
+		}
+		kv[key] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// Reads a JSON object or array of objects keyed by the OMM keywords
+func readOMMJSON(r io.Reader) ([]GPElements, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("OMM JSON: %w", err)
+	}
+	var records []map[string]json.RawMessage
+	if bytes.HasPrefix(raw, []byte("{")) {
+		records = make([]map[string]json.RawMessage, 1)
+		if err := json.Unmarshal(raw, &records[0]); err != nil {
+			return nil, fmt.Errorf("OMM JSON: %w", err)
+		}
+	} else if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("OMM JSON: %w", err)
+	}
+
+	sets := make([]GPElements, 0, len(records))
+	for i, rec := range records {
+		gp, err := gpFromJSON(rec)
+		if err != nil {
+			return nil, fmt.Errorf("OMM JSON record %d: %w", i+1, err)
+		}
+		sets = append(sets, gp)
+	}
+	return sets, nil
+}
+
+// Converts one JSON record into an element set
+func gpFromJSON(rec map[string]json.RawMessage) (GPElements, error) {
+	kv := make(map[string]string, len(rec))
+	for key, v := range rec {
+		var s string
+		switch {
+		case bytes.Equal(v, []byte("null")):
+			continue
+		case bytes.HasPrefix(v, []byte(`"`)):
+			if err := json.Unmarshal(v, &s); err != nil {
+				return GPElements{}, fmt.Errorf("Error on parsing %s: %v", key, err)
+			}
+		default:
+			s = string(v)
+		}
+		kv[strings.ToUpper(key)] = strings.TrimSpace(s)
+	}
+	return gpFromKeywords(kv)
+}
+
+// Converts OMM keyword values in OMM units into an element set
+func gpFromKeywords(kv map[string]string) (GPElements, error) {
+	for _, name := range ommRequired {
+		if kv[name] == "" {
+			return GPElements{}, fmt.Errorf("OMM lacks keyword %s", name)
+		}
+	}
+	var perr error
+	float := func(name string) float64 {
+		s := kv[name]
+		if s == "" {
+			return 0
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil && perr == nil {
+			perr = fmt.Errorf("Error on parsing %s: %v", name, err)
+		}
+		return v
+	}
+	integer := func(name string) int64 {
+		s := kv[name]
+		if s == "" {
+			return 0
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil && perr == nil {
+			perr = fmt.Errorf("Error on parsing %s: %v", name, err)
+		}
+		return v
+	}
+
+	gp := GPElements{
+		ObjectName:         kv["OBJECT_NAME"],
+		ObjectID:           kv["OBJECT_ID"],
+		NoradCatID:         integer("NORAD_CAT_ID"),
+		MeanMotion:         float("MEAN_MOTION"),
+		Eccentricity:       float("ECCENTRICITY"),
+		Inclination:        float("INCLINATION"),
+		RAOfAscNode:        float("RA_OF_ASC_NODE"),
+		ArgOfPericenter:    float("ARG_OF_PERICENTER"),
+		MeanAnomaly:        float("MEAN_ANOMALY"),
+		EphemerisType:      int(integer("EPHEMERIS_TYPE")),
+		ClassificationType: kv["CLASSIFICATION_TYPE"],
+		ElementSetNo:       integer("ELEMENT_SET_NO"),
+		RevAtEpoch:         integer("REV_AT_EPOCH"),
+		Source:             kv["SOURCE"],
+		Bstar:              float("BSTAR"),
+		MeanMotionDot:      float("MEAN_MOTION_DOT"),
+		MeanMotionDDot:     float("MEAN_MOTION_DDOT"),
+	}
+	if perr == nil {
+		gp.Epoch, perr = parseGPEpoch(kv["EPOCH"])
+	}
+	return gp, perr
+}
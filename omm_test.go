@@ -0,0 +1,99 @@
+package satellite
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const ommKVN = `CCSDS_OMM_VERS = 2.0
+CREATION_DATE = 2008-09-20T13:00:00
+ORIGINATOR = 18 SPCS
+
+OBJECT_NAME = ISS (ZARYA)
+OBJECT_ID = 1998-067A
+CENTER_NAME = EARTH
+REF_FRAME = TEME
+TIME_SYSTEM = UTC
+MEAN_ELEMENT_THEORY = SGP4
+
+COMMENT Mean elements
+EPOCH = 2008-09-20T12:25:40.104192
+MEAN_MOTION = 15.72125391 [rev/day]
+ECCENTRICITY = .0006703
+INCLINATION = 51.6416 [deg]
+RA_OF_ASC_NODE = 247.4627 [deg]
+ARG_OF_PERICENTER = 130.5360 [deg]
+MEAN_ANOMALY = 325.0288 [deg]
+EPHEMERIS_TYPE = 0
+CLASSIFICATION_TYPE = U
+NORAD_CAT_ID = 25544
+ELEMENT_SET_NO = 292
+REV_AT_EPOCH = 56353
+BSTAR = -.11606E-4
+MEAN_MOTION_DOT = -.2182E-4
+MEAN_MOTION_DDOT = 0
+`
+
+// Space-Track quotes its numbers, CelesTrak does not
+const ommJSON = `[{"OBJECT_NAME":"ISS (ZARYA)","OBJECT_ID":"1998-067A","EPOCH":"2008-09-20T12:25:40.104192",
+"MEAN_MOTION":"15.72125391","ECCENTRICITY":"0.0006703","INCLINATION":"51.6416","RA_OF_ASC_NODE":"247.4627",
+"ARG_OF_PERICENTER":"130.5360","MEAN_ANOMALY":"325.0288","EPHEMERIS_TYPE":"0","CLASSIFICATION_TYPE":"U",
+"NORAD_CAT_ID":"25544","ELEMENT_SET_NO":"292","REV_AT_EPOCH":"56353","BSTAR":"-0.11606E-4",
+"MEAN_MOTION_DOT":"-0.2182E-4","MEAN_MOTION_DDOT":"0","DECAY_DATE":null},
+{"OBJECT_NAME":"NOAA 19","OBJECT_ID":"2009-005A","EPOCH":"2008-09-20T11:45:27.556992","MEAN_MOTION":14.12079902,
+"ECCENTRICITY":0.0013054,"INCLINATION":99.0394,"RA_OF_ASC_NODE":120.216,"ARG_OF_PERICENTER":232.8317,
+"MEAN_ANOMALY":127.1662,"EPHEMERIS_TYPE":0,"CLASSIFICATION_TYPE":"U","NORAD_CAT_ID":33591,"ELEMENT_SET_NO":999,
+"REV_AT_EPOCH":37833,"BSTAR":0.66998E-4,"MEAN_MOTION_DOT":0.77E-6,"MEAN_MOTION_DDOT":0}]`
+
+var _ = Describe("OMM", func() {
+	It("should initialize the same satellite as the TLE from KVN, XML and JSON", func() {
+		tle := jobTestSatellites()[0]
+		t := tle.jdsatepoch.ToTime().Add(12 * 3600e9)
+		want, _, _ := tle.propagateAt(t)
+
+		xmlDoc := ndmXML[strings.Index(ndmXML, "<omm") : strings.Index(ndmXML, "</omm>")+len("</omm>")]
+		jsonDoc := ommJSON[:strings.Index(ommJSON, "},")+1] + "]"
+		for _, doc := range []string{ommKVN, "\ufeff" + xmlDoc, jsonDoc, strings.TrimSuffix(strings.TrimPrefix(jsonDoc, "["), "]")} {
+			sat, err := NewSatFromOMM(strings.NewReader(doc), "wgs72")
+			Expect(err).To(BeNil())
+			Expect(sat.Satnum).To(Equal(int64(25544)))
+			Expect(sat.jdsatepoch.Single()).To(BeNumerically("~", tle.jdsatepoch.Single(), 1e-8))
+			got, _, err := sat.propagateAt(t)
+			Expect(err).To(BeNil())
+			Expect(distance(got, want)).To(BeNumerically("<", 1e-3))
+		}
+	})
+
+	It("should read every element set of a document", func() {
+		sets, err := ReadOMM(strings.NewReader(ommJSON))
+		Expect(err).To(BeNil())
+		Expect(sets).To(HaveLen(2))
+		Expect(sets[1].NoradCatID).To(Equal(int64(33591)))
+		Expect(sets[1].MeanMotion).To(Equal(14.12079902))
+		Expect(sets[0].ElementSetNo).To(Equal(int64(292)))
+
+		sets, err = ReadOMM(strings.NewReader(ommKVN + "\n" + strings.Replace(ommKVN, "25544", "25545", 1)))
+		Expect(err).To(BeNil())
+		Expect(sets).To(HaveLen(2))
+		Expect(sets[0].ObjectName).To(Equal("ISS (ZARYA)"))
+		Expect(sets[0].Inclination).To(Equal(51.6416))
+		Expect(sets[1].NoradCatID).To(Equal(int64(25545)))
+
+		_, err = NewSatFromOMM(strings.NewReader(ommJSON), "wgs72")
+		Expect(err).To(MatchError("OMM holds 2 element sets, expected one"))
+	})
+
+	It("should return error on incomplete or malformed messages", func() {
+		_, err := ReadOMM(strings.NewReader(strings.Replace(ommKVN, "MEAN_ANOMALY", "COMMENT", 1)))
+		Expect(err).To(MatchError("OMM line 1: OMM lacks keyword MEAN_ANOMALY"))
+
+		_, err = ReadOMM(strings.NewReader(strings.Replace(ommJSON, `"BSTAR":"-0.11606E-4"`, `"BSTAR":"x"`, 1)))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("OMM JSON record 1: Error on parsing BSTAR"))
+
+		_, err = ReadOMM(strings.NewReader("  \n"))
+		Expect(err).To(MatchError("no OMM element sets"))
+	})
+})
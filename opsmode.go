@@ -0,0 +1,32 @@
+package satellite
+
+import "fmt"
+
+// SGP4 operation mode of the element set initialization and the deep space perturbations
+type OpsMode string
+
+const (
+	// Improved mode of Vallado's revised SGP4, the default
+	OpsModeImproved OpsMode = "i"
+
+	// AFSPC mode matching the legacy Air Force code: the sidereal time at epoch from the
+	// 1970 based formula and the original handling of negative node angles of deep space
+	// orbits
+	OpsModeAFSPC OpsMode = "a"
+)
+
+// Returns the operation mode the satellite was initialized in
+func (sat *Satellite) OpsMode() OpsMode {
+	return OpsMode(sat.operationmode)
+}
+
+// Returns a copy of the satellite initialized in another operation mode
+func (sat Satellite) WithOpsMode(mode OpsMode) (Satellite, error) {
+	if mode != OpsModeImproved && mode != OpsModeAFSPC {
+		return sat, fmt.Errorf("unknown SGP4 operation mode %q", mode)
+	}
+	sat.operationmode = string(mode)
+	sat.no = sat.noKozai
+	_, _, err := sat.sgp4init(sat.jdsatepoch.Subtract(2433281.5))
+	return sat, err
+}
@@ -0,0 +1,44 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpsMode", func() {
+	It("should reinitialize the satellite in AFSPC mode", func() {
+		sat, err := NewSatFromTLE(
+			"1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600",
+			"2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119",
+			"wgs72")
+		Expect(err).To(BeNil())
+		Expect(sat.OpsMode()).To(Equal(OpsModeImproved))
+
+		afspc, err := sat.WithOpsMode(OpsModeAFSPC)
+		Expect(err).To(BeNil())
+		Expect(afspc.OpsMode()).To(Equal(OpsModeAFSPC))
+		Expect(sat.OpsMode()).To(Equal(OpsModeImproved))
+		// The sidereal time at epoch of both formulas agrees closely
+		Expect(afspc.gsto).To(BeNumerically("~", sat.gsto, 1e-6))
+		Expect(afspc.gsto).NotTo(Equal(sat.gsto))
+
+		again, err := afspc.WithOpsMode(OpsModeImproved)
+		Expect(err).To(BeNil())
+		t := sat.EpochTime().Add(48 * time.Hour)
+		want, _, _ := sat.Propagate(t)
+		got, _, err := again.Propagate(t)
+		Expect(err).To(BeNil())
+		Expect(distance(got, want)).To(BeNumerically("<", 1e-9))
+
+		got, _, err = afspc.Propagate(t)
+		Expect(err).To(BeNil())
+		Expect(distance(got, want)).To(BeNumerically("<", 1))
+	})
+
+	It("should refuse unknown operation modes", func() {
+		_, err := jobTestSatellites()[0].WithOpsMode("x")
+		Expect(err).To(MatchError(`unknown SGP4 operation mode "x"`))
+	})
+})
@@ -0,0 +1,75 @@
+// Package otelsat reports the spans of the satellite package to OpenTelemetry. It lives in
+// its own module so the satellite package does not depend on OpenTelemetry.
+//
+//	satellite.SetTracer(otelsat.NewTracer(otel.GetTracerProvider()))
+package otelsat
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation scope name of the spans
+const tracerName = "github.com/mpielikis/go-satellite"
+
+// Returns a satellite.Tracer starting spans with a tracer of the provider
+func NewTracer(tp trace.TracerProvider) satellite.Tracer {
+	return tracer{tp.Tracer(tracerName)}
+}
+
+type tracer struct {
+	t trace.Tracer
+}
+
+func (t tracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, satellite.Span) {
+	ctx, s := t.t.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, span{s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttributes(attrs ...slog.Attr) {
+	s.s.SetAttributes(attributes(attrs)...)
+}
+
+// Records err on the span and marks it failed when err is not nil, then ends the span
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+// Converts slog attributes into OpenTelemetry attributes
+func attributes(attrs []slog.Attr) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindInt64:
+			kvs = append(kvs, attribute.Int64(a.Key, v.Int64()))
+		case slog.KindUint64:
+			kvs = append(kvs, attribute.Int64(a.Key, int64(v.Uint64())))
+		case slog.KindFloat64:
+			kvs = append(kvs, attribute.Float64(a.Key, v.Float64()))
+		case slog.KindBool:
+			kvs = append(kvs, attribute.Bool(a.Key, v.Bool()))
+		case slog.KindDuration:
+			kvs = append(kvs, attribute.Float64(a.Key, v.Duration().Seconds()))
+		case slog.KindTime:
+			kvs = append(kvs, attribute.String(a.Key, v.Time().UTC().Format(time.RFC3339Nano)))
+		default:
+			kvs = append(kvs, attribute.String(a.Key, v.String()))
+		}
+	}
+	return kvs
+}
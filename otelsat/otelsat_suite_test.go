@@ -0,0 +1,13 @@
+package otelsat
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestOtelsat(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Otelsat Suite")
+}
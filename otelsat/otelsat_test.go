@@ -0,0 +1,71 @@
+package otelsat
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("NewTracer", func() {
+	var recorder *tracetest.SpanRecorder
+	var provider *sdktrace.TracerProvider
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		satellite.SetTracer(NewTracer(provider))
+	})
+
+	AfterEach(func() {
+		satellite.SetTracer(nil)
+	})
+
+	It("should record pass searches as children of the catalog timeline span", func() {
+		sat, err := satellite.NewSatFromTLE(
+			"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+			satellite.WGS72)
+		Expect(err).To(BeNil())
+		catalog := satellite.NewCatalog(sat)
+		obs := satellite.NewLatLongAlt(55.6167, 12.6500, 0.005)
+		start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+		_, err = catalog.Passes(ctx, obs, start, start.Add(6*time.Hour), satellite.PassOptions{})
+		Expect(err).To(BeNil())
+		parent.End()
+
+		spans := map[string]sdktrace.ReadOnlySpan{}
+		for _, s := range recorder.Ended() {
+			spans[s.Name()] = s
+		}
+		timeline, search := spans["satellite.Catalog.Passes"], spans["satellite.PredictPasses"]
+		Expect(timeline).NotTo(BeNil())
+		Expect(search).NotTo(BeNil())
+		Expect(timeline.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(search.Parent().SpanID()).To(Equal(timeline.SpanContext().SpanID()))
+		Expect(search.SpanContext().TraceID()).To(Equal(parent.SpanContext().TraceID()))
+		Expect(search.Attributes()).To(ContainElement(attribute.Int64("satellite.satnum", 25544)))
+		Expect(search.Attributes()).To(ContainElement(attribute.String("window.start", "2008-09-20T00:00:00Z")))
+		Expect(timeline.Attributes()).To(ContainElement(attribute.Int64("satellite.count", 1)))
+	})
+
+	It("should mark spans ended with an error as failed", func() {
+		_, s := NewTracer(provider).Start(context.Background(), "failing")
+		s.End(errors.New("propagation failed"))
+
+		ended := recorder.Ended()
+		Expect(ended).To(HaveLen(1))
+		Expect(ended[0].Status().Code).To(Equal(codes.Error))
+		Expect(ended[0].Status().Description).To(Equal("propagation failed"))
+		Expect(ended[0].Events()).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,293 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Visibility class of a pass for an optical observer
+type Visibility int
+
+const (
+	// The observer is in daylight for the whole pass
+	Daylight Visibility = iota
+
+	// The observer is dark but the satellite stays in the umbra of the Earth
+	Eclipsed
+
+	// The satellite is sunlit, at least in part outside the umbra, while the observer is
+	// dark for part of the pass
+	Visible
+)
+
+func (v Visibility) String() string {
+	switch v {
+	case Daylight:
+		return "daylight"
+	case Eclipsed:
+		return "eclipsed"
+	case Visible:
+		return "visible"
+	}
+	return "unknown"
+}
+
+// Options for PredictPasses and NextPasses
+type PassOptions struct {
+	// Minimum elevation in radians and whether to lower it by the horizon dip of an
+	// elevated observer, see AccessOptions
+	MinElevation Radians
+	HorizonDip   bool
+
+	// Optional terrain or obstruction profile, see AccessOptions
+	Mask *HorizonMask
+
+	// Sampling step of the pass search, 30 seconds when zero
+	Step time.Duration
+
+	// How far past start NextPasses searches, 7 days when zero
+	Horizon time.Duration
+
+	// Step of the detailed track returned by Pass.Track, 10 seconds when zero
+	TrackStep time.Duration
+
+	// Sun elevation in radians below which the observer counts as dark, civil twilight
+	// at -6 deg when zero
+	TwilightElevation Radians
+
+	// Optional progress callback, invoked after each search step with the passes that ended
+	// in it. Catalog.Passes invokes it after each catalog member instead.
+	Progress ProgressFunc[Pass]
+}
+
+// One pass of a satellite over an observer
+type Pass struct {
+	Satnum int64
+
+	// Acquisition of signal, time of closest approach and loss of signal
+	AOS, TCA, LOS time.Time
+
+	// Azimuth at AOS and LOS and the elevation at TCA, in radians
+	AOSAzimuth, LOSAzimuth float64
+	MaxElevation           float64
+
+	Duration time.Duration
+
+	Visibility Visibility
+
+	// Largest absolute range rate during the pass in km/s
+	MaxRangeRate float64
+
+	track *passTrack
+}
+
+// Returns the largest Doppler shift in Hz of a transmitter at freq during the pass
+func (p *Pass) MaxDoppler(freq float64) float64 {
+	return freq * p.MaxRangeRate / speedOfLight
+}
+
+// Observer view of the satellite at one moment of a pass
+type TrackPoint struct {
+	Time   time.Time
+	Angles LookAngles
+
+	// Range rate in km/s, positive when receding
+	RangeRate float64
+}
+
+// Detailed pass track computed on first use
+type passTrack struct {
+	once   sync.Once
+	sat    Propagator
+	obs    LatLongAlt
+	step   time.Duration
+	points []TrackPoint
+	err    error
+}
+
+// Returns look angles and range rate from AOS to LOS at the track step of the options the
+// pass was predicted with. The track is computed on the first call.
+func (p *Pass) Track() ([]TrackPoint, error) {
+	if p.track == nil {
+		return nil, errors.New("pass has no satellite to compute the track from")
+	}
+	t := p.track
+	t.once.Do(func() {
+		t.points, t.err = trackPoints(t.sat, t.obs, p.AOS, p.LOS, t.step)
+	})
+	return t.points, t.err
+}
+
+// Returns look angles and range rate of the satellite seen from the observer between start
+// and stop every step, the stop time included
+func trackPoints(sat Propagator, obs LatLongAlt, start, stop time.Time, step time.Duration) ([]TrackPoint, error) {
+	obsECEF := LLAToECEF(obs, gravityOf(sat))
+	times := sampleTimes(start, stop, step)
+	points := make([]TrackPoint, 0, len(times))
+	for _, at := range times {
+		pos, vel, err := stateECEF(sat, at)
+		if err != nil {
+			return points, err
+		}
+		points = append(points, TrackPoint{
+			Time:      at,
+			Angles:    ecefLookAngles(pos, obsECEF, obs),
+			RangeRate: topocentricRangeRate(pos, vel, obsECEF),
+		})
+	}
+	return points, nil
+}
+
+// Finds every pass of the satellite or ephemeris over the observer between start and stop. Passes in
+// progress at start or stop are clipped.
+func PredictPasses(sat Propagator, obs LatLongAlt, start, stop time.Time, opts PassOptions) ([]Pass, error) {
+	return PredictPassesContext(context.Background(), sat, obs, start, stop, opts)
+}
+
+// PredictPasses with a context carrying the parent span of the search. On cancellation the
+// passes found so far are returned with the context error.
+func PredictPassesContext(ctx context.Context, sat Propagator, obs LatLongAlt, start, stop time.Time, opts PassOptions) (passes []Pass, err error) {
+	if !stop.After(start) {
+		return nil, errors.New("pass search stop time must be after start time")
+	}
+	ctx, span := startSpan(ctx, "satellite.PredictPasses", satelliteWindowAttributes(sat.CatalogNumber(), start, stop)...)
+	defer func() { span.End(err) }()
+	return findPasses(ctx, sat, obs, start, stop, -1, opts)
+}
+
+// Returns up to n passes of the satellite or ephemeris over the observer starting at start,
+// searching as far as the horizon of the options. Passes in progress at start or at the end
+// of the horizon are clipped.
+func NextPasses(sat Propagator, obs LatLongAlt, start time.Time, n int, opts PassOptions) ([]Pass, error) {
+	return NextPassesContext(context.Background(), sat, obs, start, n, opts)
+}
+
+// NextPasses with a context carrying the parent span of the search. On cancellation the
+// passes found so far are returned with the context error.
+func NextPassesContext(ctx context.Context, sat Propagator, obs LatLongAlt, start time.Time, n int, opts PassOptions) (passes []Pass, err error) {
+	if n <= 0 {
+		return nil, errors.New("number of passes must be positive")
+	}
+	horizon := opts.Horizon
+	if horizon <= 0 {
+		horizon = 7 * 24 * time.Hour
+	}
+	ctx, span := startSpan(ctx, "satellite.NextPasses", satelliteWindowAttributes(sat.CatalogNumber(), start, start.Add(horizon))...)
+	defer func() { span.End(err) }()
+	return findPasses(ctx, sat, obs, start, start.Add(horizon), n, opts)
+}
+
+// Finds the windows between start and stop in which an optical observer can see the
+// satellite above the minimum elevation: the satellite is sunlit while the sun is below the
+// twilight elevation of the options at the observer. A Visible pass holds one or more of
+// these windows. Windows in progress at start or stop are clipped.
+func VisibleWindows(sat Propagator, obs LatLongAlt, start, stop time.Time, opts PassOptions) ([]AccessWindow, error) {
+	if !stop.After(start) {
+		return nil, errors.New("visibility search stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+	a := newAccessSearch(sat, obs, AccessOptions{
+		MinElevation:      opts.MinElevation,
+		HorizonDip:        opts.HorizonDip,
+		Mask:              opts.Mask,
+		Optical:           true,
+		TwilightElevation: opts.TwilightElevation,
+	})
+	return a.windows(start, stop, step, -1)
+}
+
+// Returns up to n passes between start and stop, all of them when n is negative
+func findPasses(ctx context.Context, sat Propagator, obs LatLongAlt, start, stop time.Time, n int, opts PassOptions) ([]Pass, error) {
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+	a := newAccessSearch(sat, obs, AccessOptions{MinElevation: opts.MinElevation, HorizonDip: opts.HorizonDip, Mask: opts.Mask})
+
+	// Passes are built as their windows close so they can be streamed to the progress callback
+	total := int((stop.Sub(start) + step - 1) / step)
+	progress := newProgressReporter(opts.Progress, total)
+	passes := []Pass{}
+	a.onSample = func(t time.Time, closed []AccessWindow) {
+		if err := ctx.Err(); err != nil {
+			a.err = err
+			return
+		}
+		found := make([]Pass, 0, len(closed))
+		for _, w := range closed {
+			p, err := newPass(sat, obs, a.obs, w, opts)
+			if err != nil {
+				a.err = err
+				break
+			}
+			found = append(found, p)
+		}
+		passes = append(passes, found...)
+		done := min(int((t.Sub(start)+step-1)/step), total)
+		progress.add(done-progress.done, found)
+	}
+	_, err := a.windows(start, stop, step, n)
+	return passes, err
+}
+
+// Builds the pass summary of an access window
+func newPass(sat Propagator, obs LatLongAlt, obsECEF Vector3, w AccessWindow, opts PassOptions) (Pass, error) {
+	trackStep := opts.TrackStep
+	if trackStep <= 0 {
+		trackStep = 10 * time.Second
+	}
+	twilight := float64(opts.TwilightElevation)
+	if twilight == 0 {
+		twilight = -6 * DEG2RAD
+	}
+	p := Pass{
+		Satnum:       sat.CatalogNumber(),
+		AOS:          w.Start,
+		TCA:          w.Culmination,
+		LOS:          w.Stop,
+		AOSAzimuth:   w.StartAngles.Az,
+		LOSAzimuth:   w.StopAngles.Az,
+		MaxElevation: w.CulminationAngles.El,
+		Duration:     w.Duration(),
+		track:        &passTrack{sat: sat, obs: obs, step: trackStep},
+	}
+
+	// Range rate peaks at the ends of a pass, the samples in between decide visibility
+	grav := gravityOf(sat)
+	dark, lit := false, false
+	for _, t := range sampleTimes(w.Start, w.Stop, 30*time.Second) {
+		s, err := sat.StateAt(t)
+		if err != nil {
+			return p, err
+		}
+		eci := s.Position
+		pos, ecfVel := grav.inertialToFixed(eci, s.Velocity, t)
+		p.MaxRangeRate = math.Max(p.MaxRangeRate, math.Abs(topocentricRangeRate(pos, ecfVel, obsECEF)))
+		if !grav.earth() {
+			// The sun position is geocentric, passes around other bodies count as daylight
+			continue
+		}
+		gmst := gmstAt(t)
+
+		sun := SunPositionECI(NewJDayFromTime(t))
+		if elevationOf(ECIToECEF(sun, gmst), obsECEF, obs) < twilight {
+			dark = true
+			if _, umbra := shadowAngles(eci, sun, grav.radiusearthkm); umbra >= 0 {
+				lit = true
+			}
+		}
+	}
+	switch {
+	case lit:
+		p.Visibility = Visible
+	case dark:
+		p.Visibility = Eclipsed
+	}
+	return p, nil
+}
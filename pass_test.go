@@ -0,0 +1,140 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PredictPasses", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+	opts := PassOptions{MinElevation: Degrees(10).Radians()}
+
+	It("should summarize the geometry of every pass", func() {
+		sat := jobTestSatellites()[0]
+		passes, err := PredictPasses(&sat, copenhagen, start, start.Add(48*time.Hour), opts)
+		Expect(err).To(BeNil())
+		Expect(passes).To(HaveLen(8))
+
+		windows, err := NextAccesses(&sat, copenhagen, start, 8, AccessOptions{MinElevation: opts.MinElevation})
+		Expect(err).To(BeNil())
+		for i, p := range passes {
+			w := windows[i]
+			Expect(p.Satnum).To(Equal(int64(25544)))
+			Expect(p.AOS).To(Equal(w.Start))
+			Expect(p.LOS).To(Equal(w.Stop))
+			Expect(p.TCA).To(Equal(w.Culmination))
+			Expect(p.Duration).To(Equal(w.Duration()))
+			Expect(p.AOSAzimuth).To(Equal(w.StartAngles.Az))
+			Expect(p.LOSAzimuth).To(Equal(w.StopAngles.Az))
+			Expect(p.MaxElevation).To(Equal(w.CulminationAngles.El))
+			Expect(p.MaxRangeRate).To(BeNumerically(">", 3))
+			Expect(p.MaxRangeRate).To(BeNumerically("<", 7.5))
+			Expect(p.MaxDoppler(145.8e6)).To(BeNumerically("~", 145.8e6*p.MaxRangeRate/speedOfLight, 1e-6))
+		}
+
+		// Evening passes; at dusk on the 21st the station is lit over a dark observer
+		Expect(passes[0].Visibility).To(Equal(Eclipsed))
+		Expect(passes[4].Visibility).To(Equal(Visible))
+		Expect(passes[4].Visibility.String()).To(Equal("visible"))
+	})
+
+	It("should return the next passes", func() {
+		sat := jobTestSatellites()[0]
+		all, err := PredictPasses(&sat, copenhagen, start, start.Add(48*time.Hour), opts)
+		Expect(err).To(BeNil())
+
+		next, err := NextPasses(&sat, copenhagen, start, 3, opts)
+		Expect(err).To(BeNil())
+		Expect(next).To(HaveLen(3))
+		for i, p := range next {
+			Expect(p.AOS).To(Equal(all[i].AOS))
+			Expect(p.LOS).To(Equal(all[i].LOS))
+			Expect(p.MaxElevation).To(Equal(all[i].MaxElevation))
+		}
+
+		_, err = NextPasses(&sat, copenhagen, start, 0, opts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should find the sunlit parts of passes over a dark observer", func() {
+		sat := jobTestSatellites()[0]
+		stop := start.Add(48 * time.Hour)
+		passes, err := PredictPasses(&sat, copenhagen, start, stop, opts)
+		Expect(err).To(BeNil())
+		windows, err := VisibleWindows(&sat, copenhagen, start, stop, opts)
+		Expect(err).To(BeNil())
+		Expect(windows).NotTo(BeEmpty())
+
+		obsECEF := LLAToECEF(copenhagen, sat.Gravity)
+		for _, w := range windows {
+			var in *Pass
+			for i := range passes {
+				if !w.Start.Before(passes[i].AOS) && !w.Stop.After(passes[i].LOS) {
+					in = &passes[i]
+				}
+			}
+			Expect(in).NotTo(BeNil())
+			Expect(in.Visibility).To(Equal(Visible))
+
+			mid := w.Start.Add(w.Duration() / 2)
+			pos, _, err := sat.propagateAt(mid)
+			Expect(err).To(BeNil())
+			sun := SunPositionECI(NewJDayFromTime(mid))
+			Expect(EclipseState(pos, sun)).NotTo(Equal(Umbra))
+			Expect(elevationOf(ECIToECEF(sun, gmstAt(mid)), obsECEF, copenhagen)).To(BeNumerically("<", -6*DEG2RAD))
+			Expect(w.CulminationAngles.El).To(BeNumerically(">=", opts.MinElevation))
+		}
+	})
+
+	It("should stream passes through the progress callback", func() {
+		sat := jobTestSatellites()[0]
+		stop := start.Add(24 * time.Hour)
+		var progress []Progress
+		var streamed []Pass
+		popts := opts
+		popts.Progress = func(p Progress, found []Pass) {
+			progress = append(progress, p)
+			streamed = append(streamed, found...)
+		}
+		passes, err := PredictPasses(&sat, copenhagen, start, stop, popts)
+		Expect(err).To(BeNil())
+		Expect(passes).NotTo(BeEmpty())
+		Expect(streamed).To(HaveLen(len(passes)))
+		for i := range passes {
+			Expect(streamed[i].AOS).To(Equal(passes[i].AOS))
+		}
+		last := progress[len(progress)-1]
+		Expect(last.Fraction).To(Equal(1.0))
+		Expect(last.Done).To(Equal(int(stop.Sub(start) / (30 * time.Second))))
+		Expect(last.Found).To(Equal(len(passes)))
+	})
+
+	It("should compute the detailed track on demand", func() {
+		sat := jobTestSatellites()[0]
+		passes, err := PredictPasses(&sat, copenhagen, start, start.Add(12*time.Hour), PassOptions{MinElevation: opts.MinElevation, TrackStep: 5 * time.Second})
+		Expect(err).To(BeNil())
+		p := passes[0]
+
+		track, err := p.Track()
+		Expect(err).To(BeNil())
+		Expect(track[0].Time).To(Equal(p.AOS))
+		Expect(track[len(track)-1].Time).To(Equal(p.LOS))
+		Expect(track[1].Time.Sub(track[0].Time)).To(Equal(5 * time.Second))
+		Expect(track[0].Angles.El * RAD2DEG).To(BeNumerically("~", 10, 0.05))
+		Expect(track[0].RangeRate).To(BeNumerically("<", 0))
+		Expect(track[len(track)-1].RangeRate).To(BeNumerically(">", 0))
+
+		again, _ := p.Track()
+		Expect(&again[0]).To(BeIdenticalTo(&track[0]))
+	})
+
+	It("should classify passes of an observer in daylight", func() {
+		sat := jobTestSatellites()[0]
+		passes, err := PredictPasses(&sat, copenhagen, start, start.Add(12*time.Hour), PassOptions{MinElevation: opts.MinElevation, TwilightElevation: Degrees(-89).Radians()})
+		Expect(err).To(BeNil())
+		Expect(passes[0].Visibility).To(Equal(Daylight))
+	})
+})
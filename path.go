@@ -0,0 +1,52 @@
+package satellite
+
+import "time"
+
+// Reference frame of sampled states
+type Frame int
+
+const (
+	// Inertial TEME frame of SGP4
+	FrameECI Frame = iota
+
+	// Earth fixed frame, velocity relative to the rotating Earth
+	FrameECEF
+)
+
+// Samples one full revolution centred on t into a polyline of states in the given frame.
+// Steps are equal in true anomaly, so eccentric orbits are sampled densely near perigee
+// and sparsely near apogee; points sets the samples per revolution, 180 when zero.
+// The last state lies one revolution after the first.
+func OrbitPath(sat *Satellite, t time.Time, frame Frame, points int) ([]State, error) {
+	if points <= 0 {
+		points = 180
+	}
+	period := time.Duration(TWOPI / sat.no * float64(time.Minute))
+	start, stop := t.Add(-period/2), t.Add(period/2)
+	dTheta := TWOPI / float64(points)
+
+	path := make([]State, 0, points+1)
+	for at := start; ; {
+		pos, vel, err := sat.propagateAt(at)
+		if err != nil {
+			return path, err
+		}
+		state := State{Time: at, Position: pos, Velocity: vel}
+		if frame == FrameECEF {
+			state.Position, state.Velocity = ECIToECEFState(pos, vel, gmstAt(at))
+		}
+		path = append(path, state)
+		if !at.Before(stop) {
+			return path, nil
+		}
+
+		// Time to sweep dTheta of true anomaly follows from the angular momentum, h = r^2 dtheta/dt
+		r := pos.Magnitude()
+		h := cross(pos, vel).Magnitude()
+		step := time.Duration(r * r / h * dTheta * float64(time.Second))
+		at = at.Add(max(step, time.Millisecond))
+		if at.After(stop) {
+			at = stop
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OrbitPath", func() {
+	molniya := func() Satellite {
+		sat, err := NewSatFromTLE(
+			"1 21118U 91012A   08264.00000000  .00000100  00000-0  10000-3 0  9999",
+			"2 21118  63.4000 200.0000 7200000 270.0000  20.0000  2.00600000 99999",
+			"wgs72")
+		Expect(err).To(BeNil())
+		return sat
+	}
+	t := time.Date(2008, 9, 21, 0, 0, 0, 0, time.UTC)
+
+	It("should cover one revolution in equal true anomaly steps", func() {
+		sat := molniya()
+		path, err := OrbitPath(&sat, t, FrameECI, 90)
+		Expect(err).To(BeNil())
+		Expect(len(path)).To(BeNumerically("~", 91, 2))
+
+		period := time.Duration(TWOPI / sat.no * float64(time.Minute))
+		Expect(path[0].Time).To(Equal(t.Add(-period / 2)))
+		Expect(path[len(path)-1].Time).To(Equal(t.Add(period / 2)))
+		Expect(distance(path[0].Position, path[len(path)-1].Position)).To(BeNumerically("<", 100))
+
+		var shortest, longest time.Duration = period, 0
+		for i := 1; i < len(path)-1; i++ {
+			a, b := path[i-1].Position, path[i].Position
+			angle := math.Acos(dot(a, b) / (a.Magnitude() * b.Magnitude()))
+			Expect(angle).To(BeNumerically("~", TWOPI/90, 0.02))
+
+			dt := path[i].Time.Sub(path[i-1].Time)
+			shortest, longest = min(shortest, dt), max(longest, dt)
+		}
+		// Perigee passes far faster than apogee at e = 0.72
+		Expect(longest).To(BeNumerically(">", 20*shortest))
+	})
+
+	It("should sample in the Earth fixed frame", func() {
+		sat := jobTestSatellites()[0]
+		path, err := OrbitPath(&sat, t, FrameECEF, 0)
+		Expect(err).To(BeNil())
+		Expect(len(path)).To(BeNumerically("~", 181, 2))
+
+		pos, vel, err := sat.PropagateECEF(path[10].Time)
+		Expect(err).To(BeNil())
+		Expect(path[10].Position).To(Equal(pos))
+		Expect(path[10].Velocity).To(Equal(vel))
+	})
+})
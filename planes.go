@@ -0,0 +1,46 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Relative orbit plane geometry of two satellites
+type PlaneGeometry struct {
+	// Angle between the two orbit planes in radians
+	PlaneAngle float64
+
+	// Right ascension of the ascending node of the second satellite minus that of the
+	// first in radians, in the range [-pi, pi)
+	RAANDifference float64
+
+	// Mean argument of latitude of the second satellite minus that of the first in
+	// radians, in the range [-pi, pi). Positive when the second satellite leads.
+	PhaseAngle float64
+}
+
+// Computes the plane angle, node difference and along orbit phase of two satellites
+// at t from their mean elements, advancing node, perigee and mean anomaly by the
+// secular J2 and drag rates to the common time. Deep space resonance and lunar-solar
+// terms are not included.
+func OrbitPlaneGeometry(a, b *Satellite, t time.Time) PlaneGeometry {
+	nodeA, uA := a.meanNodeAndLatitude(a.minutesSinceEpoch(t))
+	nodeB, uB := b.meanNodeAndLatitude(b.minutesSinceEpoch(t))
+	dNode := wrapPi(nodeB - nodeA)
+
+	cosAngle := math.Cos(a.inclo)*math.Cos(b.inclo) + math.Sin(a.inclo)*math.Sin(b.inclo)*math.Cos(dNode)
+	return PlaneGeometry{
+		PlaneAngle:     math.Acos(math.Max(-1, math.Min(1, cosAngle))),
+		RAANDifference: dNode,
+		PhaseAngle:     wrapPi(uB - uA),
+	}
+}
+
+// Returns the mean right ascension of the ascending node and mean argument of
+// latitude tsince minutes after epoch
+func (sat *Satellite) meanNodeAndLatitude(tsince float64) (node, u float64) {
+	node = sat.nodeo + sat.nodedot*tsince + sat.nodecf*tsince*tsince
+	argp := sat.argpo + sat.argpdot*tsince
+	m := sat.mo + sat.mdot*tsince
+	return math.Mod(node, TWOPI), math.Mod(argp+m, TWOPI)
+}
@@ -0,0 +1,34 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OrbitPlaneGeometry", func() {
+	t := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+
+	It("should report phase only for satellites sharing a plane", func() {
+		sats := relayTestConstellation(4)
+		g := OrbitPlaneGeometry(&sats[0], &sats[1], t)
+		Expect(g.PlaneAngle).To(BeNumerically("~", 0, 1e-6))
+		Expect(g.RAANDifference).To(BeNumerically("~", 0, 1e-9))
+		Expect(g.PhaseAngle).To(BeNumerically("~", math.Pi/2, 1e-6))
+	})
+
+	It("should report node difference and plane angle for shifted planes", func() {
+		sats := jobTestSatellites()
+		g := OrbitPlaneGeometry(&sats[0], &sats[1], t)
+		Expect(g.RAANDifference * RAD2DEG).To(BeNumerically("~", 10, 1e-6))
+
+		incl := 51.6416 * DEG2RAD
+		want := math.Acos(math.Cos(incl)*math.Cos(incl) + math.Sin(incl)*math.Sin(incl)*math.Cos(10*DEG2RAD))
+		Expect(g.PlaneAngle).To(BeNumerically("~", want, 1e-9))
+
+		polar := OrbitPlaneGeometry(&sats[0], &sats[3], t)
+		Expect(polar.PlaneAngle * RAD2DEG).To(BeNumerically(">", 47))
+	})
+})
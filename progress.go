@@ -0,0 +1,47 @@
+package satellite
+
+// Progress of a long running search such as pass prediction, coverage analysis or screening
+type Progress struct {
+	// Completed share of the work in the range 0 to 1
+	Fraction float64
+
+	// Work items (time steps, satellites, pairs, ...) processed so far and in total
+	Done, Total int
+
+	// Results found so far
+	Found int
+}
+
+// Callback invoked by long running searches as work completes. Results found since
+// the previous call are passed in found, so callers can stream them before the
+// search returns. The callback runs on the searching goroutine and should return quickly.
+type ProgressFunc[T any] func(p Progress, found []T)
+
+// Tracks progress of a search and reports it through an optional callback
+type progressReporter[T any] struct {
+	fn    ProgressFunc[T]
+	total int
+	done  int
+	found int
+}
+
+func newProgressReporter[T any](fn ProgressFunc[T], total int) *progressReporter[T] {
+	return &progressReporter[T]{fn: fn, total: total}
+}
+
+// Marks n more work items done with the results they produced and reports it
+func (p *progressReporter[T]) add(n int, found []T) {
+	p.done += n
+	p.found += len(found)
+	if p.fn == nil {
+		return
+	}
+	fraction := 1.0
+	if p.total > 0 {
+		fraction = float64(p.done) / float64(p.total)
+		if fraction > 1 {
+			fraction = 1
+		}
+	}
+	p.fn(Progress{Fraction: fraction, Done: p.done, Total: p.total, Found: p.found}, found)
+}
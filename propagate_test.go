@@ -0,0 +1,206 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Propagate", func() {
+	sat, _ := NewSatFromTLE(
+		"1 25544U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990",
+		"2 25544  51.6433 131.2277 0001338 330.3524 173.1622 15.49372617227549",
+		"wgs72")
+
+	It("should agree with propagation to the julian date", func() {
+		t := time.Date(2020, 5, 23, 20, 23, 37, 0, time.UTC)
+		pos, vel, err := sat.Propagate(t)
+		Expect(err).To(BeNil())
+
+		want, wantVel, err := sat.PropagateJDay(NewJDayFromTime(t))
+		Expect(err).To(BeNil())
+		Expect(distance(pos, want)).To(BeNumerically("<", 1e-6))
+		Expect(distance(vel, wantVel)).To(BeNumerically("<", 1e-9))
+
+		epoch, _, err := sat.Propagate(sat.jdsatepoch.ToTime())
+		Expect(err).To(BeNil())
+		init, _, _ := sat.sgp4(0)
+		Expect(distance(epoch, init)).To(BeNumerically("<", 1e-6))
+	})
+
+	It("should return an error instead of a bad state", func() {
+		decaying, err := NewSatFromTLE(
+			"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+			"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+			"wgs72")
+		Expect(err).To(BeNil())
+
+		_, _, err = decaying.Propagate(decaying.jdsatepoch.ToTime().Add(1e7 * time.Minute))
+		var perr *PropagationError
+		Expect(errors.As(err, &perr)).To(BeTrue())
+		Expect(perr.Satnum).To(Equal(int64(6251)))
+	})
+})
+
+var _ = Describe("Concurrent propagation", func() {
+	geo, _ := NewSatFromTLE(
+		"1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600",
+		"2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119",
+		"wgs72")
+
+	It("should not modify the satellite while propagating", func() {
+		for _, sat := range []Satellite{geo, jobTestSatellites()[0]} {
+			before := sat
+			for k := 0; k < 5; k++ {
+				_, _, err := sat.sgp4(float64(k) * 1000)
+				Expect(err).To(BeNil())
+			}
+			Expect(sat).To(Equal(before))
+		}
+	})
+
+	It("should give the same states from parallel goroutines", func() {
+		for _, sat := range []Satellite{geo, jobTestSatellites()[0]} {
+			want := make([]Vector3, 50)
+			for k := range want {
+				want[k], _, _ = sat.sgp4(float64(k) * 97)
+			}
+			got := make([][]Vector3, 8)
+			var wg sync.WaitGroup
+			for g := range got {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					got[g] = make([]Vector3, len(want))
+					// Each goroutine starts at its own time
+					for i := range want {
+						k := (i + g*7) % len(want)
+						got[g][k], _, _ = sat.sgp4(float64(k) * 97)
+					}
+				}(g)
+			}
+			wg.Wait()
+			for g := range got {
+				Expect(got[g]).To(Equal(want))
+			}
+		}
+	})
+})
+
+var _ = Describe("PropagateRange", func() {
+	sat := jobTestSatellites()[0]
+	start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+
+	It("should return states at every step including the stop time", func() {
+		states, err := sat.PropagateRange(start, start.Add(10*time.Minute+30*time.Second), time.Minute)
+		Expect(err).To(BeNil())
+		Expect(states).To(HaveLen(12))
+		Expect(states[11].Time).To(Equal(start.Add(10*time.Minute + 30*time.Second)))
+		for _, s := range states {
+			pos, vel, err := sat.Propagate(s.Time)
+			Expect(err).To(BeNil())
+			Expect(s.Position).To(Equal(pos))
+			Expect(s.Velocity).To(Equal(vel))
+		}
+	})
+
+	It("should stop at the first propagation failure", func() {
+		decaying, err := NewSatFromTLE(
+			"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+			"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+			"wgs72")
+		Expect(err).To(BeNil())
+		epoch := decaying.jdsatepoch.ToTime()
+		states, err := decaying.PropagateRange(epoch, epoch.Add(1e7*time.Minute), 1e6*time.Minute)
+		Expect(err).NotTo(BeNil())
+		Expect(len(states)).To(BeNumerically("<", 11))
+
+		_, err = sat.PropagateRange(start, start, 0)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("PropagateECEF", func() {
+	sat, _ := NewSatFromTLE(
+		"1 25544U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990",
+		"2 25544  51.6433 131.2277 0001338 330.3524 173.1622 15.49372617227549",
+		"wgs72")
+	t := time.Date(2020, 5, 23, 20, 23, 37, 0, time.UTC)
+
+	It("should rotate the inertial position into the Earth fixed frame", func() {
+		pos, _, err := sat.PropagateECEF(t)
+		Expect(err).To(BeNil())
+
+		eci, _, _ := sat.propagateAt(t)
+		want := ECIToECEF(eci, gmstAt(t))
+		Expect(pos.X).To(BeNumerically("~", want.X, 1e-9))
+		Expect(pos.Y).To(BeNumerically("~", want.Y, 1e-9))
+		Expect(pos.Z).To(BeNumerically("~", want.Z, 1e-9))
+	})
+
+	It("should return velocity relative to the rotating Earth", func() {
+		_, vel, err := sat.PropagateECEF(t)
+		Expect(err).To(BeNil())
+
+		before, _, _ := sat.PropagateECEF(t.Add(-time.Second))
+		after, _, _ := sat.PropagateECEF(t.Add(time.Second))
+		Expect(vel.X).To(BeNumerically("~", (after.X-before.X)/2, 1e-4))
+		Expect(vel.Y).To(BeNumerically("~", (after.Y-before.Y)/2, 1e-4))
+		Expect(vel.Z).To(BeNumerically("~", (after.Z-before.Z)/2, 1e-4))
+	})
+
+	It("should return propagation errors", func() {
+		decaying, err := NewSatFromTLE(
+			"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+			"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+			"wgs72")
+		Expect(err).To(BeNil())
+
+		_, _, err = decaying.PropagateECEF(decaying.jdsatepoch.ToTime().Add(1e7 * time.Minute))
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("PropagateLLA", func() {
+	sat, _ := NewSatFromTLE(
+		"1 25544U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990",
+		"2 25544  51.6433 131.2277 0001338 330.3524 173.1622 15.49372617227549",
+		"wgs72")
+	t := time.Date(2020, 5, 23, 20, 23, 37, 0, time.UTC)
+
+	It("should match the geodetic conversion of the inertial position", func() {
+		lla, _, err := sat.PropagateLLA(t)
+		Expect(err).To(BeNil())
+
+		eci, _, _ := sat.propagateAt(t)
+		alt, _, ll := ECIToLLA(eci, gmstAt(t))
+		Expect(lla.AltitudeKm).To(BeNumerically("~", alt, 1e-6))
+		Expect(lla.LatLong.Latitude).To(BeNumerically("~", ll.Latitude, 1e-9))
+		Expect(lla.LatLong.Longitude).To(BeNumerically("~", wrapPi(ll.Longitude), 1e-9))
+		Expect(lla.LatLong.Longitude).To(BeNumerically(">=", -math.Pi))
+		Expect(lla.LatLong.Longitude).To(BeNumerically("<", math.Pi))
+	})
+
+	It("should return the ground speed of the sub-satellite point", func() {
+		_, speed, err := sat.PropagateLLA(t)
+		Expect(err).To(BeNil())
+
+		// Distance between sub-satellite points one second apart on a mean radius sphere
+		before, _, _ := sat.PropagateLLA(t.Add(-500 * time.Millisecond))
+		after, _, _ := sat.PropagateLLA(t.Add(500 * time.Millisecond))
+		Expect(speed).To(BeNumerically("~", centralAngle(before.LatLong, after.LatLong)*6371, 0.05))
+	})
+
+	It("should return degrees on request", func() {
+		rad, _, _ := sat.PropagateLLA(t)
+		deg, _, err := sat.PropagateLLADeg(t)
+		Expect(err).To(BeNil())
+		Expect(deg.LatLong.Latitude).To(BeNumerically("~", rad.LatLong.Latitude*RAD2DEG, 1e-9))
+		Expect(deg.LatLong.Longitude).To(BeNumerically("~", rad.LatLong.Longitude*RAD2DEG, 1e-9))
+		Expect(deg.AltitudeKm).To(Equal(rad.AltitudeKm))
+	})
+})
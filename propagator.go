@@ -0,0 +1,56 @@
+package satellite
+
+import "time"
+
+// Source of inertial (TEME) states of one object. Satellite propagates with SGP4,
+// EphemerisTable interpolates precomputed or imported states and OEMEphemeris the segments
+// of an Orbit Ephemeris Message; the access and pass searches accept any of them.
+type Propagator interface {
+	// Returns the NORAD catalog number of the object, zero when unknown
+	CatalogNumber() int64
+
+	// Returns the position in km and velocity in km/s at t
+	StateAt(t time.Time) (State, error)
+}
+
+// Returns the catalog number of the satellite
+func (sat *Satellite) CatalogNumber() int64 {
+	return sat.Satnum
+}
+
+// Returns the SGP4 state at t without logging failures
+func (sat *Satellite) StateAt(t time.Time) (State, error) {
+	pos, vel, err := sat.propagateAt(t)
+	return State{Time: t, Position: pos, Velocity: vel}, err
+}
+
+// Returns the catalog number of the table
+func (e *EphemerisTable) CatalogNumber() int64 {
+	return e.Satnum
+}
+
+// Earth model of propagators that do not carry one, used to place ground points
+var defaultGravity, _ = getGravConst(WGS84)
+
+// Returns the central body model of a propagator: the gravity model of a satellite or
+// numerical propagator, WGS84 otherwise
+func gravityOf(p Propagator) GravConst {
+	if g, ok := p.(interface{ gravity() GravConst }); ok {
+		return g.gravity()
+	}
+	return defaultGravity
+}
+
+func (sat *Satellite) gravity() GravConst {
+	return sat.Gravity
+}
+
+// Returns the body fixed position and velocity of a propagator at t
+func stateECEF(p Propagator, t time.Time) (position, velocity Vector3, err error) {
+	s, err := p.StateAt(t)
+	if err != nil {
+		return position, velocity, err
+	}
+	position, velocity = gravityOf(p).inertialToFixed(s.Position, s.Velocity, t)
+	return position, velocity, nil
+}
@@ -0,0 +1,56 @@
+package satellite
+
+import (
+	"errors"
+	"time"
+)
+
+// Returns the angular velocity of the satellite across the sky of the observer in rad/s
+func ApparentAngularRate(sat *Satellite, obs LatLongAlt, t time.Time) (float64, error) {
+	_, rate, err := apparentMotion(sat, obs, LLAToECEF(obs, sat.Gravity), t)
+	return rate, err
+}
+
+// Returns the look angles and the apparent angular rate in rad/s at t
+func apparentMotion(sat *Satellite, obs LatLongAlt, obsECEF Vector3, t time.Time) (LookAngles, float64, error) {
+	pos, vel, err := sat.PropagateECEF(t)
+	if err != nil {
+		return LookAngles{}, 0, err
+	}
+	// The observer is fixed in the Earth frame, so the line of sight rate is the satellite velocity
+	rho := Vector3{pos.X - obsECEF.X, pos.Y - obsECEF.Y, pos.Z - obsECEF.Z}
+	r := rho.Magnitude()
+	rate := cross(rho, vel).Magnitude() / (r * r)
+	return ecefLookAngles(pos, obsECEF, obs), rate, nil
+}
+
+// Apparent motion of the satellite at one moment of a pass
+type AngularRateSample struct {
+	Time   time.Time
+	Angles LookAngles
+
+	// Angular velocity across the sky in rad/s
+	Rate float64
+}
+
+// Samples the apparent angular rate over an access window every step, 10 seconds when zero.
+// The window stop is always included.
+func PassAngularRates(sat *Satellite, obs LatLongAlt, w AccessWindow, step time.Duration) ([]AngularRateSample, error) {
+	if !w.Stop.After(w.Start) {
+		return nil, errors.New("access window stop must be after start")
+	}
+	if step <= 0 {
+		step = 10 * time.Second
+	}
+	obsECEF := LLAToECEF(obs, sat.Gravity)
+	times := sampleTimes(w.Start, w.Stop, step)
+	samples := make([]AngularRateSample, 0, len(times))
+	for _, t := range times {
+		la, rate, err := apparentMotion(sat, obs, obsECEF, t)
+		if err != nil {
+			return samples, err
+		}
+		samples = append(samples, AngularRateSample{Time: t, Angles: la, Rate: rate})
+	}
+	return samples, nil
+}
@@ -0,0 +1,66 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ApparentAngularRate", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	// Returns the unit line of sight from the observer to the satellite
+	lineOfSight := func(sat *Satellite, t time.Time) Vector3 {
+		pos, _, err := sat.PropagateECEF(t)
+		Expect(err).To(BeNil())
+		obs := LLAToECEF(copenhagen, sat.Gravity)
+		rho := Vector3{pos.X - obs.X, pos.Y - obs.Y, pos.Z - obs.Z}
+		r := rho.Magnitude()
+		return Vector3{rho.X / r, rho.Y / r, rho.Z / r}
+	}
+
+	It("should match the turn of the line of sight", func() {
+		sat := jobTestSatellites()[0]
+		windows, err := NextAccesses(&sat, copenhagen, start, 1, AccessOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+
+		t := windows[0].Culmination
+		rate, err := ApparentAngularRate(&sat, copenhagen, t)
+		Expect(err).To(BeNil())
+
+		a, b := lineOfSight(&sat, t.Add(-50*time.Millisecond)), lineOfSight(&sat, t.Add(50*time.Millisecond))
+		Expect(rate).To(BeNumerically("~", math.Acos(dot(a, b))/0.1, 1e-4))
+	})
+
+	It("should peak near culmination along a pass", func() {
+		sat := jobTestSatellites()[0]
+		windows, err := NextAccesses(&sat, copenhagen, start, 1, AccessOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+		w := windows[0]
+
+		samples, err := PassAngularRates(&sat, copenhagen, w, 0)
+		Expect(err).To(BeNil())
+		Expect(samples[0].Time).To(Equal(w.Start))
+		Expect(samples[len(samples)-1].Time).To(Equal(w.Stop))
+
+		peak := samples[0]
+		for _, s := range samples {
+			if s.Rate > peak.Rate {
+				peak = s
+			}
+		}
+		Expect(peak.Time.Sub(w.Culmination).Abs()).To(BeNumerically("<=", 10*time.Second))
+		Expect(peak.Rate).To(BeNumerically(">", samples[0].Rate))
+		// A low orbit crosses the sky well below 2 deg/s even overhead
+		Expect(peak.Rate * RAD2DEG).To(BeNumerically("<", 2))
+	})
+
+	It("should reject an empty window", func() {
+		sat := jobTestSatellites()[0]
+		_, err := PassAngularRates(&sat, copenhagen, AccessWindow{Start: start, Stop: start}, 0)
+		Expect(err).NotTo(BeNil())
+	})
+})
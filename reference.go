@@ -0,0 +1,206 @@
+package satellite
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// State produced by another SGP4 implementation
+type ReferencePoint struct {
+	// Time of the state; when zero Tsince gives the minutes from the element set epoch
+	Time   time.Time
+	Tsince float64
+
+	// TEME position in km and velocity in km/s
+	Position, Velocity Vector3
+}
+
+// Reference states keyed by NORAD catalog number
+type ReferenceEphemeris map[int64][]ReferencePoint
+
+// Reads the verification output of the Vallado SGP4 code (tcppver.out), in which a
+// "<satnum> xx" line opens each satellite and every following line holds minutes since
+// epoch, position and velocity
+func ReadVerificationOutput(r io.Reader) (ReferenceEphemeris, error) {
+	ref := make(ReferenceEphemeris)
+	var satnum int64 = -1
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		f := strings.Fields(scanner.Text())
+		switch {
+		case len(f) == 0:
+		case len(f) == 2 && f[1] == "xx":
+			s, err := strconv.ParseInt(f[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("verification output line %d: %w", n, err)
+			}
+			satnum = s
+		case satnum < 0:
+			return nil, fmt.Errorf("verification output line %d: states before satellite header", n)
+		case len(f) < 7:
+			return nil, fmt.Errorf("verification output line %d: expected minutes, position and velocity", n)
+		default:
+			var v [7]float64
+			for i := range v {
+				x, err := strconv.ParseFloat(f[i], 64)
+				if err != nil {
+					return nil, fmt.Errorf("verification output line %d: %w", n, err)
+				}
+				v[i] = x
+			}
+			ref[satnum] = append(ref[satnum], ReferencePoint{
+				Tsince:   v[0],
+				Position: Vector3{v[1], v[2], v[3]},
+				Velocity: Vector3{v[4], v[5], v[6]},
+			})
+		}
+	}
+	return ref, scanner.Err()
+}
+
+// Reads reference states from CSV with a header row. Column names are matched ignoring
+// case: satnum (or norad_cat_id), either tsince in minutes or time in RFC 3339, and
+// x, y, z, vx, vy, vz in km and km/s.
+func ReadReferenceCSV(r io.Reader) (ReferenceEphemeris, error) {
+	records := csv.NewReader(r)
+	header, err := records.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := func(names ...string) int {
+		return slices.IndexFunc(header, func(h string) bool {
+			return slices.Contains(names, strings.ToLower(strings.TrimSpace(h)))
+		})
+	}
+	satCol, tsinceCol, timeCol := col("satnum", "norad_cat_id"), col("tsince"), col("time")
+	valueCols := []int{col("x"), col("y"), col("z"), col("vx"), col("vy"), col("vz")}
+	if satCol < 0 || (tsinceCol < 0 && timeCol < 0) || slices.Contains(valueCols, -1) {
+		return nil, fmt.Errorf("reference CSV needs satnum, tsince or time, x, y, z, vx, vy and vz columns")
+	}
+
+	ref := make(ReferenceEphemeris)
+	for line := 2; ; line++ {
+		rec, err := records.Read()
+		if err == io.EOF {
+			return ref, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		satnum, err := strconv.ParseInt(strings.TrimSpace(rec[satCol]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("reference CSV line %d: %w", line, err)
+		}
+		var p ReferencePoint
+		if timeCol >= 0 {
+			if p.Time, err = time.Parse(time.RFC3339Nano, strings.TrimSpace(rec[timeCol])); err != nil {
+				return nil, fmt.Errorf("reference CSV line %d: %w", line, err)
+			}
+		} else if p.Tsince, err = strconv.ParseFloat(strings.TrimSpace(rec[tsinceCol]), 64); err != nil {
+			return nil, fmt.Errorf("reference CSV line %d: %w", line, err)
+		}
+		var v [6]float64
+		for i, c := range valueCols {
+			if v[i], err = strconv.ParseFloat(strings.TrimSpace(rec[c]), 64); err != nil {
+				return nil, fmt.Errorf("reference CSV line %d: %w", line, err)
+			}
+		}
+		p.Position, p.Velocity = Vector3{v[0], v[1], v[2]}, Vector3{v[3], v[4], v[5]}
+		ref[satnum] = append(ref[satnum], p)
+	}
+}
+
+// Collects reference states from OEM segments in the TEME frame whose OBJECT_ID is a
+// catalog number. Epochs in GPS time are converted to UTC.
+func ReferenceFromOEM(segments []OEMSegment) (ReferenceEphemeris, error) {
+	ref := make(ReferenceEphemeris)
+	for _, seg := range segments {
+		satnum, err := strconv.ParseInt(seg.ObjectID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("OEM OBJECT_ID %q is not a catalog number", seg.ObjectID)
+		}
+		if seg.RefFrame != "TEME" {
+			return nil, fmt.Errorf("OEM segment of %d is in %s, reference states must be in TEME", satnum, seg.RefFrame)
+		}
+		if seg.TimeSystem != "UTC" && seg.TimeSystem != "GPS" {
+			return nil, fmt.Errorf("OEM segment of %d uses unsupported time system %s", satnum, seg.TimeSystem)
+		}
+		for _, s := range seg.States {
+			t := s.Time
+			if seg.TimeSystem == "GPS" {
+				t = gpsToUTC(t)
+			}
+			ref[satnum] = append(ref[satnum], ReferencePoint{Time: t, Position: s.Position, Velocity: s.Velocity})
+		}
+	}
+	return ref, nil
+}
+
+// Agreement of this package with a reference implementation for one satellite
+type ImplementationDifference struct {
+	Satnum int64
+
+	// Reference states compared and states this package failed to propagate
+	Points, Failures int
+
+	// Largest and root mean square position difference in km and velocity difference in km/s
+	MaxPosition, RMSPosition float64
+	MaxVelocity, RMSVelocity float64
+
+	// Minutes from epoch of the largest position difference
+	WorstTsince float64
+}
+
+// Propagates every satellite with reference states and reports the differences, ordered
+// by catalog number. Reference states of satellites missing from sats are an error.
+func CompareReference(sats []Satellite, ref ReferenceEphemeris) ([]ImplementationDifference, error) {
+	bySatnum := make(map[int64]*Satellite, len(sats))
+	for i := range sats {
+		bySatnum[sats[i].Satnum] = &sats[i]
+	}
+	var out []ImplementationDifference
+	for satnum, points := range ref {
+		sat, ok := bySatnum[satnum]
+		if !ok {
+			return nil, newError(ErrUnknownSatellite, "no element set for reference states of %d", satnum)
+		}
+		d := ImplementationDifference{Satnum: satnum}
+		var sumPos, sumVel float64
+		for _, p := range points {
+			tsince := p.Tsince
+			if !p.Time.IsZero() {
+				tsince = sat.minutesSinceEpoch(p.Time)
+			}
+			pos, vel, err := sat.sgp4(tsince)
+			if err != nil {
+				d.Failures++
+				continue
+			}
+			dp, dv := distance(pos, p.Position), distance(vel, p.Velocity)
+			d.Points++
+			sumPos += dp * dp
+			sumVel += dv * dv
+			if dp > d.MaxPosition {
+				d.MaxPosition, d.WorstTsince = dp, tsince
+			}
+			d.MaxVelocity = math.Max(d.MaxVelocity, dv)
+		}
+		if d.Points > 0 {
+			d.RMSPosition = math.Sqrt(sumPos / float64(d.Points))
+			d.RMSVelocity = math.Sqrt(sumVel / float64(d.Points))
+		}
+		out = append(out, d)
+	}
+	slices.SortFunc(out, func(a, b ImplementationDifference) int { return cmp.Compare(a.Satnum, b.Satnum) })
+	return out, nil
+}
@@ -0,0 +1,108 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Excerpt of the Vallado verification output for satellite 5
+const verificationSample = `     5 xx
+       0.00000000    7022.46529266   -1400.08296755       0.03995155    1.893841015    6.405893759    4.534807250
+     360.00000000   -7154.03120202   -3783.17682504   -3536.19412294    4.741887409   -4.151817765   -2.093935425 2000  6 28  0:50:19.733571
+     720.00000000   -7134.59340119    6531.68641334    3260.27186483   -4.113793027   -2.911922039   -2.557327851 2000  6 28  6:50:19.733571
+`
+
+var _ = Describe("CompareReference", func() {
+	vanguard := func() Satellite {
+		sat, err := NewSatFromTLE(
+			"1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+			"2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+			"wgs72")
+		Expect(err).To(BeNil())
+		return sat
+	}
+
+	It("should agree with the Vallado verification output", func() {
+		ref, err := ReadVerificationOutput(strings.NewReader(verificationSample))
+		Expect(err).To(BeNil())
+		Expect(ref[5]).To(HaveLen(3))
+
+		diffs, err := CompareReference([]Satellite{vanguard()}, ref)
+		Expect(err).To(BeNil())
+		Expect(diffs).To(HaveLen(1))
+		Expect(diffs[0].Satnum).To(Equal(int64(5)))
+		Expect(diffs[0].Points).To(Equal(3))
+		Expect(diffs[0].Failures).To(Equal(0))
+		Expect(diffs[0].MaxPosition).To(BeNumerically("<", 1e-4))
+		Expect(diffs[0].MaxVelocity).To(BeNumerically("<", 1e-6))
+	})
+
+	It("should report the size and place of differences from CSV", func() {
+		sat := vanguard()
+		var b strings.Builder
+		b.WriteString("satnum,tsince,x,y,z,vx,vy,vz\n")
+		for _, tsince := range []float64{0, 60, 120, 180} {
+			pos, vel, err := sat.sgp4(tsince)
+			Expect(err).To(BeNil())
+			if tsince == 120 {
+				pos.X += 0.003
+			}
+			fmt.Fprintf(&b, "5,%f,%.9f,%.9f,%.9f,%.12f,%.12f,%.12f\n", tsince, pos.X, pos.Y, pos.Z, vel.X, vel.Y, vel.Z)
+		}
+
+		ref, err := ReadReferenceCSV(strings.NewReader(b.String()))
+		Expect(err).To(BeNil())
+		diffs, err := CompareReference([]Satellite{sat}, ref)
+		Expect(err).To(BeNil())
+		Expect(diffs[0].Points).To(Equal(4))
+		Expect(diffs[0].MaxPosition).To(BeNumerically("~", 0.003, 1e-6))
+		Expect(diffs[0].RMSPosition).To(BeNumerically("~", 0.0015, 1e-6))
+		Expect(diffs[0].WorstTsince).To(Equal(120.0))
+	})
+
+	It("should read TEME states from OEM", func() {
+		sat := jobTestSatellites()[0]
+		start := time.Date(2008, 9, 20, 12, 0, 0, 0, time.UTC)
+		var b strings.Builder
+		b.WriteString("CCSDS_OEM_VERS = 2.0\nCREATION_DATE = 2008-09-20T00:00:00\nORIGINATOR = TEST\n\n")
+		b.WriteString("META_START\nOBJECT_NAME = ISS (ZARYA)\nOBJECT_ID = 25544\nCENTER_NAME = EARTH\n")
+		b.WriteString("REF_FRAME = TEME\nTIME_SYSTEM = UTC\nSTART_TIME = 2008-09-20T12:00:00.000\nSTOP_TIME = 2008-09-20T12:02:00.000\nMETA_STOP\n")
+		b.WriteString("COMMENT generated by python-sgp4\n")
+		for k := 0; k < 3; k++ {
+			t := start.Add(time.Duration(k) * time.Minute)
+			pos, vel, err := sat.propagateAt(t)
+			Expect(err).To(BeNil())
+			fmt.Fprintf(&b, "%s %.6f %.6f %.6f %.9f %.9f %.9f\n", t.Format("2006-01-02T15:04:05.000"), pos.X, pos.Y, pos.Z, vel.X, vel.Y, vel.Z)
+		}
+		b.WriteString("COVARIANCE_START\nEPOCH = 2008-09-20T12:00:00.000\n1.0\nCOVARIANCE_STOP\n")
+
+		segments, err := ReadOEM(strings.NewReader(b.String()))
+		Expect(err).To(BeNil())
+		Expect(segments).To(HaveLen(1))
+		Expect(segments[0].ObjectName).To(Equal("ISS (ZARYA)"))
+		Expect(segments[0].States).To(HaveLen(3))
+
+		ref, err := ReferenceFromOEM(segments)
+		Expect(err).To(BeNil())
+		diffs, err := CompareReference(jobTestSatellites(), ref)
+		Expect(err).To(BeNil())
+		Expect(diffs).To(HaveLen(1))
+		Expect(diffs[0].MaxPosition).To(BeNumerically("<", 1e-5))
+
+		segments[0].RefFrame = "EME2000"
+		_, err = ReferenceFromOEM(segments)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should reject reference states of unknown satellites", func() {
+		ref, err := ReadVerificationOutput(strings.NewReader(verificationSample))
+		Expect(err).To(BeNil())
+		_, err = CompareReference(jobTestSatellites(), ref)
+		Expect(errors.Is(err, ErrUnknownSatellite)).To(BeTrue())
+	})
+})
@@ -0,0 +1,136 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// Options for RelayPaths
+type RelayOptions struct {
+	Start, Stop time.Time
+
+	// Sampling step, 30 seconds when zero. Path changes are resolved to one step.
+	Step time.Duration
+
+	// Minimum elevation in radians for a ground station to satellite link
+	MinElevation Radians
+
+	// Lower the minimum elevation by the horizon dip of elevated stations
+	HorizonDip bool
+
+	// Maximum length in km of a satellite to satellite crosslink
+	MaxCrosslinkRange Kilometers
+}
+
+// Relay path between two ground stations available during an interval
+type RelayPath struct {
+	Start, Stop time.Time
+
+	// Catalog numbers of the satellites along the path, from the source station
+	// to the destination station
+	Hops []int64
+}
+
+// Finds the relay paths connecting the source and destination stations through
+// the constellation between the start and stop times. At every step the path with
+// the fewest hops is chosen; consecutive steps using the same path are merged into
+// one interval. Times without any path are left out.
+func RelayPaths(src, dst LatLongAlt, sats []Satellite, opts RelayOptions) ([]RelayPath, error) {
+	if !opts.Stop.After(opts.Start) {
+		return nil, errors.New("relay stop time must be after start time")
+	}
+	if len(sats) == 0 {
+		return nil, errors.New("relay search needs at least one satellite")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+
+	grav := sats[0].Gravity
+	srcECEF, dstECEF := LLAToECEF(src, grav), LLAToECEF(dst, grav)
+
+	var paths []RelayPath
+	var open *RelayPath
+	for _, t := range sampleTimes(opts.Start, opts.Stop, step) {
+		hops := relayPathAt(t, srcECEF, dstECEF, src, dst, sats, opts)
+		if open != nil && !slices.Equal(open.Hops, hops) {
+			open.Stop = t
+			paths = append(paths, *open)
+			open = nil
+		}
+		if open == nil && hops != nil {
+			open = &RelayPath{Start: t, Hops: hops}
+		}
+	}
+	if open != nil {
+		open.Stop = opts.Stop
+		paths = append(paths, *open)
+	}
+	return paths, nil
+}
+
+// Returns the satellites of the fewest hop path at t or nil when the stations are not connected
+func relayPathAt(t time.Time, srcECEF, dstECEF Vector3, src, dst LatLongAlt, sats []Satellite, opts RelayOptions) []int64 {
+	gmst := gmstAt(t)
+	pos := make([]Vector3, len(sats))
+	ok := make([]bool, len(sats))
+	for i := range sats {
+		eci, _, err := sats[i].propagateAt(t)
+		if err == nil {
+			pos[i], ok[i] = ECIToECEF(eci, gmst), true
+		}
+	}
+
+	grav := sats[0].Gravity
+	srcMinEl := observerMinElevation(opts.MinElevation, src, opts.HorizonDip, grav)
+	dstMinEl := observerMinElevation(opts.MinElevation, dst, opts.HorizonDip, grav)
+
+	// Breadth first search from the source station over satellites
+	prev := make([]int, len(sats))
+	var queue []int
+	for i := range sats {
+		prev[i] = -2
+		if ok[i] && elevationOf(pos[i], srcECEF, src) >= srcMinEl {
+			prev[i] = -1
+			queue = append(queue, i)
+		}
+	}
+	radius := grav.radiusearthkm
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if elevationOf(pos[i], dstECEF, dst) >= dstMinEl {
+			var hops []int64
+			for j := i; j >= 0; j = prev[j] {
+				hops = append(hops, sats[j].Satnum)
+			}
+			slices.Reverse(hops)
+			return hops
+		}
+		for j := range sats {
+			if prev[j] != -2 || !ok[j] {
+				continue
+			}
+			if distance(pos[i], pos[j]) <= float64(opts.MaxCrosslinkRange) && clearOfEarth(pos[i], pos[j], radius) {
+				prev[j] = i
+				queue = append(queue, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Reports whether the straight line between two points stays above the earth radius
+func clearOfEarth(a, b Vector3, radius float64) bool {
+	d := Vector3{b.X - a.X, b.Y - a.Y, b.Z - a.Z}
+	dd := dot(d, d)
+	if dd == 0 {
+		return a.Magnitude() > radius
+	}
+	s := math.Max(0, math.Min(1, -dot(a, d)/dd))
+	closest := Vector3{a.X + s*d.X, a.Y + s*d.Y, a.Z + s*d.Z}
+	return closest.Magnitude() > radius
+}
@@ -0,0 +1,74 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Returns a ring of satellites spread evenly in mean anomaly along the ISS orbit plane
+func relayTestConstellation(count int) []Satellite {
+	line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+	line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+	sats := make([]Satellite, count)
+	for i := range sats {
+		satnum := fmt.Sprintf("%05d", 90000+i)
+		mo := fmt.Sprintf("%8.4f", math.Mod(325.0288+float64(i)*360/float64(count), 360))
+		sat, err := NewSatFromTLE(line1[:2]+satnum+line1[7:], line2[:2]+satnum+line2[7:43]+mo+line2[51:], "wgs72")
+		Expect(err).To(BeNil())
+		sats[i] = sat
+	}
+	return sats
+}
+
+var _ = Describe("RelayPaths", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+	newYork := NewLatLongAlt(40.7128, -74.0060, 0.01)
+
+	It("should find single and multi hop paths that respect the link constraints", func() {
+		sats := relayTestConstellation(12)
+		opts := RelayOptions{Start: start, Stop: start.Add(24 * time.Hour), MaxCrosslinkRange: 6000}
+		paths, err := RelayPaths(copenhagen, newYork, sats, opts)
+		Expect(err).To(BeNil())
+		Expect(paths).To(Not(BeEmpty()))
+
+		multi := false
+		for i, p := range paths {
+			Expect(p.Stop).To(BeTemporally(">", p.Start))
+			Expect(p.Hops).To(Not(BeEmpty()))
+			if len(p.Hops) > 1 {
+				multi = true
+			}
+			if i > 0 {
+				Expect(p.Start).To(BeTemporally(">=", paths[i-1].Stop))
+			}
+		}
+		Expect(multi).To(BeTrue())
+	})
+
+	It("should find fewer connected intervals without crosslinks", func() {
+		sats := relayTestConstellation(12)
+		opts := RelayOptions{Start: start, Stop: start.Add(24 * time.Hour), MaxCrosslinkRange: 6000}
+		linked, err := RelayPaths(copenhagen, newYork, sats, opts)
+		Expect(err).To(BeNil())
+
+		opts.MaxCrosslinkRange = 0
+		direct, err := RelayPaths(copenhagen, newYork, sats, opts)
+		Expect(err).To(BeNil())
+		for _, p := range direct {
+			Expect(p.Hops).To(HaveLen(1))
+		}
+
+		total := func(paths []RelayPath) (d time.Duration) {
+			for _, p := range paths {
+				d += p.Stop.Sub(p.Start)
+			}
+			return
+		}
+		Expect(total(direct)).To(BeNumerically("<", total(linked)))
+	})
+})
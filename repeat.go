@@ -0,0 +1,123 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Ascending equator crossing of the ground track
+type NodeCrossing struct {
+	Time time.Time
+
+	// Geodetic longitude of the crossing in radians, in the range [-pi, pi)
+	Longitude float64
+}
+
+// Ground track repeat cycle detected from propagated equator crossings
+type RepeatCycle struct {
+	// Revolutions making up one cycle and the mean cycle length in days
+	Revolutions int
+	Days        float64
+
+	// Mean eastward shift of the crossing longitude after one cycle in radians
+	// and the same drift per day
+	Drift, DriftRate float64
+
+	// Ascending node crossings the cycle was detected from
+	Crossings []NodeCrossing
+}
+
+// Returns the ascending node crossings of the ground track between start and stop
+func (sat *Satellite) NodeCrossings(start, stop time.Time) ([]NodeCrossing, error) {
+	if !stop.After(start) {
+		return nil, errors.New("crossing search stop time must be after start time")
+	}
+	var perr error
+	z := func(t time.Time) float64 {
+		pos, _, err := sat.propagateAt(t)
+		if err != nil && perr == nil {
+			perr = err
+		}
+		return pos.Z
+	}
+
+	var crossings []NodeCrossing
+	// Ten samples per revolution keep at most one crossing between samples
+	step := time.Duration(TWOPI / sat.no / 10 * float64(time.Minute))
+	times := sampleTimes(start, stop, step)
+	prev := z(times[0])
+	for k := 1; k < len(times); k++ {
+		now := z(times[k])
+		if perr != nil {
+			return crossings, perr
+		}
+		if prev < 0 && now >= 0 {
+			lo := times[k-1]
+			sec := bisect(func(s float64) float64 {
+				return z(lo.Add(time.Duration(s * float64(time.Second))))
+			}, 0, times[k].Sub(lo).Seconds(), 1e-3)
+			t := lo.Add(time.Duration(sec * float64(time.Second)))
+			pos, _, err := sat.propagateAt(t)
+			if err != nil {
+				return crossings, err
+			}
+			_, _, ll := ECIToLLA(pos, gmstAt(t))
+			crossings = append(crossings, NodeCrossing{Time: t, Longitude: wrapPi(ll.Longitude)})
+		}
+		prev = now
+	}
+	return crossings, nil
+}
+
+// Detects the ground track repeat cycle from the ascending node crossings over the
+// given number of days. The shortest cycle whose mean crossing longitude drift is
+// within tolerance radians is reported; when none is, the cycle with the smallest
+// drift is. Cycles are tried up to half the crossings found.
+func (sat *Satellite) DetectRepeatCycle(start time.Time, days int, tolerance float64) (RepeatCycle, error) {
+	crossings, err := sat.NodeCrossings(start, start.AddDate(0, 0, days))
+	if err != nil {
+		return RepeatCycle{}, err
+	}
+	if len(crossings) < 4 {
+		return RepeatCycle{}, errors.New("too few equator crossings to detect a repeat cycle")
+	}
+
+	var best RepeatCycle
+	bestAbs := math.Inf(1)
+	for k := 1; k <= len(crossings)/2; k++ {
+		var drift, cycleDays float64
+		n := len(crossings) - k
+		for i := 0; i < n; i++ {
+			drift += wrapPi(crossings[i+k].Longitude - crossings[i].Longitude)
+			cycleDays += crossings[i+k].Time.Sub(crossings[i].Time).Hours() / 24
+		}
+		drift /= float64(n)
+		cycleDays /= float64(n)
+
+		cycle := RepeatCycle{Revolutions: k, Days: cycleDays, Drift: drift, DriftRate: drift / cycleDays, Crossings: crossings}
+		if math.Abs(drift) <= tolerance {
+			return cycle, nil
+		}
+		if math.Abs(drift) < bestAbs {
+			best, bestAbs = cycle, math.Abs(drift)
+		}
+	}
+	return best, nil
+}
+
+// Estimates the repeat cycle analytically from the secular rates of the mean elements,
+// returning the smallest number of days up to maxDays after which the ground track
+// repeats within 0.01 revolutions and the revolutions flown in that time
+func (sat *Satellite) RepeatTrackEstimate(maxDays int) (revolutions, days int, ok bool) {
+	// Revolutions per day measured from the node and nodal days per solar day
+	revsPerDay := (sat.mdot + sat.argpdot) * 1440 / TWOPI
+	nodalDaysPerDay := (earthAngularVelocity*60 - sat.nodedot) * 1440 / TWOPI
+	q := revsPerDay / nodalDaysPerDay
+	for d := 1; d <= maxDays; d++ {
+		if r := q * float64(d); math.Abs(r-math.Round(r)) < 0.01 {
+			return int(math.Round(r)), d, true
+		}
+	}
+	return 0, 0, false
+}
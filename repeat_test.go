@@ -0,0 +1,59 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("repeat", func() {
+	// Landsat-like sun-synchronous orbit with a nominal 233 revolution, 16 day cycle
+	landsat := func() Satellite {
+		sat, err := NewSatFromTLE(
+			"1 39084U 13008A   20001.00000000  .00000023  00000-0  15000-4 0  9990",
+			"2 39084  98.2100  70.0000 0001200  95.0000 265.1000 14.57107000123456", "wgs72")
+		Expect(err).NotTo(HaveOccurred())
+		return sat
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	Describe("RepeatTrackEstimate", func() {
+		It("should find the nominal cycle", func() {
+			sat := landsat()
+			revs, days, ok := sat.RepeatTrackEstimate(30)
+			Expect(ok).To(BeTrue())
+			Expect(revs).To(Equal(233))
+			Expect(days).To(Equal(16))
+		})
+	})
+
+	Describe("DetectRepeatCycle", func() {
+		It("should detect the cycle from propagated crossings", func() {
+			sat := landsat()
+			cycle, err := sat.DetectRepeatCycle(start, 40, 0.002)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cycle.Revolutions).To(Equal(233))
+			Expect(cycle.Days).To(BeNumerically("~", 16, 0.01))
+			Expect(math.Abs(cycle.Drift)).To(BeNumerically("<", 0.002))
+			Expect(cycle.DriftRate).To(BeNumerically("~", cycle.Drift/cycle.Days, 1e-12))
+		})
+
+		It("should space crossings by one nodal period", func() {
+			sat := landsat()
+			crossings, err := sat.NodeCrossings(start, start.Add(24*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(crossings)).To(BeNumerically(">=", 14))
+			for i := 1; i < len(crossings); i++ {
+				Expect(crossings[i].Time.Sub(crossings[i-1].Time).Minutes()).To(BeNumerically("~", 1440.0/14.571, 0.5))
+			}
+		})
+
+		It("should reject a window with too few crossings", func() {
+			sat := landsat()
+			_, err := sat.DetectRepeatCycle(start, 0, 0.002)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
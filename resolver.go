@@ -0,0 +1,187 @@
+package satellite
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Maps satellite names to NORAD catalog numbers and back. Names are matched ignoring
+// case and punctuation, by exact name, by word prefix and finally by edit distance.
+// A Resolver is safe for concurrent use.
+type Resolver struct {
+	mu    sync.RWMutex
+	names map[int64]string
+	keys  map[int64]string
+}
+
+// Candidate returned by a name search
+type NameMatch struct {
+	Satnum int64
+	Name   string
+
+	// Edit distance between the query and the name, zero for exact and prefix matches
+	Distance int
+}
+
+// Creates an empty resolver
+func NewResolver() *Resolver {
+	return &Resolver{names: make(map[int64]string), keys: make(map[int64]string)}
+}
+
+// Records the name of a catalog number, replacing any earlier name
+func (r *Resolver) Add(satnum int64, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names[satnum] = strings.TrimSpace(name)
+	r.keys[satnum] = nameKey(name)
+}
+
+// Loads names from SATCAT data in CelesTrak CSV format, using the OBJECT_NAME and
+// NORAD_CAT_ID columns
+func (r *Resolver) LoadSATCAT(rd io.Reader) error {
+	records := csv.NewReader(rd)
+	header, err := records.Read()
+	if err != nil {
+		return err
+	}
+	nameCol, idCol := slices.Index(header, "OBJECT_NAME"), slices.Index(header, "NORAD_CAT_ID")
+	if nameCol < 0 || idCol < 0 {
+		return errors.New("SATCAT data needs OBJECT_NAME and NORAD_CAT_ID columns")
+	}
+	for {
+		rec, err := records.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		satnum, err := strconv.ParseInt(strings.TrimSpace(rec[idCol]), 10, 64)
+		if err != nil {
+			return newError(ErrUnknownSatellite, "bad NORAD_CAT_ID %q", rec[idCol])
+		}
+		r.Add(satnum, rec[nameCol])
+	}
+}
+
+// Returns the name recorded for a catalog number
+func (r *Resolver) Name(satnum int64) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.names[satnum]
+	return name, ok
+}
+
+// Resolves a catalog number such as "25544" or a name such as "ISS (ZARYA)" or "iss"
+// to a known catalog number. Returns ErrAmbiguousName when several satellites match
+// equally well.
+func (r *Resolver) Resolve(query string) (int64, error) {
+	if satnum, err := strconv.ParseInt(strings.TrimSpace(query), 10, 64); err == nil {
+		if _, ok := r.Name(satnum); !ok {
+			return 0, newError(ErrUnknownSatellite, "no satellite with catalog number %d", satnum)
+		}
+		return satnum, nil
+	}
+	matches := r.Search(query, 0)
+	switch {
+	case len(matches) == 0:
+		return 0, newError(ErrUnknownSatellite, "no satellite named %q", query)
+	case len(matches) > 1 && matches[1].Distance == matches[0].Distance:
+		return 0, newError(ErrAmbiguousName, "%q matches %s and %s", query, matches[0].Name, matches[1].Name)
+	}
+	return matches[0].Satnum, nil
+}
+
+// Returns up to limit satellites matching a name, best first; limit <= 0 returns all.
+// Exact matches come before prefix matches, which hide edit distance matches.
+func (r *Resolver) Search(query string, limit int) []NameMatch {
+	key := nameKey(query)
+	if key == "" {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var exact, prefix, fuzzy []NameMatch
+	maxDist := max(1, len(key)/4)
+	for satnum, k := range r.keys {
+		m := NameMatch{Satnum: satnum, Name: r.names[satnum]}
+		switch {
+		case k == key:
+			exact = append(exact, m)
+		case hasWordPrefix(k, key):
+			prefix = append(prefix, m)
+		default:
+			if m.Distance = editDistance(k, key); m.Distance <= maxDist {
+				fuzzy = append(fuzzy, m)
+			}
+		}
+	}
+	matches := exact
+	if len(matches) == 0 {
+		matches = prefix
+	}
+	if len(matches) == 0 {
+		matches = fuzzy
+	}
+	slices.SortFunc(matches, func(a, b NameMatch) int {
+		if c := cmp.Compare(a.Distance, b.Distance); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Satnum, b.Satnum)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Returns the name in upper case with runs of punctuation and space folded into one space
+func nameKey(name string) string {
+	fields := strings.FieldsFunc(strings.ToUpper(name), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Reports whether the name, or a word of it, starts with prefix
+func hasWordPrefix(name, prefix string) bool {
+	for {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+		i := strings.IndexByte(name, ' ')
+		if i < 0 {
+			return false
+		}
+		name = name[i+1:]
+	}
+}
+
+// Returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
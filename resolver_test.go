@@ -0,0 +1,72 @@
+package satellite
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const satcatSample = `OBJECT_NAME,OBJECT_ID,NORAD_CAT_ID,OBJECT_TYPE,OPS_STATUS_CODE,OWNER
+ISS (ZARYA),1998-067A,25544,PAY,+,ISS
+NOAA 19,2009-005A,33591,PAY,+,US
+NOAA 18,2005-018A,28654,PAY,+,US
+HUBBLE SPACE TELESCOPE,1990-037B,20580,PAY,+,US
+`
+
+var _ = Describe("Resolver", func() {
+	var r *Resolver
+
+	BeforeEach(func() {
+		r = NewResolver()
+		Expect(r.LoadSATCAT(strings.NewReader(satcatSample))).To(Succeed())
+	})
+
+	It("should resolve catalog numbers and names interchangeably", func() {
+		for _, q := range []string{"25544", "ISS (ZARYA)", "iss zarya", "ISS"} {
+			satnum, err := r.Resolve(q)
+			Expect(err).To(BeNil(), q)
+			Expect(satnum).To(Equal(int64(25544)), q)
+		}
+		name, ok := r.Name(25544)
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("ISS (ZARYA)"))
+	})
+
+	It("should match word prefixes and misspellings", func() {
+		satnum, err := r.Resolve("hubble")
+		Expect(err).To(BeNil())
+		Expect(satnum).To(Equal(int64(20580)))
+
+		satnum, err = r.Resolve("telescope")
+		Expect(err).To(BeNil())
+		Expect(satnum).To(Equal(int64(20580)))
+
+		satnum, err = r.Resolve("NOA 19")
+		Expect(err).To(BeNil())
+		Expect(satnum).To(Equal(int64(33591)))
+	})
+
+	It("should report ambiguous and unknown queries", func() {
+		_, err := r.Resolve("NOAA")
+		Expect(errors.Is(err, ErrAmbiguousName)).To(BeTrue())
+		Expect(r.Search("NOAA", 0)).To(HaveLen(2))
+		Expect(r.Search("NOAA", 1)).To(HaveLen(1))
+
+		_, err = r.Resolve("TIANGONG")
+		Expect(errors.Is(err, ErrUnknownSatellite)).To(BeTrue())
+		_, err = r.Resolve("99999")
+		Expect(errors.Is(err, ErrUnknownSatellite)).To(BeTrue())
+	})
+
+	It("should prefer exact names over prefixes", func() {
+		r.Add(90001, "ISS DEB")
+		satnum, err := r.Resolve("ISS DEB")
+		Expect(err).To(BeNil())
+		Expect(satnum).To(Equal(int64(90001)))
+
+		_, err = r.Resolve("ISS")
+		Expect(errors.Is(err, ErrAmbiguousName)).To(BeTrue())
+	})
+})
@@ -0,0 +1,138 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// One rise and set of a satellite over an observer
+type RiseSet struct {
+	Satnum int64
+
+	Rise, Culmination, Set time.Time
+
+	// Azimuth at rise and set and the elevation at culmination, in radians
+	RiseAzimuth, SetAzimuth float64
+	MaxElevation            float64
+}
+
+// Options for RiseSetTable
+type RiseSetOptions struct {
+	// Minimum elevation in radians and whether to lower it by the horizon dip of an
+	// elevated observer, see AccessOptions
+	MinElevation Radians
+	HorizonDip   bool
+
+	// Optional terrain or obstruction profile, see AccessOptions
+	Mask *HorizonMask
+
+	// Sampling step near a pass, 30 seconds when zero. Far from the observer the step grows
+	// to the shortest time the ground track needs to come within reach.
+	Step time.Duration
+
+	// Optional progress callback, invoked after each satellite
+	Progress ProgressFunc[RiseSet]
+}
+
+// Computes the rise and set times of every satellite over the observer between start and stop,
+// ordered by rise time, using all CPU cores. Instead of sampling the whole window at a fixed
+// step the search skips ahead by the time the sub-satellite point needs at its fastest to
+// reach the edge of the access footprint, so windows of months stay cheap. Edges are refined
+// to a tenth of a second. Satellites that fail to propagate are skipped. On cancellation the
+// rows found so far are returned with the context error.
+func RiseSetTable(ctx context.Context, sats []Satellite, obs LatLongAlt, start, stop time.Time, opts RiseSetOptions) (rows []RiseSet, err error) {
+	if !stop.After(start) {
+		return nil, errors.New("rise/set stop time must be after start time")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = 30 * time.Second
+	}
+
+	ctx, span := startSpan(ctx, "satellite.RiseSetTable",
+		slog.Int("satellite.count", len(sats)),
+		slog.Float64("window.minutes", stop.Sub(start).Minutes()))
+	defer func() { span.End(err) }()
+	log := Logger().With("job", "risesets")
+
+	var mu sync.Mutex
+	progress := newProgressReporter(opts.Progress, len(sats))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(sats)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				found, err := sats[i].riseSets(obs, start, stop, step, opts)
+				if err != nil {
+					log.Debug("rise/set search failed", "satnum", sats[i].Satnum, "err", err)
+					found = nil
+				}
+				mu.Lock()
+				rows = append(rows, found...)
+				progress.add(1, found)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range sats {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].Rise.Equal(rows[j].Rise) {
+			return rows[i].Rise.Before(rows[j].Rise)
+		}
+		return rows[i].Satnum < rows[j].Satnum
+	})
+	span.SetAttributes(slog.Int("rise_set.count", len(rows)))
+	log.Info("rise/set search finished", "satellites", len(sats), "rows", len(rows))
+	return rows, err
+}
+
+// Returns the rise and set times of one satellite sampled with orbit aware adaptive steps
+func (sat *Satellite) riseSets(obs LatLongAlt, start, stop time.Time, step time.Duration, opts RiseSetOptions) ([]RiseSet, error) {
+	a := newAccessSearch(sat, obs, AccessOptions{MinElevation: opts.MinElevation, HorizonDip: opts.HorizonDip, Mask: opts.Mask})
+	footprint, ok := sat.accessFootprint(a.minEl)
+	if !ok {
+		return nil, nil
+	}
+	footprint += groundTrackMargin
+
+	// Fastest motion of the sub-satellite point: the orbital rate at perigee plus the Earth rotation
+	rate := sat.no/60*math.Sqrt(1+sat.ecco)/math.Pow(1-sat.ecco, 1.5) + earthAngularVelocity
+	obsRadius := a.obs.Magnitude()
+
+	windows, err := a.scan(start, stop, -1, func(_ time.Time, access bool, satECEF Vector3) time.Duration {
+		if access {
+			return step
+		}
+		angle := math.Acos(math.Max(-1, math.Min(1, dot(satECEF, a.obs)/(satECEF.Magnitude()*obsRadius))))
+		return max(time.Duration((angle-footprint)/rate*float64(time.Second)), step)
+	})
+	rows := make([]RiseSet, len(windows))
+	for i, w := range windows {
+		rows[i] = RiseSet{
+			Satnum:       sat.Satnum,
+			Rise:         w.Start,
+			Culmination:  w.Culmination,
+			Set:          w.Stop,
+			RiseAzimuth:  w.StartAngles.Az,
+			SetAzimuth:   w.StopAngles.Az,
+			MaxElevation: w.CulminationAngles.El,
+		}
+	}
+	return rows, err
+}
@@ -0,0 +1,59 @@
+package satellite
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RiseSetTable", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should find the same passes as the fixed step search", func() {
+		sats := jobTestSatellites()
+		sats = []Satellite{sats[0], sats[3]}
+		stop := start.Add(72 * time.Hour)
+		opts := RiseSetOptions{MinElevation: Degrees(5).Radians()}
+
+		var progress []Progress
+		opts.Progress = func(p Progress, _ []RiseSet) { progress = append(progress, p) }
+		rows, err := RiseSetTable(context.Background(), sats, copenhagen, start, stop, opts)
+		Expect(err).To(BeNil())
+		Expect(progress).To(HaveLen(2))
+		Expect(progress[1].Found).To(Equal(len(rows)))
+
+		var want []Pass
+		for i := range sats {
+			passes, err := PredictPasses(&sats[i], copenhagen, start, stop, PassOptions{MinElevation: opts.MinElevation})
+			Expect(err).To(BeNil())
+			want = append(want, passes...)
+		}
+		Expect(rows).To(HaveLen(len(want)))
+		for i := 1; i < len(rows); i++ {
+			Expect(rows[i].Rise).NotTo(BeTemporally("<", rows[i-1].Rise))
+		}
+		for _, p := range want {
+			Expect(rows).To(ContainElement(Satisfy(func(r RiseSet) bool {
+				return r.Satnum == p.Satnum && r.Rise.Sub(p.AOS).Abs() < time.Second && r.Set.Sub(p.LOS).Abs() < time.Second
+			})))
+		}
+	})
+
+	It("should cover long windows", func() {
+		sat := jobTestSatellites()[3]
+		stop := start.Add(30 * 24 * time.Hour)
+		rows, err := RiseSetTable(context.Background(), []Satellite{sat}, copenhagen, start, stop, RiseSetOptions{})
+		Expect(err).To(BeNil())
+
+		passes, err := PredictPasses(&sat, copenhagen, start, stop, PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(rows).To(HaveLen(len(passes)))
+		for i, r := range rows {
+			Expect(r.Rise).To(BeTemporally("~", passes[i].AOS, time.Second))
+			Expect(r.MaxElevation).To(BeNumerically("~", passes[i].MaxElevation, 1e-3))
+		}
+	})
+})
@@ -0,0 +1,185 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Mechanical limits of an azimuth/elevation antenna rotator, angles in radians and rates in
+// rad/s. An azimuth range wider than 2pi, such as 0 to 450 deg, gives an overlap region the
+// planner uses to avoid unwinding during a pass. An elevation range reaching past pi/2 allows
+// flipped operation.
+type Rotator struct {
+	MinAzimuth, MaxAzimuth     float64
+	MinElevation, MaxElevation float64
+
+	// Largest slew rates, unlimited when zero
+	AzimuthRate, ElevationRate float64
+}
+
+// How a rotator follows a pass
+type RotatorMode int
+
+const (
+	// Azimuth and elevation of the satellite as they are
+	RotatorNormal RotatorMode = iota
+
+	// Azimuth turned by pi and elevation mirrored to pi-el for the whole pass, which moves
+	// the azimuth path away from the stop
+	RotatorFlipped
+
+	// Normal up to culmination and flipped after it, which carries overhead passes through
+	// the zenith with the azimuth nearly still
+	RotatorFlipAtTCA
+)
+
+func (m RotatorMode) String() string {
+	switch m {
+	case RotatorNormal:
+		return "normal"
+	case RotatorFlipped:
+		return "flipped"
+	case RotatorFlipAtTCA:
+		return "flip at TCA"
+	}
+	return "unknown"
+}
+
+// Rotator angles commanded at one moment of a pass
+type RotatorPoint struct {
+	Time               time.Time
+	Azimuth, Elevation float64
+}
+
+// How a rotator can follow a pass
+type RotatorPlan struct {
+	Mode RotatorMode
+
+	// Multiple of 2pi added to the commanded azimuths to keep them within the travel
+	AzimuthOffset float64
+
+	// Commanded angles at every track point. Azimuths are continuous and may leave 0 to 2pi.
+	Points []RotatorPoint
+
+	// Largest slew rates the plan needs in rad/s
+	AzimuthRate, ElevationRate float64
+
+	// Whether the rotator can follow the plan, and why not otherwise
+	Feasible bool
+	Reason   string
+
+	// The normal mode runs into a zenith keyhole, where the azimuth rate exceeds the rotator,
+	// or into the azimuth stop
+	Keyhole, AzimuthWrap bool
+}
+
+// Analyzes a pass for zenith keyholes and azimuth wrap and returns the first plan the rotator
+// can follow, trying normal, flipped and flip at TCA operation in that order. Flipped modes
+// need an elevation range past pi/2. When no mode works the normal plan is returned with
+// Feasible false and the reason.
+func (r Rotator) PlanPass(p *Pass) (RotatorPlan, error) {
+	track, err := p.Track()
+	if err != nil {
+		return RotatorPlan{}, err
+	}
+	return r.planTrack(track, p.TCA)
+}
+
+// Plans a track culminating at tca
+func (r Rotator) planTrack(track []TrackPoint, tca time.Time) (RotatorPlan, error) {
+	if len(track) == 0 {
+		return RotatorPlan{}, errors.New("pass has no track points")
+	}
+	if r.MaxAzimuth <= r.MinAzimuth || r.MaxElevation <= r.MinElevation {
+		return RotatorPlan{}, errors.New("rotator limits are empty")
+	}
+
+	normal := r.plan(track, tca, RotatorNormal)
+	normal.Keyhole = r.AzimuthRate > 0 && normal.AzimuthRate > r.AzimuthRate
+	normal.AzimuthWrap = !r.fitsAzimuth(normal)
+	if normal.Feasible || r.MaxElevation <= math.Pi/2 {
+		return normal, nil
+	}
+	for _, mode := range []RotatorMode{RotatorFlipped, RotatorFlipAtTCA} {
+		plan := r.plan(track, tca, mode)
+		if plan.Feasible {
+			plan.Keyhole, plan.AzimuthWrap = normal.Keyhole, normal.AzimuthWrap
+			return plan, nil
+		}
+	}
+	return normal, nil
+}
+
+// Builds the commanded angles of one mode and checks them against the limits
+func (r Rotator) plan(track []TrackPoint, tca time.Time, mode RotatorMode) RotatorPlan {
+	plan := RotatorPlan{Mode: mode, Points: make([]RotatorPoint, len(track))}
+	for i, tp := range track {
+		az, el := tp.Angles.Az, tp.Angles.El
+		if mode == RotatorFlipped || mode == RotatorFlipAtTCA && tp.Time.After(tca) {
+			az, el = az+math.Pi, math.Pi-el
+		}
+		if i > 0 {
+			// Continue from the previous azimuth instead of jumping across north
+			az = plan.Points[i-1].Azimuth + wrapPi(az-plan.Points[i-1].Azimuth)
+		} else {
+			az = math.Mod(az, TWOPI)
+		}
+		plan.Points[i] = RotatorPoint{Time: tp.Time, Azimuth: az, Elevation: el}
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, pt := range plan.Points {
+		lo, hi = math.Min(lo, pt.Azimuth), math.Max(hi, pt.Azimuth)
+	}
+	// Smallest turn count that lifts the path above the lower stop, if it then clears the upper
+	plan.AzimuthOffset = TWOPI * math.Ceil((r.MinAzimuth-lo)/TWOPI)
+	if lo+plan.AzimuthOffset < r.MinAzimuth || hi+plan.AzimuthOffset > r.MaxAzimuth {
+		plan.AzimuthOffset = 0
+		plan.Reason = fmt.Sprintf("azimuth path of %.1f deg does not fit the rotator travel", (hi-lo)*RAD2DEG)
+	}
+	for i := range plan.Points {
+		plan.Points[i].Azimuth += plan.AzimuthOffset
+	}
+
+	var azAt, elAt time.Time
+	for i, pt := range plan.Points {
+		if pt.Elevation < r.MinElevation || pt.Elevation > r.MaxElevation {
+			if plan.Reason == "" {
+				plan.Reason = fmt.Sprintf("elevation %.1f deg outside the rotator range at %s", pt.Elevation*RAD2DEG, pt.Time.Format(time.RFC3339))
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		dt := pt.Time.Sub(plan.Points[i-1].Time).Seconds()
+		if dt <= 0 {
+			continue
+		}
+		if rate := math.Abs(pt.Azimuth-plan.Points[i-1].Azimuth) / dt; rate > plan.AzimuthRate {
+			plan.AzimuthRate, azAt = rate, pt.Time
+		}
+		if rate := math.Abs(pt.Elevation-plan.Points[i-1].Elevation) / dt; rate > plan.ElevationRate {
+			plan.ElevationRate, elAt = rate, pt.Time
+		}
+	}
+	if plan.Reason == "" && r.AzimuthRate > 0 && plan.AzimuthRate > r.AzimuthRate {
+		plan.Reason = fmt.Sprintf("azimuth rate %.2f deg/s exceeds %.2f deg/s at %s", plan.AzimuthRate*RAD2DEG, r.AzimuthRate*RAD2DEG, azAt.Format(time.RFC3339))
+	}
+	if plan.Reason == "" && r.ElevationRate > 0 && plan.ElevationRate > r.ElevationRate {
+		plan.Reason = fmt.Sprintf("elevation rate %.2f deg/s exceeds %.2f deg/s at %s", plan.ElevationRate*RAD2DEG, r.ElevationRate*RAD2DEG, elAt.Format(time.RFC3339))
+	}
+	plan.Feasible = plan.Reason == ""
+	return plan
+}
+
+// Reports whether the commanded azimuths of a plan lie within the rotator travel
+func (r Rotator) fitsAzimuth(plan RotatorPlan) bool {
+	for _, pt := range plan.Points {
+		if pt.Azimuth < r.MinAzimuth || pt.Azimuth > r.MaxAzimuth {
+			return false
+		}
+	}
+	return true
+}
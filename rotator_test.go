@@ -0,0 +1,88 @@
+package satellite
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rotator", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	// Track of ten second points with azimuth and elevation given in degrees
+	track := func(angles ...[2]float64) []TrackPoint {
+		points := make([]TrackPoint, len(angles))
+		for i, a := range angles {
+			points[i] = TrackPoint{
+				Time:   start.Add(time.Duration(i) * 10 * time.Second),
+				Angles: LookAngles{Az: math.Mod(a[0]+360, 360) * DEG2RAD, El: a[1] * DEG2RAD},
+			}
+		}
+		return points
+	}
+	// Pass from the north west through north to the north east
+	northern := track([2]float64{300, 5}, [2]float64{330, 30}, [2]float64{0, 40}, [2]float64{30, 30}, [2]float64{60, 5})
+	// Overhead pass from the east to the west
+	overhead := track([2]float64{90, 10}, [2]float64{90, 60}, [2]float64{90, 89.5}, [2]float64{270, 60}, [2]float64{270, 10})
+
+	It("should use the azimuth overlap of the rotator", func() {
+		r := Rotator{MaxAzimuth: 450 * DEG2RAD, MaxElevation: 90 * DEG2RAD}
+		plan, err := r.planTrack(northern, northern[2].Time)
+		Expect(err).To(BeNil())
+		Expect(plan.Feasible).To(BeTrue())
+		Expect(plan.Mode).To(Equal(RotatorNormal))
+		Expect(plan.AzimuthWrap).To(BeFalse())
+		Expect(plan.Points[4].Azimuth * RAD2DEG).To(BeNumerically("~", 420, 1e-9))
+	})
+
+	It("should flip passes crossing the azimuth stop", func() {
+		r := Rotator{MaxAzimuth: TWOPI, MaxElevation: math.Pi}
+		plan, err := r.planTrack(northern, northern[2].Time)
+		Expect(err).To(BeNil())
+		Expect(plan.Feasible).To(BeTrue())
+		Expect(plan.AzimuthWrap).To(BeTrue())
+		Expect(plan.Mode).To(Equal(RotatorFlipped))
+		Expect(plan.Points[0].Azimuth * RAD2DEG).To(BeNumerically("~", 120, 1e-9))
+		Expect(plan.Points[2].Elevation * RAD2DEG).To(BeNumerically("~", 140, 1e-9))
+
+		r.MaxElevation = math.Pi / 2
+		plan, err = r.planTrack(northern, northern[2].Time)
+		Expect(err).To(BeNil())
+		Expect(plan.Feasible).To(BeFalse())
+		Expect(plan.Reason).To(ContainSubstring("azimuth path"))
+	})
+
+	It("should carry overhead passes through the zenith keyhole", func() {
+		r := Rotator{MaxAzimuth: 450 * DEG2RAD, MaxElevation: math.Pi, AzimuthRate: 6 * DEG2RAD, ElevationRate: 6 * DEG2RAD}
+		plan, err := r.planTrack(overhead, overhead[2].Time)
+		Expect(err).To(BeNil())
+		Expect(plan.Keyhole).To(BeTrue())
+		Expect(plan.Feasible).To(BeTrue())
+		Expect(plan.Mode).To(Equal(RotatorFlipAtTCA))
+		Expect(plan.AzimuthRate).To(BeNumerically("<", 1e-9))
+		Expect(plan.Points[4].Elevation * RAD2DEG).To(BeNumerically("~", 170, 1e-9))
+
+		r.MaxElevation = math.Pi / 2
+		plan, err = r.planTrack(overhead, overhead[2].Time)
+		Expect(err).To(BeNil())
+		Expect(plan.Feasible).To(BeFalse())
+		Expect(plan.Mode).To(Equal(RotatorNormal))
+		Expect(plan.Reason).To(ContainSubstring("azimuth rate"))
+	})
+
+	It("should plan predicted passes", func() {
+		sat := jobTestSatellites()[0]
+		passes, err := PredictPasses(&sat, NewLatLongAlt(55.6167, 12.6500, 0.005), start, start.Add(12*time.Hour), PassOptions{})
+		Expect(err).To(BeNil())
+		Expect(passes).NotTo(BeEmpty())
+
+		r := Rotator{MaxAzimuth: 450 * DEG2RAD, MaxElevation: 90 * DEG2RAD, AzimuthRate: 6 * DEG2RAD, ElevationRate: 6 * DEG2RAD}
+		plan, err := r.PlanPass(&passes[0])
+		Expect(err).To(BeNil())
+		track, _ := passes[0].Track()
+		Expect(plan.Points).To(HaveLen(len(track)))
+		Expect(plan.Feasible).To(Equal(plan.Reason == ""))
+	})
+})
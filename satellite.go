@@ -1,7 +1,13 @@
 package satellite
 
-// Struct for holding satellite information during and before propagation
+// Struct for holding the initialized SGP4 state of a satellite. Propagation only reads it and
+// keeps the intermediate values per call, so one Satellite can be propagated from several
+// goroutines at once.
 type Satellite struct {
+	// Object name from the name line of a three line element set or the OMM OBJECT_NAME,
+	// empty when not given
+	Name string
+
 	Line1 string
 	Line2 string
 
@@ -25,6 +31,19 @@ type Satellite struct {
 	alta  float64
 	altp  float64
 
+	// Kozai mean motion of the element set in rad/min, no holds the recovered Brouwer value
+	noKozai float64
+
+	// Provider of a supplemental element set, empty for standard GP elements
+	source string
+
+	// Element set metadata, see the accessors
+	classification string
+	designator     string
+	ephemerisType  int
+	elementSetNo   int64
+	revNumber      int64
+
 	method        string
 	operationmode string
 	init          string
@@ -113,3 +132,40 @@ type Satellite struct {
 	xlamo float64
 	atime float64
 }
+
+// Reports whether the element set is a supplemental one derived from operator data
+func (sat *Satellite) Supplemental() bool {
+	return sat.source != ""
+}
+
+// Returns the provider of a supplemental element set, such as SpaceX, or an empty string
+func (sat *Satellite) Source() string {
+	return sat.source
+}
+
+// Returns the security classification of the element set, U for unclassified, or an
+// empty string when not given
+func (sat *Satellite) Classification() string {
+	return sat.classification
+}
+
+// Returns the international designator in the TLE form of two digit launch year, launch
+// number and piece, such as 98067A, or an empty string when not given
+func (sat *Satellite) InternationalDesignator() string {
+	return sat.designator
+}
+
+// Returns the ephemeris type, zero for SGP4 element sets
+func (sat *Satellite) EphemerisType() int {
+	return sat.ephemerisType
+}
+
+// Returns the element set number, zero when missing
+func (sat *Satellite) ElementSetNumber() int64 {
+	return sat.elementSetNo
+}
+
+// Returns the revolution number at epoch, zero when missing
+func (sat *Satellite) RevolutionNumber() int64 {
+	return sat.revNumber
+}
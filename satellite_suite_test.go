@@ -4,6 +4,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"testing"
@@ -36,15 +38,89 @@ var _ = Describe("go-satellite", func() {
 			hour, min, sec := time.Clock()
 
 			jDay := NewJDay(year, int(month), day, hour, min, float64(sec))
-			pos, _, err := sat.Propagate(jDay)
+			pos, _, err := sat.PropagateJDay(jDay)
 
 			latLongAlt := NewLatLongAlt(55.6167, 12.6500, 0.005)
 
-			angles := ECIToLookAngles(pos, latLongAlt, jDay.Single(), sat.Gravity)
+			angles := ECIToLookAngles(pos, latLongAlt, jDay, sat.Gravity)
 
 			Expect(err).To(BeNil())
-			Expect(angles.El * RAD2DEG).To(Equal(42.06164214709452))
-			Expect(angles.Az * RAD2DEG).To(Equal(181.2902281625632))
+			Expect(angles.El * RAD2DEG).To(Equal(42.061642152239536))
+			Expect(angles.Az * RAD2DEG).To(Equal(181.2902276931812))
+		})
+
+		It("should convert look angles to degrees and format them", func() {
+			angles := LookAngles{Az: 123.4 * DEG2RAD, El: 45.6 * DEG2RAD, Rg: 789.2}
+
+			deg := angles.Deg()
+			Expect(float64(deg.Az)).To(BeNumerically("~", 123.4, 1e-9))
+			Expect(float64(deg.El)).To(BeNumerically("~", 45.6, 1e-9))
+			Expect(deg.Rg).To(Equal(Kilometers(789.2)))
+			Expect(angles.String()).To(Equal("Az 123.4° El 45.6° Rng 789 km"))
+		})
+	})
+
+	Describe("PropagationError", func() {
+		It("should report the offending quantity and time for invalid mean elements", func() {
+			sat, err := NewSatFromTLE(
+				"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+				"2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+				"wgs72")
+			Expect(err).To(BeNil())
+
+			_, _, err = sat.sgp4(1e7)
+
+			var perr *PropagationError
+			Expect(errors.As(err, &perr)).To(BeTrue())
+			Expect(errors.Is(err, ErrOutsideValidity)).To(BeTrue())
+			Expect(errors.Is(err, ErrMeanElements)).To(BeTrue())
+			Expect(errors.Is(err, ErrSatelliteDecayed)).To(BeFalse())
+			Expect(perr.Code).To(Equal(1))
+			Expect(perr.Satnum).To(Equal(int64(6251)))
+			Expect(perr.Tsince).To(Equal(1e7))
+			Expect(perr.Value).To(BeNumerically("<", 0))
+			Expect(perr.Time.Year()).To(Equal(2025))
+			Expect(perr.Error()).To(ContainSubstring("satellite 6251 at 2025-06-30"))
+		})
+
+		It("should report sub-orbital epoch elements", func() {
+			sat, err := NewSatFromTLE(
+				"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+				"2 25544  51.6416 247.4627 1000000 130.5360 180.0000 15.72125391563537",
+				"wgs72")
+
+			var perr *PropagationError
+			Expect(errors.As(err, &perr)).To(BeTrue())
+			Expect(errors.Is(err, ErrSubOrbital)).To(BeTrue())
+			Expect(errors.Is(err, ErrSatelliteDecayed)).To(BeTrue())
+			Expect(perr.Code).To(Equal(5))
+			Expect(perr.Tsince).To(BeZero())
+			Expect(perr.Time).To(Equal(sat.EpochTime()))
+			// Perigee radius a(1 - e), about 6060 km for e = 0.1
+			Expect(perr.Value).To(BeNumerically("~", 6060, 10))
+			Expect(perr.Value).To(BeNumerically("<", sat.Gravity.radiusearthkm))
+			Expect(perr.Error()).To(ContainSubstring("epoch elements are sub-orbital"))
+		})
+
+		It("should match the sentinel of each code and its category", func() {
+			cases := []struct {
+				code     int
+				sentinel error
+				category error
+			}{
+				{2, ErrNegativeMeanMotion, ErrOutsideValidity},
+				{3, ErrNegativeEccentricity, ErrOutsideValidity},
+				{4, ErrSemiLatusRectum, ErrOutsideValidity},
+				{5, ErrSubOrbital, ErrSatelliteDecayed},
+				{6, ErrSatelliteDecayed, ErrSatelliteDecayed},
+			}
+			for _, c := range cases {
+				var err error = &PropagationError{Code: c.code}
+				Expect(errors.Is(err, c.sentinel)).To(BeTrue())
+				Expect(errors.Is(err, c.category)).To(BeTrue())
+				Expect(errors.Is(fmt.Errorf("wrapped: %w", err), c.sentinel)).To(BeTrue())
+			}
+			Expect(errors.Is(&PropagationError{Code: 6}, ErrOutsideValidity)).To(BeFalse())
 		})
 	})
 
@@ -56,6 +132,57 @@ var _ = Describe("go-satellite", func() {
 
 			Expect(err).To(Not(BeNil()))
 			Expect(err.Error()).To(Equal("Line1 length should be 69 but was 68"))
+			Expect(errors.Is(err, ErrBadLineLength)).To(BeTrue())
+		})
+
+		It("should return ErrUnknownGravModel on unknown gravity model", func() {
+			_, err := NewSatFromTLE(
+				"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+				"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+				"wgs66")
+
+			Expect(errors.Is(err, ErrUnknownGravModel)).To(BeTrue())
+		})
+
+		It("should verify checksums in strict mode", func() {
+			line1 := "1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927"
+			line2 := "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"
+			Expect(TLEChecksum(line1)).To(Equal(7))
+			Expect(TLEChecksum(line2)).To(Equal(7))
+
+			sat, err := ParseTLEStrict(line1, line2)
+			Expect(err).To(BeNil())
+			Expect(sat.Satnum).To(Equal(int64(25544)))
+
+			_, err = ParseTLEStrict(line1[:68]+"3", line2)
+			Expect(errors.Is(err, ErrChecksum)).To(BeTrue())
+			Expect(err.Error()).To(Equal("Line1 checksum should be 7 but was 3"))
+
+			_, err = ParseTLEStrict(line2, line1)
+			Expect(err).To(MatchError(`Line1 should start with "1 "`))
+		})
+
+		It("should ignore trailing white space and line breaks", func() {
+			sat, err := ParseTLE(
+				"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927  \r\n",
+				"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537\r")
+
+			Expect(err).To(BeNil())
+			Expect(sat.Line1).To(HaveLen(69))
+			Expect(sat.Line2).To(HaveLen(69))
+		})
+
+		It("should keep the name of a three line element set", func() {
+			for _, line0 := range []string{"ISS (ZARYA)             ", "0 ISS (ZARYA)"} {
+				sat, err := NewSatFrom3LE(line0,
+					"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+					"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537",
+					"wgs72")
+
+				Expect(err).To(BeNil())
+				Expect(sat.Name).To(Equal("ISS (ZARYA)"))
+				Expect(sat.Satnum).To(Equal(int64(25544)))
+			}
 		})
 
 		It("should not return error on no standard TLE", func() {
@@ -326,7 +453,8 @@ var _ = Describe("go-satellite", func() {
 })
 
 type PropagationTestCase struct {
-	line1, line2, testData, grav string
+	line1, line2, testData string
+	grav                   GravModel
 }
 
 func propagationTest(testCase PropagationTestCase) {
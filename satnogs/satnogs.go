@@ -0,0 +1,71 @@
+// Package satnogs fetches transmitter data from the SatNOGS DB for Doppler planning.
+package satnogs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	satellite "github.com/mpielikis/go-satellite"
+)
+
+// Default location of the SatNOGS DB transmitters API
+const DefaultBaseURL = "https://db.satnogs.org/api/transmitters/"
+
+// Client for the SatNOGS DB. The zero value uses http.DefaultClient and DefaultBaseURL.
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+}
+
+// Client used by the package level functions
+var DefaultClient = &Client{}
+
+// Fetches the transmitters of one satellite
+func FetchTransmitters(ctx context.Context, satnum int64) (satellite.TransmitterTable, error) {
+	return DefaultClient.FetchTransmitters(ctx, satnum)
+}
+
+// Fetches the transmitters of one satellite
+func (c *Client) FetchTransmitters(ctx context.Context, satnum int64) (satellite.TransmitterTable, error) {
+	tt, err := c.query(ctx, url.Values{"satellite__norad_cat_id": {strconv.FormatInt(satnum, 10)}, "format": {"json"}})
+	if err != nil {
+		return nil, fmt.Errorf("satnogs transmitters of %d: %w", satnum, err)
+	}
+	return tt, nil
+}
+
+// Fetches the transmitters of every satellite in the database
+func (c *Client) FetchAllTransmitters(ctx context.Context) (satellite.TransmitterTable, error) {
+	tt, err := c.query(ctx, url.Values{"format": {"json"}})
+	if err != nil {
+		return nil, fmt.Errorf("satnogs transmitters: %w", err)
+	}
+	return tt, nil
+}
+
+func (c *Client) query(ctx context.Context, params url.Values) (satellite.TransmitterTable, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return satellite.ReadSatNOGSTransmitters(resp.Body)
+}
@@ -0,0 +1,13 @@
+package satnogs
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSatnogs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Satnogs Suite")
+}
@@ -0,0 +1,44 @@
+package satnogs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const issTransmitters = `[
+{"uuid":"a","description":"Mode V/V FM Voice","alive":true,"type":"Transceiver","uplink_low":145990000,"uplink_high":null,"downlink_low":145800000,"downlink_high":null,"mode":"FM","invert":false,"baud":null,"norad_cat_id":25544,"status":"active"},
+{"uuid":"b","description":"APRS","alive":true,"type":"Transceiver","uplink_low":145825000,"uplink_high":null,"downlink_low":145825000,"downlink_high":null,"mode":"AFSK","invert":false,"baud":1200,"norad_cat_id":25544,"status":"active"}
+]`
+
+var _ = Describe("FetchTransmitters", func() {
+	It("should query the transmitters of one satellite", func() {
+		var query string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Write([]byte(issTransmitters))
+		}))
+		defer server.Close()
+
+		client := &Client{BaseURL: server.URL}
+		tt, err := client.FetchTransmitters(context.Background(), 25544)
+		Expect(err).To(BeNil())
+		Expect(query).To(Equal("format=json&satellite__norad_cat_id=25544"))
+		Expect(tt[25544]).To(HaveLen(2))
+		Expect(tt[25544][1].Baud).To(Equal(1200.0))
+	})
+
+	It("should report failed queries", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := &Client{BaseURL: server.URL}
+		_, err := client.FetchAllTransmitters(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("503")))
+	})
+})
@@ -0,0 +1,32 @@
+package satellite
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Returns a tracking schedule of the pass: look angles and range rate from AOS to LOS every
+// step, 1 second when zero. Unlike Track the schedule is computed on every call.
+func (p *Pass) Schedule(step time.Duration) ([]TrackPoint, error) {
+	if p.track == nil {
+		return nil, errors.New("pass has no satellite to compute the schedule from")
+	}
+	if step <= 0 {
+		step = time.Second
+	}
+	return trackPoints(p.track.sat, p.track.obs, p.AOS, p.LOS, step)
+}
+
+// Writes a tracking schedule one point per line: the UTC time in RFC 3339 with milliseconds,
+// azimuth and elevation in degrees, range in km and range rate in km/s separated by spaces
+func WriteSchedule(w io.Writer, points []TrackPoint) error {
+	bw := bufio.NewWriter(w)
+	for _, pt := range points {
+		fmt.Fprintf(bw, "%s %8.3f %7.3f %10.3f %7.4f\n", pt.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+			pt.Angles.Az*RAD2DEG, pt.Angles.El*RAD2DEG, pt.Angles.Rg, pt.RangeRate)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,54 @@
+package satellite
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pass.Schedule", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should sample the pass at the requested cadence", func() {
+		sat := jobTestSatellites()[0]
+		passes, err := NextPasses(&sat, copenhagen, start, 1, PassOptions{MinElevation: Degrees(10).Radians()})
+		Expect(err).To(BeNil())
+		p := passes[0]
+
+		points, err := p.Schedule(0)
+		Expect(err).To(BeNil())
+		Expect(points[0].Time).To(Equal(p.AOS))
+		Expect(points[len(points)-1].Time).To(Equal(p.LOS))
+		Expect(points[1].Time.Sub(points[0].Time)).To(Equal(time.Second))
+		Expect(len(points)).To(BeNumerically("~", int(p.Duration/time.Second)+1, 1))
+
+		track, err := p.Track()
+		Expect(err).To(BeNil())
+		Expect(points[10]).To(Equal(track[1]))
+	})
+
+	It("should reject a pass without a satellite", func() {
+		_, err := (&Pass{}).Schedule(time.Second)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("WriteSchedule", func() {
+	It("should write one line per point", func() {
+		points := []TrackPoint{
+			{Time: time.Date(2008, 9, 20, 18, 1, 2, 500e6, time.UTC), Angles: LookAngles{Az: 123.4567 * DEG2RAD, El: 12.3456 * DEG2RAD, Rg: 1234.5678}, RangeRate: -6.54321},
+			{Time: time.Date(2008, 9, 20, 18, 1, 3, 500e6, time.UTC), Angles: LookAngles{Az: 5 * DEG2RAD, El: 0.5 * DEG2RAD, Rg: 2000}, RangeRate: 1},
+		}
+		var buf bytes.Buffer
+		Expect(WriteSchedule(&buf, points)).To(Succeed())
+		Expect(strings.Split(buf.String(), "\n")).To(Equal([]string{
+			"2008-09-20T18:01:02.500Z  123.457  12.346   1234.568 -6.5432",
+			"2008-09-20T18:01:03.500Z    5.000   0.500   2000.000  1.0000",
+			"",
+		}))
+	})
+})
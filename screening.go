@@ -0,0 +1,215 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// Close approach between two satellites found by Screen
+type Conjunction struct {
+	Primary, Secondary int64
+
+	// Time of closest approach
+	TCA time.Time
+
+	// Distance at closest approach in km
+	MissDistance float64
+
+	// Relative speed at closest approach in km/s
+	RelativeSpeed float64
+}
+
+// Options for a conjunction screening job
+type ScreeningOptions struct {
+	Start, Stop time.Time
+
+	// Sampling step of the coarse search, one minute when zero
+	Step time.Duration
+
+	// Approaches closer than this distance in km are reported
+	Threshold Kilometers
+
+	// Checkpoint of a canceled job to resume from
+	Resume Checkpoint
+
+	// Optional progress callback, invoked after each primary satellite
+	Progress ProgressFunc[Conjunction]
+}
+
+// Margin in km added to the apsis prefilter to cover short period perturbations
+const screeningApsisMargin = 50.0
+
+// Screens every pair of satellites for close approaches within the time window.
+// Pairs whose perigee/apogee shells are farther apart than the threshold are
+// skipped. The job is canceled through ctx: on cancellation the conjunctions
+// found so far are returned together with a checkpoint that resumes the job
+// and the context error. A completed job returns an empty checkpoint.
+func Screen(ctx context.Context, sats []Satellite, opts ScreeningOptions) (conjunctions []Conjunction, next Checkpoint, err error) {
+	if !opts.Stop.After(opts.Start) {
+		return nil, "", errors.New("screening stop time must be after start time")
+	}
+	if opts.Threshold <= 0 {
+		return nil, "", errors.New("screening threshold must be positive")
+	}
+	step := opts.Step
+	if step <= 0 {
+		step = time.Minute
+	}
+
+	fp := newFingerprint().satellites(sats).time(opts.Start).time(opts.Stop).int(int64(step)).float(float64(opts.Threshold)).sum()
+	first, err := opts.Resume.resume("screen", fp)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, span := startSpan(ctx, "satellite.Screen",
+		slog.Int("satellite.count", len(sats)),
+		slog.Float64("window.minutes", opts.Stop.Sub(opts.Start).Minutes()),
+		slog.Float64("threshold.km", float64(opts.Threshold)))
+	defer func() { span.End(err) }()
+
+	log := Logger().With("job", "screen")
+	log.Info("screening started", "satellites", len(sats), "start", opts.Start, "stop", opts.Stop, "resume", first)
+
+	times := sampleTimes(opts.Start, opts.Stop, step)
+	ephem := make([][]Vector3, len(sats))
+	perigee := make([]float64, len(sats))
+	apogee := make([]float64, len(sats))
+	for i := range sats {
+		perigee[i], apogee[i] = sats[i].apsisRadii()
+		if i < first {
+			continue
+		}
+		ephem[i] = sampleEphemeris(&sats[i], times)
+	}
+
+	progress := newProgressReporter(opts.Progress, len(sats))
+	progress.done = first
+	candidates := 0
+	stepMin := step.Minutes()
+
+	for i := first; i < len(sats); i++ {
+		if err = ctx.Err(); err != nil {
+			next = newCheckpoint("screen", fp, i)
+			log.Info("screening canceled", "next", i, "found", len(conjunctions))
+			span.SetAttributes(slog.Int("candidate.count", candidates), slog.Int("conjunction.count", len(conjunctions)))
+			return
+		}
+
+		var found []Conjunction
+		for j := i + 1; j < len(sats); j++ {
+			if math.Max(perigee[i], perigee[j])-math.Min(apogee[i], apogee[j]) > float64(opts.Threshold)+screeningApsisMargin {
+				continue
+			}
+			candidates++
+			found = append(found, screenPair(&sats[i], &sats[j], ephem[i], ephem[j], times, stepMin, float64(opts.Threshold))...)
+		}
+
+		conjunctions = append(conjunctions, found...)
+		progress.add(1, found)
+	}
+
+	span.SetAttributes(slog.Int("candidate.count", candidates), slog.Int("conjunction.count", len(conjunctions)))
+	log.Info("screening finished", "candidates", candidates, "found", len(conjunctions))
+	return conjunctions, "", nil
+}
+
+// Finds the close approaches of one pair from the sampled ephemerides
+func screenPair(a, b *Satellite, ea, eb []Vector3, times []time.Time, stepMin, threshold float64) (found []Conjunction) {
+	dist := make([]float64, len(times))
+	for k := range times {
+		dist[k] = distance(ea[k], eb[k])
+	}
+
+	for k := range dist {
+		if math.IsNaN(dist[k]) {
+			continue
+		}
+		if k > 0 && !(dist[k] <= dist[k-1]) {
+			continue
+		}
+		if k < len(dist)-1 && !(dist[k] < dist[k+1]) {
+			continue
+		}
+
+		// The true minimum lies between the neighbouring samples and can be up to
+		// one step of relative motion below the sampled distance
+		lo := times[max(k-1, 0)]
+		hi := times[min(k+1, len(times)-1)]
+		_, va, errA := a.propagateAt(times[k])
+		_, vb, errB := b.propagateAt(times[k])
+		if errA != nil || errB != nil {
+			continue
+		}
+		speed := distance(va, vb)
+		if dist[k] > threshold+speed*stepMin*60 {
+			continue
+		}
+
+		f := func(m float64) float64 {
+			t := lo.Add(time.Duration(m * float64(time.Minute)))
+			pa, _, errA := a.propagateAt(t)
+			pb, _, errB := b.propagateAt(t)
+			if errA != nil || errB != nil {
+				return math.Inf(1)
+			}
+			return distance(pa, pb)
+		}
+		m, miss := goldenSection(f, 0, hi.Sub(lo).Minutes(), 1e-4)
+		if miss >= threshold {
+			continue
+		}
+
+		tca := lo.Add(time.Duration(m * float64(time.Minute)))
+		_, va, _ = a.propagateAt(tca)
+		_, vb, _ = b.propagateAt(tca)
+		found = append(found, Conjunction{
+			Primary:       a.Satnum,
+			Secondary:     b.Satnum,
+			TCA:           tca,
+			MissDistance:  miss,
+			RelativeSpeed: distance(va, vb),
+		})
+	}
+	return
+}
+
+// Returns the mean perigee and apogee radii in km
+func (sat *Satellite) apsisRadii() (perigee, apogee float64) {
+	a := math.Pow(sat.Gravity.xke/sat.no, 2.0/3.0) * sat.Gravity.radiusearthkm
+	return a * (1 - sat.ecco), a * (1 + sat.ecco)
+}
+
+// Returns the sample times from start to stop inclusive
+func sampleTimes(start, stop time.Time, step time.Duration) []time.Time {
+	n := int(stop.Sub(start)/step) + 1
+	times := make([]time.Time, 0, n+1)
+	for t := start; !t.After(stop); t = t.Add(step) {
+		times = append(times, t)
+	}
+	if times[len(times)-1].Before(stop) {
+		times = append(times, stop)
+	}
+	return times
+}
+
+// Propagates sat to each time, leaving NaN positions where propagation fails
+func sampleEphemeris(sat *Satellite, times []time.Time) []Vector3 {
+	ephem := make([]Vector3, len(times))
+	for k, t := range times {
+		pos, _, err := sat.propagateAt(t)
+		if err != nil {
+			pos = Vector3{math.NaN(), math.NaN(), math.NaN()}
+		}
+		ephem[k] = pos
+	}
+	return ephem
+}
+
+// Returns the distance between two vectors
+func distance(a, b Vector3) float64 {
+	return Vector3{a.X - b.X, a.Y - b.Y, a.Z - b.Z}.Magnitude()
+}
@@ -0,0 +1,41 @@
+package satellite
+
+import "math"
+
+// Finds the minimum of f inside [a, b] by golden section search until the
+// bracket is narrower than tol
+func goldenSection(f func(float64) float64, a, b, tol float64) (x, fx float64) {
+	const invPhi = 0.6180339887498949
+	c := b - (b-a)*invPhi
+	d := a + (b-a)*invPhi
+	fc, fd := f(c), f(d)
+	for math.Abs(b-a) > tol {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - (b-a)*invPhi
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + (b-a)*invPhi
+			fd = f(d)
+		}
+	}
+	x = (a + b) / 2
+	return x, f(x)
+}
+
+// Finds where f changes sign inside [a, b] by bisection until the bracket is
+// narrower than tol. f(a) and f(b) must have opposite signs.
+func bisect(f func(float64) float64, a, b, tol float64) float64 {
+	fa := f(a)
+	for math.Abs(b-a) > tol {
+		m := (a + b) / 2
+		fm := f(m)
+		if (fm > 0) == (fa > 0) {
+			a, fa = m, fm
+		} else {
+			b = m
+		}
+	}
+	return (a + b) / 2
+}
@@ -0,0 +1,56 @@
+package satellite
+
+import "math"
+
+// Imaging sensor carried by a satellite, used to constrain access windows to
+// feasible imaging opportunities
+type Sensor struct {
+	// Half angle in radians of the sensor field of view around its boresight
+	HalfAngle Radians
+
+	// Maximum angle in radians the platform can roll the boresight away from nadir,
+	// across the ground track. Zero means a fixed nadir pointing sensor.
+	MaxRoll Radians
+
+	// Minimum elevation of the sun above the target horizon in radians, applied
+	// when SunConstraint is set
+	MinSunElevation Radians
+	SunConstraint   bool
+}
+
+// Returns the sensor margin for a target, positive while the sensor can image it.
+// Positions and velocity are given in Earth fixed axes; the velocity is the inertial
+// velocity rotated into those axes so it defines the orbit plane.
+func (s *Sensor) margin(satPos, satVel, target Vector3, targetCoords LatLongAlt, sunECEF Vector3) float64 {
+	los := Vector3{target.X - satPos.X, target.Y - satPos.Y, target.Z - satPos.Z}
+	r := satPos.Magnitude()
+	nadir := Vector3{-satPos.X / r, -satPos.Y / r, -satPos.Z / r}
+	normal := cross(satPos, satVel)
+	nm := normal.Magnitude()
+	normal = Vector3{normal.X / nm, normal.Y / nm, normal.Z / nm}
+
+	// Roll toward the target as far as the platform allows, then test the field of view
+	roll := math.Atan2(dot(los, normal), dot(los, nadir))
+	roll = math.Max(-float64(s.MaxRoll), math.Min(float64(s.MaxRoll), roll))
+	boresight := Vector3{
+		math.Cos(roll)*nadir.X + math.Sin(roll)*normal.X,
+		math.Cos(roll)*nadir.Y + math.Sin(roll)*normal.Y,
+		math.Cos(roll)*nadir.Z + math.Sin(roll)*normal.Z,
+	}
+	off := math.Acos(math.Max(-1, math.Min(1, dot(los, boresight)/los.Magnitude())))
+	margin := float64(s.HalfAngle) - off
+
+	if s.SunConstraint {
+		margin = math.Min(margin, elevationOf(sunECEF, target, targetCoords)-float64(s.MinSunElevation))
+	}
+	return margin
+}
+
+// Returns the elevation of point p above the geodetic horizon of an observer,
+// both given in Earth fixed coordinates
+func elevationOf(p, obs Vector3, obsCoords LatLongAlt) float64 {
+	lat, lon := obsCoords.LatLong.Latitude, obsCoords.LatLong.Longitude
+	up := Vector3{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+	d := Vector3{p.X - obs.X, p.Y - obs.Y, p.Z - obs.Z}
+	return math.Asin(dot(d, up) / d.Magnitude())
+}
@@ -0,0 +1,59 @@
+package satellite
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sensor", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	total := func(windows []AccessWindow) (d time.Duration) {
+		for _, w := range windows {
+			d += w.Duration()
+		}
+		return
+	}
+
+	It("should only allow imaging when the target is within reach of the sensor", func() {
+		sat := jobTestSatellites()[3]
+		opts := AccessOptions{Horizon: 3 * 24 * time.Hour}
+		visible, err := NextAccesses(&sat, copenhagen, start, 100, opts)
+		Expect(err).To(BeNil())
+
+		opts.Sensor = &Sensor{HalfAngle: Degrees(5).Radians(), MaxRoll: Degrees(30).Radians()}
+		agile, err := NextAccesses(&sat, copenhagen, start, 100, opts)
+		Expect(err).To(BeNil())
+		Expect(agile).To(Not(BeEmpty()))
+
+		opts.Sensor = &Sensor{HalfAngle: Degrees(5).Radians()}
+		fixed, err := NextAccesses(&sat, copenhagen, start, 100, opts)
+		Expect(err).To(BeNil())
+
+		Expect(total(fixed)).To(BeNumerically("<", total(agile)))
+		Expect(total(agile)).To(BeNumerically("<", total(visible)))
+		for _, w := range agile {
+			Expect(w.CulminationAngles.El * RAD2DEG).To(BeNumerically(">", 40))
+		}
+	})
+
+	It("should respect the sun elevation at the target", func() {
+		sat := jobTestSatellites()[3]
+		opts := AccessOptions{
+			Horizon: 3 * 24 * time.Hour,
+			Sensor:  &Sensor{HalfAngle: Degrees(5).Radians(), MaxRoll: Degrees(45).Radians(), SunConstraint: true, MinSunElevation: Degrees(10).Radians()},
+		}
+		windows, err := NextAccesses(&sat, copenhagen, start, 100, opts)
+		Expect(err).To(BeNil())
+		Expect(windows).To(Not(BeEmpty()))
+
+		obs := LLAToECEF(copenhagen, sat.Gravity)
+		for _, w := range windows {
+			sun := ECIToECEF(SunPositionECI(NewJDayFromTime(w.Culmination)), gmstAt(w.Culmination))
+			Expect(elevationOf(sun, obs, copenhagen) * RAD2DEG).To(BeNumerically(">", 10))
+		}
+	})
+})
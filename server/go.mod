@@ -0,0 +1,21 @@
+module github.com/mpielikis/go-satellite/server
+
+go 1.21
+
+require (
+	github.com/mpielikis/go-satellite v0.0.0-00010101000000-000000000000
+	github.com/onsi/ginkgo v1.16.2
+	github.com/onsi/gomega v1.12.0
+	golang.org/x/net v0.35.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/nxadm/tail v1.4.8 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+)
+
+replace github.com/mpielikis/go-satellite => ../
@@ -0,0 +1,212 @@
+// Package server streams live satellite positions to web clients over WebSocket.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+	"golang.org/x/net/websocket"
+)
+
+// Fastest update interval a client may request, used when Server.MinRate is zero
+const DefaultMinRate = 100 * time.Millisecond
+
+// Most satellites a subscription may list, used when Server.MaxSatellites is zero
+const DefaultMaxSatellites = 100
+
+// Serves position updates of catalog members. The zero value is not usable, a Catalog must be set.
+type Server struct {
+	Catalog *satellite.Catalog
+
+	// Fastest update interval a client may request, DefaultMinRate when zero
+	MinRate time.Duration
+
+	// Most satellites a subscription may list, DefaultMaxSatellites when zero
+	MaxSatellites int
+
+	// Source of the current time, time.Now when nil
+	Now func() time.Time
+}
+
+// Returns a server streaming the members of the catalog
+func New(catalog *satellite.Catalog) *Server {
+	return &Server{Catalog: catalog}
+}
+
+// Subscription sent by a client as a JSON text message. Every new message replaces the
+// previous subscription of the connection.
+type Subscription struct {
+	Satnums []int64 `json:"satnums"`
+
+	// Update interval in milliseconds, one second when zero
+	RateMillis int `json:"rate_ms,omitempty"`
+
+	// Observer for look angles and range rate, left out when nil
+	Observer *Observer `json:"observer,omitempty"`
+}
+
+// Observer location in degrees and km
+type Observer struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Altitude  float64 `json:"alt"`
+}
+
+// Message sent to a client at every tick, one position per subscribed satellite. A
+// rejected subscription is answered with an update carrying only the error, the
+// previous subscription stays in effect.
+type Update struct {
+	Time      time.Time  `json:"time"`
+	Positions []Position `json:"positions"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Position of one satellite, angles in degrees, distances in km and rates in km/s
+type Position struct {
+	Satnum    int64    `json:"satnum"`
+	Latitude  float64  `json:"lat"`
+	Longitude float64  `json:"lon"`
+	Altitude  float64  `json:"alt"`
+	Azimuth   *float64 `json:"az,omitempty"`
+	Elevation *float64 `json:"el,omitempty"`
+	Range     *float64 `json:"range,omitempty"`
+	RangeRate *float64 `json:"range_rate,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Returns the WebSocket handler
+func (s *Server) Handler() http.Handler {
+	return websocket.Handler(s.serve)
+}
+
+func (s *Server) serve(ws *websocket.Conn) {
+	defer ws.Close()
+
+	subs := make(chan Subscription)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var sub Subscription
+			if err := websocket.JSON.Receive(ws, &sub); err != nil {
+				return
+			}
+			select {
+			case subs <- sub:
+			case <-ws.Request().Context().Done():
+				return
+			}
+		}
+	}()
+
+	var sub Subscription
+	var trackers []*satellite.Tracker
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+	for {
+		var u Update
+		select {
+		case <-done:
+			return
+		case next := <-subs:
+			if err := s.validate(next); err != nil {
+				u = Update{Time: s.now(), Error: err.Error()}
+				break
+			}
+			sub, trackers = next, make([]*satellite.Tracker, len(next.Satnums))
+			if ticker != nil {
+				ticker.Stop()
+			}
+			ticker = time.NewTicker(s.rate(sub))
+			tick = ticker.C
+			u = s.update(sub, trackers)
+		case <-tick:
+			u = s.update(sub, trackers)
+		}
+		if err := websocket.JSON.Send(ws, u); err != nil {
+			return
+		}
+	}
+}
+
+// Returns the current time of the server in UTC
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// Returns an error when the subscription lists more satellites than the server allows
+func (s *Server) validate(sub Subscription) error {
+	limit := s.MaxSatellites
+	if limit <= 0 {
+		limit = DefaultMaxSatellites
+	}
+	if len(sub.Satnums) > limit {
+		return fmt.Errorf("server: subscription lists %d satellites, at most %d allowed", len(sub.Satnums), limit)
+	}
+	return nil
+}
+
+// Returns the update interval of a subscription, limited by the server rate
+func (s *Server) rate(sub Subscription) time.Duration {
+	rate := time.Duration(sub.RateMillis) * time.Millisecond
+	if rate <= 0 {
+		rate = time.Second
+	}
+	minRate := s.MinRate
+	if minRate <= 0 {
+		minRate = DefaultMinRate
+	}
+	return max(rate, minRate)
+}
+
+// Returns the positions of the subscribed satellites now. The trackers of the
+// subscription are created at the first update and reused at every tick after it, with
+// the element set refreshed from the catalog.
+func (s *Server) update(sub Subscription, trackers []*satellite.Tracker) Update {
+	u := Update{Time: s.now(), Positions: make([]Position, len(sub.Satnums))}
+
+	var obs satellite.LatLongAlt
+	if o := sub.Observer; o != nil {
+		obs = satellite.NewLatLongAlt(o.Latitude, o.Longitude, o.Altitude)
+	}
+	for i, satnum := range sub.Satnums {
+		p := &u.Positions[i]
+		p.Satnum = satnum
+		sat, ok := s.Catalog.Get(satnum)
+		if !ok {
+			p.Error = satellite.ErrUnknownSatellite.Error()
+			continue
+		}
+		tr := trackers[i]
+		if tr == nil {
+			tr = satellite.NewTracker(&sat, obs)
+			trackers[i] = tr
+		} else {
+			tr.SetSatellite(&sat)
+		}
+		tu, err := tr.Update(u.Time)
+		if err != nil {
+			p.Error = err.Error()
+			continue
+		}
+		p.Latitude = float64(satellite.Radians(tu.Position.LatLong.Latitude).Degrees())
+		p.Longitude = float64(satellite.Radians(tu.Position.LatLong.Longitude).Degrees())
+		p.Altitude = tu.Position.AltitudeKm
+		if sub.Observer != nil {
+			az := float64(satellite.Radians(tu.Angles.Az).Degrees())
+			el := float64(satellite.Radians(tu.Angles.El).Degrees())
+			p.Azimuth, p.Elevation, p.Range, p.RangeRate = &az, &el, &tu.Angles.Rg, &tu.RangeRate
+		}
+	}
+	return u
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/websocket"
+)
+
+var _ = Describe("Server", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	var iss satellite.Satellite
+	var ts *httptest.Server
+	var ws *websocket.Conn
+
+	BeforeEach(func() {
+		var err error
+		iss, err = satellite.NewSatFromTLE(
+			"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+			"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537", "wgs72")
+		Expect(err).To(BeNil())
+
+		s := New(satellite.NewCatalog(iss))
+		s.MinRate = 10 * time.Millisecond
+		s.Now = func() time.Time { return start }
+		ts = httptest.NewServer(s.Handler())
+
+		ws, err = websocket.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), "", ts.URL)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		ws.Close()
+		ts.Close()
+	})
+
+	It("should stream positions of the subscribed satellites", func() {
+		Expect(websocket.JSON.Send(ws, Subscription{Satnums: []int64{25544, 99999}, RateMillis: 10})).To(Succeed())
+
+		lla, _, err := iss.PropagateLLADeg(start)
+		Expect(err).To(BeNil())
+		for i := 0; i < 3; i++ {
+			var u Update
+			Expect(websocket.JSON.Receive(ws, &u)).To(Succeed())
+			Expect(u.Time).To(Equal(start))
+			Expect(u.Positions).To(HaveLen(2))
+			p := u.Positions[0]
+			Expect(p.Satnum).To(Equal(int64(25544)))
+			Expect(p.Latitude).To(BeNumerically("~", lla.LatLong.Latitude, 1e-9))
+			Expect(p.Longitude).To(BeNumerically("~", lla.LatLong.Longitude, 1e-9))
+			Expect(p.Altitude).To(BeNumerically("~", lla.AltitudeKm, 1e-9))
+			Expect(p.Azimuth).To(BeNil())
+			Expect(u.Positions[1].Error).To(Equal(satellite.ErrUnknownSatellite.Error()))
+		}
+	})
+
+	It("should add look angles for an observer and follow a new subscription", func() {
+		Expect(websocket.JSON.Send(ws, Subscription{Satnums: []int64{99999}})).To(Succeed())
+		var u Update
+		Expect(websocket.JSON.Receive(ws, &u)).To(Succeed())
+		Expect(u.Positions[0].Satnum).To(Equal(int64(99999)))
+
+		obs := &Observer{Latitude: 55.6167, Longitude: 12.65, Altitude: 0.005}
+		Expect(websocket.JSON.Send(ws, Subscription{Satnums: []int64{25544}, RateMillis: 10, Observer: obs})).To(Succeed())
+		Eventually(func() int64 {
+			Expect(websocket.JSON.Receive(ws, &u)).To(Succeed())
+			return u.Positions[0].Satnum
+		}).Should(Equal(int64(25544)))
+
+		want, err := satellite.NewTracker(&iss, satellite.NewLatLongAlt(obs.Latitude, obs.Longitude, obs.Altitude)).Update(start)
+		Expect(err).To(BeNil())
+		p := u.Positions[0]
+		Expect(*p.Elevation).To(BeNumerically("~", float64(satellite.Radians(want.Angles.El).Degrees()), 1e-9))
+		Expect(*p.Azimuth).To(BeNumerically("~", float64(satellite.Radians(want.Angles.Az).Degrees()), 1e-9))
+		Expect(*p.Range).To(BeNumerically("~", want.Angles.Rg, 1e-9))
+		Expect(*p.RangeRate).To(BeNumerically("~", want.RangeRate, 1e-12))
+	})
+
+	It("should reject a subscription over the satellite limit and keep the previous one", func() {
+		Expect(websocket.JSON.Send(ws, Subscription{Satnums: []int64{25544}, RateMillis: 10})).To(Succeed())
+		var u Update
+		Expect(websocket.JSON.Receive(ws, &u)).To(Succeed())
+		Expect(u.Error).To(BeEmpty())
+
+		satnums := make([]int64, DefaultMaxSatellites+1)
+		Expect(websocket.JSON.Send(ws, Subscription{Satnums: satnums})).To(Succeed())
+		Eventually(func() string {
+			Expect(websocket.JSON.Receive(ws, &u)).To(Succeed())
+			return u.Error
+		}).Should(ContainSubstring("at most 100 allowed"))
+		Expect(u.Positions).To(BeEmpty())
+
+		var next Update
+		Expect(websocket.JSON.Receive(ws, &next)).To(Succeed())
+		Expect(next.Error).To(BeEmpty())
+		Expect(next.Positions).To(HaveLen(1))
+		Expect(next.Positions[0].Satnum).To(Equal(int64(25544)))
+	})
+})
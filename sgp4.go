@@ -1,14 +1,114 @@
 package satellite
 
 import (
+	"context"
 	"errors"
 	"math"
+	"time"
 )
 
-// Calculates position and velocity vectors for given time
-func (sat *Satellite) Propagate(jDay JDay) (position, velocity Vector3, err error) {
+// Calculates the TEME position in km and velocity in km/s at t. A failed propagation
+// returns a *PropagationError instead of the meaningless state.
+func (sat *Satellite) Propagate(t time.Time) (position, velocity Vector3, err error) {
+	tsince := sat.minutesSinceEpoch(t)
+	position, velocity, err = sat.sgp4(tsince)
+	if err != nil {
+		Logger().Debug("propagation failed", "satnum", sat.Satnum, "tsince", tsince, "err", err)
+	}
+	return
+}
+
+// Calculates TEME states every step from start to stop. The stop time is always included.
+// Propagation ends at the first failure, returning the states before it with the error.
+func (sat *Satellite) PropagateRange(start, stop time.Time, step time.Duration) ([]State, error) {
+	return sat.PropagateRangeContext(context.Background(), start, stop, step)
+}
+
+// PropagateRange with a context carrying the parent span of the propagation. On cancellation
+// the states before it are returned with the context error.
+func (sat *Satellite) PropagateRangeContext(ctx context.Context, start, stop time.Time, step time.Duration) (states []State, err error) {
+	if stop.Before(start) {
+		return nil, errors.New("propagation stop time must not be before start time")
+	}
+	if step <= 0 {
+		return nil, errors.New("propagation step must be positive")
+	}
+	_, span := startSpan(ctx, "satellite.PropagateRange", satelliteWindowAttributes(sat.Satnum, start, stop)...)
+	defer func() { span.End(err) }()
+	epoch := sat.jdsatepoch.ToTime()
+	times := sampleTimes(start, stop, step)
+	states = make([]State, 0, len(times))
+	for _, t := range times {
+		if err := ctx.Err(); err != nil {
+			return states, err
+		}
+		pos, vel, err := sat.sgp4(t.Sub(epoch).Minutes())
+		if err != nil {
+			Logger().Debug("propagation failed", "satnum", sat.Satnum, "time", t, "err", err)
+			return states, err
+		}
+		states = append(states, State{Time: t, Position: pos, Velocity: vel})
+	}
+	return states, nil
+}
+
+// Calculates position and velocity vectors for given julian date
+func (sat *Satellite) PropagateJDay(jDay JDay) (position, velocity Vector3, err error) {
 	tsince := jDay.SubtractDay(sat.jdsatepoch)
-	return sat.sgp4(tsince)
+	position, velocity, err = sat.sgp4(tsince)
+	if err != nil {
+		Logger().Debug("propagation failed", "satnum", sat.Satnum, "tsince", tsince, "err", err)
+	}
+	return
+}
+
+// Calculates position and velocity vectors in the Earth fixed frame for given time.
+// Velocity is relative to the rotating Earth.
+func (sat *Satellite) PropagateECEF(t time.Time) (position, velocity Vector3, err error) {
+	tsince := sat.minutesSinceEpoch(t)
+	eciPos, eciVel, err := sat.sgp4(tsince)
+	if err != nil {
+		Logger().Debug("propagation failed", "satnum", sat.Satnum, "tsince", tsince, "err", err)
+		return
+	}
+	position, velocity = ECIToECEFState(eciPos, eciVel, gmstAt(t))
+	return
+}
+
+// Calculates the geodetic position in radians and the speed of the sub-satellite point
+// over the Earth surface in km/s for given time
+func (sat *Satellite) PropagateLLA(t time.Time) (lla LatLongAlt, groundSpeed float64, err error) {
+	pos, vel, err := sat.PropagateECEF(t)
+	if err != nil {
+		return
+	}
+	alt, _, ll := ECIToLLA(pos, 0)
+	lla = LatLongAlt{LatLong: LatLong{Latitude: ll.Latitude, Longitude: wrapPi(ll.Longitude)}, AltitudeKm: alt}
+
+	// Horizontal velocity scaled down from orbit height to the surface
+	r := pos.Magnitude()
+	radial := (vel.X*pos.X + vel.Y*pos.Y + vel.Z*pos.Z) / r
+	horizontal := math.Sqrt(math.Max(0, vel.X*vel.X+vel.Y*vel.Y+vel.Z*vel.Z-radial*radial))
+	groundSpeed = horizontal * (r - alt) / r
+	return
+}
+
+// Same as PropagateLLA with latitude and longitude in degrees
+func (sat *Satellite) PropagateLLADeg(t time.Time) (lla LatLongAlt, groundSpeed float64, err error) {
+	lla, groundSpeed, err = sat.PropagateLLA(t)
+	lla.LatLong.Latitude *= RAD2DEG
+	lla.LatLong.Longitude *= RAD2DEG
+	return
+}
+
+// Returns the minutes elapsed from the TLE epoch to t
+func (sat *Satellite) minutesSinceEpoch(t time.Time) float64 {
+	return t.Sub(sat.jdsatepoch.ToTime()).Minutes()
+}
+
+// Calculates position and velocity vectors at t without logging failures
+func (sat *Satellite) propagateAt(t time.Time) (position, velocity Vector3, err error) {
+	return sat.sgp4(sat.minutesSinceEpoch(t))
 }
 
 // this procedure initializes variables for sgp4.
@@ -19,7 +119,9 @@ func (satrec *Satellite) sgp4init(epoch float64) (position, velocity Vector3, er
 	var cosim, sinim, em, emsq, argpm, nodem, inclm, mm, nm, s1, s2, s3, s4, s5, ss1, ss2, ss3, ss4, ss5, sz1, sz3, sz11, sz13, sz21, sz23, sz31, sz33, tc, z1, z3, z11, z13, z21, z23, z31, z33, xpidot float64
 
 	satrec.method = "n"
-	satrec.operationmode = "i"
+	if satrec.operationmode == "" {
+		satrec.operationmode = string(OpsModeImproved)
+	}
 
 	radiusearthkm := satrec.Gravity.radiusearthkm
 	j2 := satrec.Gravity.j2
@@ -185,7 +287,7 @@ func (satrec *Satellite) sgp4init(epoch float64) (position, velocity Vector3, er
 			satrec.zmol = dscomResults.zmol
 			satrec.zmos = dscomResults.zmos
 
-			dpperResults := dpper(satrec, inclm, satrec.init, satrec.ecco, satrec.inclo, satrec.nodeo, satrec.argpo, satrec.mo, satrec.operationmode)
+			dpperResults := dpper(satrec, satrec.t, inclm, satrec.init, satrec.ecco, satrec.inclo, satrec.nodeo, satrec.argpo, satrec.mo, satrec.operationmode)
 
 			satrec.ecco = dpperResults.ep
 			satrec.inclo = dpperResults.inclp
@@ -246,6 +348,11 @@ func (satrec *Satellite) sgp4init(epoch float64) (position, velocity Vector3, er
 	position, velocity, err = satrec.sgp4(0.0)
 	satrec.init = "n"
 
+	// Perigee below the earth surface, reported unless propagation at epoch already failed
+	if err == nil && rp < 1.0 {
+		err = satrec.propagationError(5, 0, rp*radiusearthkm)
+	}
+
 	return
 }
 
@@ -297,7 +404,7 @@ func (satrec *Satellite) initl(epoch float64) (ainv, no, ao, con41, con42, cosio
 			gsto = gsto + TWOPI
 		}
 	} else {
-		gsto = gstime(epoch + 2433281.5)
+		gsto = gstime(JDay{Day: epoch + 2433281.5})
 	}
 
 	return
@@ -320,31 +427,35 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 
 	vkmpersec := radiusearthkm * xke / 60.0
 
-	satrec.t = tsince
+	// Values the deep space branch recomputes at t are kept local so that propagation
+	// never writes to the satellite and one satellite can be propagated concurrently
+	t := tsince
+	aycof, xlcof := satrec.aycof, satrec.xlcof
+	con41, x1mth2, x7thm1 := satrec.con41, satrec.x1mth2, satrec.x7thm1
 
-	xmdf = satrec.mo + satrec.mdot*satrec.t
-	var argpdf = satrec.argpo + satrec.argpdot*satrec.t
-	nodedf = satrec.nodeo + satrec.nodedot*satrec.t
+	xmdf = satrec.mo + satrec.mdot*t
+	var argpdf = satrec.argpo + satrec.argpdot*t
+	nodedf = satrec.nodeo + satrec.nodedot*t
 	argpm = argpdf
 	mm = xmdf
-	t2 = satrec.t * satrec.t
+	t2 = t * t
 	nodem = nodedf + satrec.nodecf*t2
-	tempa = 1.0 - satrec.cc1*satrec.t
-	tempe = satrec.bstar * satrec.cc4 * satrec.t
+	tempa = 1.0 - satrec.cc1*t
+	tempe = satrec.bstar * satrec.cc4 * t
 	templ = satrec.t2cof * t2
 
 	if satrec.isimp != 1 {
-		delomg = satrec.omgcof * satrec.t
+		delomg = satrec.omgcof * t
 		delmtemp := 1.0 + satrec.eta*math.Cos(xmdf)
 		delm = satrec.xmcof * (delmtemp*delmtemp*delmtemp - satrec.delmo)
 		temp = delomg + delm
 		mm = xmdf + temp
 		argpm = argpdf - temp
-		t3 = t2 * satrec.t
-		t4 = t3 * satrec.t
+		t3 = t2 * t
+		t4 = t3 * t
 		tempa = tempa - satrec.d2*t2 - satrec.d3*t3 - satrec.d4*t4
 		tempe = tempe + satrec.bstar*satrec.cc5*(math.Sin(mm)-satrec.sinmao)
-		templ = templ + satrec.t3cof*t3 + t4*(satrec.t4cof+satrec.t*satrec.t5cof)
+		templ = templ + satrec.t3cof*t3 + t4*(satrec.t4cof+t*satrec.t5cof)
 	}
 
 	nm = satrec.no
@@ -352,9 +463,9 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	inclm = satrec.inclo
 
 	if satrec.method == "d" {
-		tc = satrec.t
+		tc = t
 
-		dspaceResult := dspace(satrec.irez, satrec.d2201, satrec.d2211, satrec.d3210, satrec.d3222, satrec.d4410, satrec.d4422, satrec.d5220, satrec.d5232, satrec.d5421, satrec.d5433, satrec.dedt, satrec.del1, satrec.del2, satrec.del3, satrec.didt, satrec.dmdt, satrec.dnodt, satrec.domdt, satrec.argpo, satrec.argpdot, satrec.t, tc, satrec.gsto, satrec.xfact, satrec.xlamo, satrec.no, satrec.atime, em, argpm, inclm, satrec.xli, mm, satrec.xni, nodem, nm)
+		dspaceResult := dspace(satrec.irez, satrec.d2201, satrec.d2211, satrec.d3210, satrec.d3222, satrec.d4410, satrec.d4422, satrec.d5220, satrec.d5232, satrec.d5421, satrec.d5433, satrec.dedt, satrec.del1, satrec.del2, satrec.del3, satrec.didt, satrec.dmdt, satrec.dnodt, satrec.domdt, satrec.argpo, satrec.argpdot, t, tc, satrec.gsto, satrec.xfact, satrec.xlamo, satrec.no, satrec.atime, em, argpm, inclm, satrec.xli, mm, satrec.xni, nodem, nm)
 
 		em = dspaceResult.em
 		argpm = dspaceResult.argpm
@@ -365,7 +476,7 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	}
 
 	if nm < 0.0 {
-		err = errors.New("Mean motion is less than zero")
+		err = satrec.propagationError(2, tsince, nm)
 		return
 	}
 
@@ -374,7 +485,7 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	em = em - tempe
 
 	if em >= 1.0 || em < -0.001 {
-		err = errors.New("mean eccentricity not within range 0.0 <= e < 1.0")
+		err = satrec.propagationError(1, tsince, em)
 		return
 	}
 
@@ -403,7 +514,7 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	cosip = cosim
 
 	if satrec.method == "d" {
-		dpperResults := dpper(satrec, satrec.inclo, "n", ep, xincp, nodep, argpp, mp, satrec.operationmode)
+		dpperResults := dpper(satrec, t, satrec.inclo, "n", ep, xincp, nodep, argpp, mp, satrec.operationmode)
 
 		ep = dpperResults.ep
 		xincp = dpperResults.inclp
@@ -418,7 +529,7 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 		}
 
 		if ep < 0.0 || ep > 1.0 {
-			err = errors.New("perturbed eccentricity not within range 0.0 <= e <= 1.0")
+			err = satrec.propagationError(3, tsince, ep)
 			return
 		}
 	}
@@ -426,18 +537,18 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	if satrec.method == "d" {
 		sinip = math.Sin(xincp)
 		cosip = math.Cos(xincp)
-		satrec.aycof = -0.5 * j3oj2 * sinip
+		aycof = -0.5 * j3oj2 * sinip
 		if math.Abs(cosip+1.0) > 1.5e-12 {
-			satrec.xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / (1.0 + cosip)
+			xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / (1.0 + cosip)
 		} else {
-			satrec.xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / temp4
+			xlcof = -0.25 * j3oj2 * sinip * (3.0 + 5.0*cosip) / temp4
 		}
 	}
 
 	axnl = ep * math.Cos(argpp)
 	temp = 1.0 / (am * (1.0 - ep*ep))
-	aynl = ep*math.Sin(argpp) + temp*satrec.aycof
-	xl = mp + argpp + nodep + temp*satrec.xlcof*axnl
+	aynl = ep*math.Sin(argpp) + temp*aycof
+	xl = mp + argpp + nodep + temp*xlcof*axnl
 
 	u = math.Mod((xl - nodep), TWOPI)
 	eo1 = u
@@ -466,7 +577,7 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	pl = am * (1.0 - el2)
 
 	if pl < 0.0 {
-		err = errors.New("semilatus rectum is less than zero")
+		err = satrec.propagationError(4, tsince, pl)
 		return
 	} else {
 		rl = am * (1.0 - ecose)
@@ -485,17 +596,17 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 
 		if satrec.method == "d" {
 			cosisq = cosip * cosip
-			satrec.con41 = 3.0*cosisq - 1.0
-			satrec.x1mth2 = 1.0 - cosisq
-			satrec.x7thm1 = 7.0*cosisq - 1.0
+			con41 = 3.0*cosisq - 1.0
+			x1mth2 = 1.0 - cosisq
+			x7thm1 = 7.0*cosisq - 1.0
 		}
 
-		mrt = rl*(1.0-1.5*temp2*betal*satrec.con41) + 0.5*temp1*satrec.x1mth2*cos2u
-		su = su - 0.25*temp2*satrec.x7thm1*sin2u
+		mrt = rl*(1.0-1.5*temp2*betal*con41) + 0.5*temp1*x1mth2*cos2u
+		su = su - 0.25*temp2*x7thm1*sin2u
 		xnode = nodep + 1.5*temp2*cosip*sin2u
 		xinc = xincp + 1.5*temp2*cosip*sinip*cos2u
-		mvt := rdotl - nm*temp1*satrec.x1mth2*sin2u/xke
-		rvdot = rvdotl + nm*temp1*(satrec.x1mth2*cos2u+1.5*satrec.con41)/xke
+		mvt := rdotl - nm*temp1*x1mth2*sin2u/xke
+		rvdot = rvdotl + nm*temp1*(x1mth2*cos2u+1.5*con41)/xke
 
 		sinsu = math.Sin(su)
 		cossu = math.Cos(su)
@@ -524,7 +635,7 @@ func (satrec *Satellite) sgp4(tsince float64) (position, velocity Vector3, err e
 	}
 
 	if mrt < 1.0 {
-		err = errors.New("mrt is less than 1.0 indicating the satellite has decayed")
+		err = satrec.propagationError(6, tsince, mrt*radiusearthkm)
 	}
 
 	return
@@ -0,0 +1,110 @@
+package satellite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Writes an SP3-d file in GPS time for the satellite, moving every precise position
+// radially outward by offset km
+func sp3FromSatellite(sat *Satellite, start time.Time, epochs int, offset float64) string {
+	var b strings.Builder
+	gps := start.Add(gpsMinusUTC(start))
+	fmt.Fprintf(&b, "#dP%4d %2d %2d %2d %2d %11.8f %7d ORBIT IGS14 HLM  IGS\n",
+		gps.Year(), gps.Month(), gps.Day(), gps.Hour(), gps.Minute(), float64(gps.Second()), epochs)
+	b.WriteString("## 2106 172800.00000000   900.00000000 59000 0.0000000000000\n")
+	b.WriteString("+    2   G01G02  0  0  0  0  0  0  0  0  0  0  0  0  0  0  0\n")
+	b.WriteString("%c G  cc GPS ccc cccc cccc cccc cccc ccccc ccccc ccccc ccccc\n")
+	b.WriteString("/* synthetic test file\n")
+	for k := 0; k < epochs; k++ {
+		utc := start.Add(time.Duration(k) * 15 * time.Minute)
+		t := gps.Add(time.Duration(k) * 15 * time.Minute)
+		fmt.Fprintf(&b, "*  %4d %2d %2d %2d %2d %11.8f\n", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), float64(t.Second()))
+
+		pos, vel, err := sat.propagateAt(utc)
+		Expect(err).To(BeNil())
+		r := pos.Magnitude()
+		pos = Vector3{pos.X * (r + offset) / r, pos.Y * (r + offset) / r, pos.Z * (r + offset) / r}
+		ecf, ecfVel := ECIToECEFState(pos, vel, gmstAt(utc))
+		fmt.Fprintf(&b, "PG01%14.6f%14.6f%14.6f%14.6f\n", ecf.X, ecf.Y, ecf.Z, 12.345678)
+		fmt.Fprintf(&b, "VG01%14.6f%14.6f%14.6f%14.6f\n", ecfVel.X*1e4, ecfVel.Y*1e4, ecfVel.Z*1e4, 0.0)
+		fmt.Fprintf(&b, "PG02%14.6f%14.6f%14.6f%14.6f\n", 0.0, 0.0, 0.0, 999999.999999)
+	}
+	b.WriteString("EOF\n")
+	return b.String()
+}
+
+var _ = Describe("SP3", func() {
+	gps := func() Satellite {
+		sat, err := NewSatFromTLE(
+			"1 32711U 08012A   20144.00000000  .00000000  00000-0  00000-0 0  9990",
+			"2 32711  55.0000 100.0000 0050000  90.0000 270.0000  2.00560000 90000",
+			"wgs72")
+		Expect(err).To(BeNil())
+		return sat
+	}
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+
+	It("should read the header and records", func() {
+		sat := gps()
+		sp3, err := ReadSP3(strings.NewReader(sp3FromSatellite(&sat, start, 8, 0)))
+		Expect(err).To(BeNil())
+		Expect(sp3.Version).To(Equal(byte('d')))
+		Expect(sp3.TimeSystem).To(Equal("GPS"))
+		Expect(sp3.CoordinateSystem).To(Equal("IGS14"))
+		Expect(sp3.Agency).To(Equal("IGS"))
+		Expect(sp3.Epochs).To(HaveLen(8))
+		Expect(sp3.Epochs[0]).To(Equal(start.Add(18 * time.Second)))
+
+		// Satellite G02 has only missing positions
+		Expect(sp3.Satellites()).To(Equal([]string{"G01"}))
+		recs := sp3.Records("G01")
+		Expect(recs).To(HaveLen(8))
+		Expect(recs[0].Clock).To(Equal(12.345678))
+		Expect(recs[0].HasVelocity).To(BeTrue())
+		_, vel, err := sat.PropagateECEF(start)
+		Expect(err).To(BeNil())
+		Expect(distance(recs[0].Velocity, vel)).To(BeNumerically("<", 1e-9))
+	})
+
+	It("should report SGP4 differences in the orbit frame", func() {
+		sat := gps()
+		sp3, err := ReadSP3(strings.NewReader(sp3FromSatellite(&sat, start, 8, 1)))
+		Expect(err).To(BeNil())
+
+		cmp, err := sp3.Compare(&sat, "G01")
+		Expect(err).To(BeNil())
+		Expect(cmp.Samples).To(HaveLen(8))
+		Expect(cmp.Samples[0].Time).To(Equal(start))
+		for _, d := range cmp.Samples {
+			Expect(d.RIC.Radial).To(BeNumerically("~", -1, 1e-4))
+			Expect(d.RIC.InTrack).To(BeNumerically("~", 0, 1e-4))
+			Expect(d.RIC.CrossTrack).To(BeNumerically("~", 0, 1e-4))
+		}
+		Expect(cmp.RMS.Radial).To(BeNumerically("~", 1, 1e-4))
+		Expect(cmp.MaxDistance).To(BeNumerically("~", 1, 1e-4))
+
+		_, err = sp3.Compare(&sat, "G02")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should reject other formats", func() {
+		_, err := ReadSP3(strings.NewReader("#aP2020  5 23  0  0  0.00000000\n"))
+		Expect(err).NotTo(BeNil())
+		_, err = ReadSP3(strings.NewReader(""))
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("gpsMinusUTC", func() {
+	It("should follow the leap seconds", func() {
+		Expect(gpsMinusUTC(time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC))).To(Equal(time.Duration(0)))
+		Expect(gpsMinusUTC(time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC))).To(Equal(14 * time.Second))
+		Expect(gpsMinusUTC(time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC))).To(Equal(18 * time.Second))
+		Expect(gpsToUTC(time.Date(2017, 1, 1, 0, 0, 18, 0, time.UTC))).To(Equal(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+})
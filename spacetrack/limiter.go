@@ -0,0 +1,64 @@
+package spacetrack
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Largest number of requests within a sliding window
+type limit struct {
+	n   int
+	per time.Duration
+}
+
+// Sliding window request limiter remembering the times of recent requests
+type limiter struct {
+	mu   sync.Mutex
+	sent []time.Time
+}
+
+// Waits until one more request keeps within every limit and records it. Returns the context
+// error when the context ends first.
+func (l *limiter) wait(ctx context.Context, limits []limit) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		l.mu.Lock()
+		now := time.Now()
+		var delay time.Duration
+		longest := time.Duration(0)
+		for _, lim := range limits {
+			longest = max(longest, lim.per)
+			// Requests within the window of this limit, the sent times are in order
+			inWindow := 0
+			for i := len(l.sent) - 1; i >= 0 && now.Sub(l.sent[i]) < lim.per; i-- {
+				inWindow++
+			}
+			if inWindow >= lim.n {
+				oldest := l.sent[len(l.sent)-lim.n]
+				delay = max(delay, lim.per-now.Sub(oldest))
+			}
+		}
+		if delay <= 0 {
+			// Forget requests older than the longest window
+			keep := 0
+			for keep < len(l.sent) && now.Sub(l.sent[keep]) >= longest {
+				keep++
+			}
+			l.sent = append(l.sent[keep:], now)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package spacetrack queries general perturbations element sets from Space-Track.org into
+// satellites, keeping to the request limits of the Space-Track API.
+package spacetrack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+)
+
+// Default location of the Space-Track API
+const DefaultBaseURL = "https://www.space-track.org"
+
+// Request limits of the Space-Track API for one account
+const (
+	DefaultPerMinute = 30
+	DefaultPerHour   = 300
+)
+
+// Object type of a catalog entry
+type ObjectType string
+
+const (
+	Payload    ObjectType = "PAYLOAD"
+	RocketBody ObjectType = "ROCKET BODY"
+	Debris     ObjectType = "DEBRIS"
+	Unknown    ObjectType = "UNKNOWN"
+)
+
+// Selection of element sets. The zero value selects the latest element set of every object
+// in the catalog.
+type Query struct {
+	// Catalog numbers to select, all objects when empty
+	NoradIDs []int64
+
+	// Epoch range of the element sets, both ends inclusive. Setting either end queries the
+	// element set history instead of the latest element sets.
+	EpochStart, EpochStop time.Time
+
+	// Object type to select, all types when empty
+	ObjectType ObjectType
+
+	// Largest number of element sets to return, unlimited when zero
+	Limit int
+}
+
+// Escapes the characters of predicate values the API paths reject, keeping the commas of lists
+var pathEscaper = strings.NewReplacer(" ", "%20", ">", "%3E", "<", "%3C", "/", "%2F")
+
+// Returns the API path of the query, ordered by catalog number and epoch
+func (q Query) Path() string {
+	class := "gp"
+	if !q.EpochStart.IsZero() || !q.EpochStop.IsZero() {
+		class = "gp_history"
+	}
+	var b strings.Builder
+	b.WriteString("/basicspacedata/query/class/" + class)
+	predicate := func(key, value string) {
+		b.WriteString("/" + key + "/" + pathEscaper.Replace(value))
+	}
+	if len(q.NoradIDs) > 0 {
+		ids := make([]string, len(q.NoradIDs))
+		for i, id := range q.NoradIDs {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		predicate("NORAD_CAT_ID", strings.Join(ids, ","))
+	}
+	const layout = "2006-01-02T15:04:05"
+	switch {
+	case !q.EpochStart.IsZero() && !q.EpochStop.IsZero():
+		predicate("EPOCH", q.EpochStart.UTC().Format(layout)+"--"+q.EpochStop.UTC().Format(layout))
+	case !q.EpochStart.IsZero():
+		predicate("EPOCH", ">"+q.EpochStart.UTC().Format(layout))
+	case !q.EpochStop.IsZero():
+		predicate("EPOCH", "<"+q.EpochStop.UTC().Format(layout))
+	}
+	if q.ObjectType != "" {
+		predicate("OBJECT_TYPE", string(q.ObjectType))
+	}
+	predicate("orderby", "NORAD_CAT_ID,EPOCH")
+	if q.Limit > 0 {
+		predicate("limit", strconv.Itoa(q.Limit))
+	}
+	b.WriteString("/format/json")
+	return b.String()
+}
+
+// Client for Space-Track queries of one account. It logs in on the first query and again
+// when the session expires, and delays requests that would exceed the request limits.
+// A Client is safe for concurrent use.
+type Client struct {
+	// Account credentials
+	Identity, Password string
+
+	// HTTP client and API location, http.DefaultClient and DefaultBaseURL when not set
+	HTTPClient *http.Client
+	BaseURL    string
+
+	// Gravity model passed to satellite.NewSatFromGP, wgs72 when empty
+	Gravity satellite.GravModel
+
+	// Request limits, DefaultPerMinute and DefaultPerHour when zero
+	PerMinute, PerHour int
+
+	mu      sync.Mutex
+	cookies []*http.Cookie
+	limiter limiter
+}
+
+// Returns a client for the account
+func NewClient(identity, password string) *Client {
+	return &Client{Identity: identity, Password: password}
+}
+
+// Logs in and keeps the session cookie for the following queries
+func (c *Client) Login(ctx context.Context) error {
+	form := url.Values{"identity": {c.Identity}, "password": {c.Password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL()+"/ajaxauth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("spacetrack login: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	// Failed logins are answered with status 200 and a JSON error message
+	if resp.StatusCode != http.StatusOK || strings.Contains(string(body), "Failed") || len(resp.Cookies()) == 0 {
+		satellite.Logger().Warn("spacetrack login rejected", "identity", c.Identity, "status", resp.StatusCode)
+		return fmt.Errorf("spacetrack login: rejected with status %s", resp.Status)
+	}
+	c.mu.Lock()
+	c.cookies = resp.Cookies()
+	c.mu.Unlock()
+	satellite.Logger().Debug("spacetrack logged in", "identity", c.Identity)
+	return nil
+}
+
+// Ends the session
+func (c *Client) Logout(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL()+"/ajaxauth/logout", nil)
+	if err != nil {
+		return err
+	}
+	c.addCookies(req)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("spacetrack logout: %w", err)
+	}
+	resp.Body.Close()
+	c.mu.Lock()
+	c.cookies = nil
+	c.mu.Unlock()
+	return nil
+}
+
+// Runs a query and returns the initialized satellites. Element sets of ephemeris type 4,
+// which need SGP4-XP, are left out. Malformed element sets are reported as
+// *satellite.GPEntryError, joined with errors.Join, next to the satellites of the good ones.
+func (c *Client) GP(ctx context.Context, q Query) ([]satellite.Satellite, error) {
+	log := satellite.Logger().With("path", q.Path())
+	sats, err := c.gp(ctx, q)
+	if err != nil {
+		log.Warn("spacetrack query failed", "satellites", len(sats), "err", err)
+		return sats, fmt.Errorf("spacetrack query: %w", err)
+	}
+	log.Info("spacetrack query fetched", "satellites", len(sats))
+	return sats, nil
+}
+
+// Runs a query and initializes the element sets it returns
+func (c *Client) gp(ctx context.Context, q Query) ([]satellite.Satellite, error) {
+	body, err := c.get(ctx, q.Path())
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	gravity := c.Gravity
+	if gravity == "" {
+		gravity = satellite.WGS72
+	}
+	d := satellite.NewGPJSONDecoder(body, gravity)
+	var sats []satellite.Satellite
+	var errs []error
+	for {
+		sat, err := d.Next()
+		var entry *satellite.GPEntryError
+		switch {
+		case err == io.EOF:
+			return sats, errors.Join(errs...)
+		case errors.Is(err, satellite.ErrUnsupportedEphemeris):
+		case errors.As(err, &entry):
+			errs = append(errs, err)
+		case err != nil:
+			return sats, errors.Join(append(errs, err)...)
+		default:
+			sats = append(sats, sat)
+		}
+	}
+}
+
+// Runs a GET request of a query path in the session, logging in first when needed and once
+// more when the session has expired
+func (c *Client) get(ctx context.Context, path string) (io.ReadCloser, error) {
+	for attempt := 0; ; attempt++ {
+		c.mu.Lock()
+		loggedIn := c.cookies != nil
+		c.mu.Unlock()
+		if !loggedIn {
+			if err := c.Login(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL()+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.addCookies(req)
+		resp, err := c.do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		satellite.Logger().Debug("spacetrack response", "path", path, "status", resp.StatusCode)
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			// The session expired, log in again
+			resp.Body.Close()
+			c.mu.Lock()
+			c.cookies = nil
+			c.mu.Unlock()
+		case resp.StatusCode != http.StatusOK:
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+		default:
+			return resp.Body, nil
+		}
+	}
+}
+
+// Sends a request once the request limits allow it
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	perMinute, perHour := c.PerMinute, c.PerHour
+	if perMinute <= 0 {
+		perMinute = DefaultPerMinute
+	}
+	if perHour <= 0 {
+		perHour = DefaultPerHour
+	}
+	if err := c.limiter.wait(ctx, []limit{{perMinute, time.Minute}, {perHour, time.Hour}}); err != nil {
+		return nil, err
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func (c *Client) addCookies(req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
@@ -0,0 +1,13 @@
+package spacetrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSpacetrack(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Spacetrack Suite")
+}
@@ -0,0 +1,136 @@
+package spacetrack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	satellite "github.com/mpielikis/go-satellite"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const gpJSON = `[{"OBJECT_NAME":"ISS (ZARYA)","OBJECT_ID":"1998-067A","EPOCH":"2008-09-20T12:25:40.104192",
+"MEAN_MOTION":"15.72125391","ECCENTRICITY":"0.0006703","INCLINATION":"51.6416","RA_OF_ASC_NODE":"247.4627",
+"ARG_OF_PERICENTER":"130.5360","MEAN_ANOMALY":"325.0288","EPHEMERIS_TYPE":"0","CLASSIFICATION_TYPE":"U",
+"NORAD_CAT_ID":"25544","ELEMENT_SET_NO":"292","REV_AT_EPOCH":"56353","BSTAR":"-0.11606E-4",
+"MEAN_MOTION_DOT":"-0.2182E-4","MEAN_MOTION_DDOT":"0","DECAY_DATE":null},
+{"OBJECT_NAME":"ISS (ZARYA)","OBJECT_ID":"1998-067A","EPOCH":"2008-09-20T12:25:40.104192",
+"MEAN_MOTION":"15.72125391","ECCENTRICITY":"0.0006703","INCLINATION":"51.6416","RA_OF_ASC_NODE":"247.4627",
+"ARG_OF_PERICENTER":"130.5360","MEAN_ANOMALY":"325.0288","EPHEMERIS_TYPE":"4","CLASSIFICATION_TYPE":"U",
+"NORAD_CAT_ID":"25544","ELEMENT_SET_NO":"293","REV_AT_EPOCH":"56353","BSTAR":"-0.11606E-4",
+"MEAN_MOTION_DOT":"-0.2182E-4","MEAN_MOTION_DDOT":"0"}]`
+
+var _ = Describe("Client", func() {
+	var server *httptest.Server
+	var logins int
+	var paths []string
+	var expire bool
+	var body string
+
+	BeforeEach(func() {
+		logins, paths, expire, body = 0, nil, false, gpJSON
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/ajaxauth/login":
+				logins++
+				if r.FormValue("identity") != "user@example.com" || r.FormValue("password") != "secret" {
+					w.Write([]byte(`{"Login":"Failed"}`))
+					return
+				}
+				http.SetCookie(w, &http.Cookie{Name: "chocolatechip", Value: "session"})
+				w.Write([]byte(`""`))
+			default:
+				if c, err := r.Cookie("chocolatechip"); err != nil || c.Value != "session" || expire {
+					expire = false
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				paths = append(paths, r.URL.EscapedPath())
+				w.Write([]byte(body))
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should log in and decode the element sets", func() {
+		client := NewClient("user@example.com", "secret")
+		client.BaseURL = server.URL
+		sats, err := client.GP(context.Background(), Query{NoradIDs: []int64{25544, 33591}, ObjectType: Payload, Limit: 5})
+		Expect(err).To(BeNil())
+		Expect(logins).To(Equal(1))
+		Expect(paths).To(Equal([]string{"/basicspacedata/query/class/gp/NORAD_CAT_ID/25544,33591/OBJECT_TYPE/PAYLOAD/orderby/NORAD_CAT_ID,EPOCH/limit/5/format/json"}))
+		Expect(sats).To(HaveLen(1))
+		Expect(sats[0].Satnum).To(Equal(int64(25544)))
+		Expect(sats[0].Name).To(Equal("ISS (ZARYA)"))
+
+		_, err = client.GP(context.Background(), Query{})
+		Expect(err).To(BeNil())
+		Expect(logins).To(Equal(1))
+	})
+
+	It("should log in again when the session expires", func() {
+		client := NewClient("user@example.com", "secret")
+		client.BaseURL = server.URL
+		_, err := client.GP(context.Background(), Query{})
+		Expect(err).To(BeNil())
+		expire = true
+		_, err = client.GP(context.Background(), Query{})
+		Expect(err).To(BeNil())
+		Expect(logins).To(Equal(2))
+		Expect(paths).To(HaveLen(2))
+	})
+
+	It("should keep the good element sets next to malformed ones", func() {
+		bad := strings.Replace(gpJSON[1:strings.Index(gpJSON, "},")+1], `"INCLINATION":"51.6416"`, `"INCLINATION":"x"`, 1)
+		body = "[" + bad + "," + gpJSON[1:]
+		client := NewClient("user@example.com", "secret")
+		client.BaseURL = server.URL
+		sats, err := client.GP(context.Background(), Query{})
+		Expect(sats).To(HaveLen(1))
+		Expect(sats[0].Satnum).To(Equal(int64(25544)))
+
+		var entry *satellite.GPEntryError
+		Expect(errors.As(err, &entry)).To(BeTrue())
+		Expect(entry.Record).To(Equal(1))
+		Expect(err).To(MatchError(ContainSubstring("Error on parsing INCLINATION")))
+	})
+
+	It("should report rejected logins", func() {
+		client := NewClient("user@example.com", "wrong")
+		client.BaseURL = server.URL
+		_, err := client.GP(context.Background(), Query{})
+		Expect(err).To(MatchError(ContainSubstring("spacetrack login: rejected")))
+	})
+})
+
+var _ = Describe("Query", func() {
+	It("should query the history for epoch ranges", func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		q := Query{NoradIDs: []int64{25544}, EpochStart: start, EpochStop: start.Add(48 * time.Hour), ObjectType: RocketBody}
+		Expect(q.Path()).To(Equal("/basicspacedata/query/class/gp_history/NORAD_CAT_ID/25544/EPOCH/2024-01-01T00:00:00--2024-01-03T00:00:00/OBJECT_TYPE/ROCKET%20BODY/orderby/NORAD_CAT_ID,EPOCH/format/json"))
+		Expect(Query{EpochStart: start}.Path()).To(ContainSubstring("/EPOCH/%3E2024-01-01T00:00:00/"))
+	})
+})
+
+var _ = Describe("limiter", func() {
+	It("should delay requests beyond the limit of a window", func() {
+		var l limiter
+		limits := []limit{{2, 150 * time.Millisecond}, {10, time.Hour}}
+		begin := time.Now()
+		for i := 0; i < 3; i++ {
+			Expect(l.wait(context.Background(), limits)).To(Succeed())
+		}
+		Expect(time.Since(begin)).To(BeNumerically(">=", 150*time.Millisecond))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(l.wait(ctx, limits)).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,116 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Margin in radians added to ground track bounds to cover short period perturbations and
+// the geodetic observer latitude
+const groundTrackMargin = 1 * DEG2RAD
+
+// Finds every pass of every catalog member over the observer between start and stop using
+// all CPU cores, merged into one timeline ordered by AOS. Members whose ground track never
+// comes close enough to the observer latitude to rise above the minimum elevation are skipped
+// without propagation, as are members that fail to propagate. On cancellation the passes
+// found so far are returned with the context error.
+func (c *Catalog) Passes(ctx context.Context, obs LatLongAlt, start, stop time.Time, opts PassOptions) (passes []Pass, err error) {
+	if !stop.After(start) {
+		return nil, errors.New("pass search stop time must be after start time")
+	}
+	sats := c.Satellites()
+	ctx, span := startSpan(ctx, "satellite.Catalog.Passes",
+		slog.Int("satellite.count", len(sats)),
+		slog.Float64("window.minutes", stop.Sub(start).Minutes()))
+	defer func() { span.End(err) }()
+	log := Logger().With("job", "passes")
+
+	var candidates []int
+	for i := range sats {
+		if sats[i].canRiseOver(obs, opts) {
+			candidates = append(candidates, i)
+		}
+	}
+	span.SetAttributes(slog.Int("candidate.count", len(candidates)))
+
+	// The progress callback counts catalog members, the per member searches report nothing
+	var mu sync.Mutex
+	progress := newProgressReporter(opts.Progress, len(candidates))
+	memberOpts := opts
+	memberOpts.Progress = nil
+
+	found := make([][]Pass, len(candidates))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(candidates)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range next {
+				sat := &sats[candidates[k]]
+				p, err := PredictPassesContext(ctx, sat, obs, start, stop, memberOpts)
+				if err != nil && ctx.Err() == nil {
+					log.Debug("pass search failed", "satnum", sat.Satnum, "err", err)
+					p = nil
+				}
+				found[k] = p
+				mu.Lock()
+				progress.add(1, p)
+				mu.Unlock()
+			}
+		}()
+	}
+	for k := range candidates {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		next <- k
+	}
+	close(next)
+	wg.Wait()
+
+	for _, p := range found {
+		passes = append(passes, p...)
+	}
+	sort.Slice(passes, func(i, j int) bool {
+		if !passes[i].AOS.Equal(passes[j].AOS) {
+			return passes[i].AOS.Before(passes[j].AOS)
+		}
+		return passes[i].Satnum < passes[j].Satnum
+	})
+	log.Info("pass search finished", "satellites", len(sats), "candidates", len(candidates), "passes", len(passes))
+	return passes, err
+}
+
+// Reports whether the orbit geometry lets the satellite rise above the minimum elevation of
+// the observer: the highest ground track latitude widened by the footprint at apogee must
+// reach the observer latitude
+func (sat *Satellite) canRiseOver(obs LatLongAlt, opts PassOptions) bool {
+	footprint, ok := sat.accessFootprint(observerMinElevation(opts.MinElevation, obs, opts.HorizonDip, sat.Gravity))
+	if !ok {
+		return false
+	}
+
+	maxLat := sat.inclo
+	if maxLat > math.Pi/2 {
+		maxLat = math.Pi - maxLat
+	}
+	return math.Abs(obs.LatLong.Latitude) <= maxLat+footprint+groundTrackMargin
+}
+
+// Returns the largest earth central angle between the sub-satellite point and a ground point
+// seeing the satellite above minEl, reached at apogee. Ok is false for orbits inside the Earth.
+func (sat *Satellite) accessFootprint(minEl float64) (angle float64, ok bool) {
+	radius := sat.Gravity.radiusearthkm
+	_, apogee := sat.apsisRadii()
+	if apogee <= radius {
+		return 0, false
+	}
+	return math.Acos(radius/apogee*math.Cos(minEl)) - minEl, true
+}
@@ -0,0 +1,163 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TAI minus UTC when leap seconds began in 1972
+const taiMinusUTC1972 = 10 * time.Second
+
+// TT minus TAI
+const ttMinusTAI = 32184 * time.Millisecond
+
+// UTC dates from which TAI ran one more second ahead of UTC, extended by AddLeapSecond
+var leapSeconds = struct {
+	sync.RWMutex
+	dates []time.Time
+}{dates: []time.Time{
+	time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1973, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1974, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1977, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1978, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1979, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1981, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1982, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1983, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1985, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1988, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1992, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1993, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1994, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1996, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1997, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2012, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+}}
+
+// Adds a leap second announced by the IERS after this package was built. The date is the
+// midnight UTC that follows the inserted second, for example 2017-01-01 for the leap second
+// at the end of 2016. Adding a known date has no effect.
+func AddLeapSecond(date time.Time) error {
+	date = date.UTC()
+	if !date.Equal(date.Truncate(24 * time.Hour)) {
+		return errors.New("leap second date must be a midnight UTC")
+	}
+	leapSeconds.Lock()
+	defer leapSeconds.Unlock()
+	dates := leapSeconds.dates
+	i := sort.Search(len(dates), func(i int) bool { return !dates[i].Before(date) })
+	if i < len(dates) && dates[i].Equal(date) {
+		return nil
+	}
+	dates = append(dates[:i:i], append([]time.Time{date}, dates[i:]...)...)
+	leapSeconds.dates = dates
+	return nil
+}
+
+// Returns the UTC dates from which TAI ran one more second ahead of UTC
+func LeapSeconds() []time.Time {
+	leapSeconds.RLock()
+	defer leapSeconds.RUnlock()
+	return append([]time.Time(nil), leapSeconds.dates...)
+}
+
+// Returns TAI minus UTC at a UTC time. Times before 1972, when UTC was not yet kept in whole
+// seconds from TAI, get the 10 seconds of 1972.
+func TAIMinusUTC(utc time.Time) time.Duration {
+	leapSeconds.RLock()
+	defer leapSeconds.RUnlock()
+	dates := leapSeconds.dates
+	n := sort.Search(len(dates), func(i int) bool { return utc.Before(dates[i]) })
+	return taiMinusUTC1972 + time.Duration(n)*time.Second
+}
+
+// Returns GPS time minus UTC at a UTC time. GPS time runs 19 seconds behind TAI.
+func gpsMinusUTC(utc time.Time) time.Duration {
+	return TAIMinusUTC(utc) - 19*time.Second
+}
+
+// Converts a GPS time, carried in a time.Time with the UTC location, into UTC
+func gpsToUTC(gps time.Time) time.Time {
+	utc := gps.Add(-gpsMinusUTC(gps))
+	// Correct the estimate when a leap second lies between the two readings
+	return gps.Add(-gpsMinusUTC(utc))
+}
+
+// Returns Terrestrial Time minus UTC at a UTC time
+func ttMinusUTC(utc time.Time) time.Duration {
+	return TAIMinusUTC(utc) + ttMinusTAI
+}
+
+// Moment carried in UTC from which the other time scales are derived
+type Instant struct {
+	UTC time.Time
+
+	// UT1 minus UTC in seconds
+	DUT1 float64
+}
+
+// Returns the instant at a UTC time taking UT1 as UTC, see EOPTable.Instant for UT1 from
+// Earth orientation parameters
+func NewInstant(utc time.Time) Instant {
+	return Instant{UTC: utc.UTC()}
+}
+
+// Returns International Atomic Time carried in a time.Time with the UTC location
+func (i Instant) TAI() time.Time {
+	return i.UTC.Add(TAIMinusUTC(i.UTC))
+}
+
+// Returns Terrestrial Time carried in a time.Time with the UTC location
+func (i Instant) TT() time.Time {
+	return i.UTC.Add(ttMinusUTC(i.UTC))
+}
+
+// Returns UT1 carried in a time.Time with the UTC location
+func (i Instant) UT1() time.Time {
+	return i.UTC.Add(time.Duration(i.DUT1 * float64(time.Second)))
+}
+
+// Returns the julian date in UTC
+func (i Instant) JDayUTC() JDay {
+	return NewJDayFromTime(i.UTC)
+}
+
+// Returns the julian date in UT1, the argument of the sidereal time
+func (i Instant) JDayUT1() JDay {
+	return NewJDayFromTime(i.UT1())
+}
+
+// Returns the julian date in TT, the argument of precession and nutation
+func (i Instant) JDayTT() JDay {
+	return NewJDayFromTime(i.TT())
+}
+
+// Returns the Julian centuries of TT since J2000.0
+func (i Instant) CenturiesTT() float64 {
+	jd := i.JDayTT()
+	return ((jd.Day - 2451545.0) + jd.Fraction) / 36525.0
+}
+
+// Returns the greenwich mean sidereal time (IAU-82) in radians
+func (i Instant) GMST() float64 {
+	return gstime(i.JDayUT1())
+}
+
+// Returns the greenwich apparent sidereal time in radians, see GAST
+func (i Instant) GAST() float64 {
+	return math.Mod(i.GMST()+equationOfEquinoxes(i.CenturiesTT())+TWOPI, TWOPI)
+}
@@ -0,0 +1,79 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TAIMinusUTC", func() {
+	It("should follow the leap seconds", func() {
+		Expect(TAIMinusUTC(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))).To(Equal(10 * time.Second))
+		Expect(TAIMinusUTC(time.Date(2004, 4, 6, 0, 0, 0, 0, time.UTC))).To(Equal(32 * time.Second))
+		Expect(TAIMinusUTC(time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC))).To(Equal(36 * time.Second))
+		Expect(TAIMinusUTC(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))).To(Equal(37 * time.Second))
+	})
+})
+
+var _ = Describe("AddLeapSecond", func() {
+	var saved []time.Time
+	BeforeEach(func() {
+		saved = LeapSeconds()
+	})
+	AfterEach(func() {
+		leapSeconds.Lock()
+		leapSeconds.dates = saved
+		leapSeconds.Unlock()
+	})
+
+	It("should extend the table", func() {
+		date := time.Date(2035, 7, 1, 0, 0, 0, 0, time.UTC)
+		Expect(AddLeapSecond(date)).To(Succeed())
+		Expect(AddLeapSecond(date)).To(Succeed())
+		Expect(LeapSeconds()).To(HaveLen(len(saved) + 1))
+		Expect(TAIMinusUTC(date.Add(-time.Second))).To(Equal(37 * time.Second))
+		Expect(TAIMinusUTC(date)).To(Equal(38 * time.Second))
+	})
+
+	It("should reject dates within a day", func() {
+		Expect(AddLeapSecond(time.Date(2035, 7, 1, 12, 0, 0, 0, time.UTC))).NotTo(Succeed())
+		Expect(LeapSeconds()).To(Equal(saved))
+	})
+})
+
+var _ = Describe("Instant", func() {
+	utc := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+
+	It("should derive the time scales from UTC", func() {
+		i := Instant{UTC: utc, DUT1: -0.4399619}
+		Expect(i.TAI().Sub(utc)).To(Equal(32 * time.Second))
+		Expect(i.TT().Sub(utc)).To(Equal(64184 * time.Millisecond))
+		Expect(i.UT1().Sub(utc)).To(Equal(-439961900 * time.Nanosecond))
+
+		// Vallado et al. (2006), Revisiting Spacetrack Report #3, appendix C
+		Expect(i.CenturiesTT()).To(BeNumerically("~", 0.0426236319, 1e-10))
+		jd := i.JDayUT1()
+		Expect(jd.Day + jd.Fraction).To(BeNumerically("~", 2453101.827406783, 1e-8))
+	})
+
+	It("should take UT1 from the EOP table", func() {
+		table, err := ReadFinals2000A(strings.NewReader(finals2000A))
+		Expect(err).To(BeNil())
+		t := time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC)
+		Expect(NewInstant(t).DUT1).To(BeZero())
+		Expect(NewInstant(t).GMST()).To(Equal(gmstAt(t)))
+
+		i, err := table.Instant(t)
+		Expect(err).To(BeNil())
+		Expect(i.DUT1).To(Equal(-0.4085841))
+		diff := math.Remainder(i.GMST()-gmstAt(t), TWOPI)
+		Expect(diff).To(BeNumerically("~", -0.4085841*1.00273790935*TWOPI/86400, 1e-10))
+
+		_, err = table.Instant(t.AddDate(1, 0, 0))
+		Expect(errors.Is(err, ErrOutOfRange)).To(BeTrue())
+	})
+})
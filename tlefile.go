@@ -0,0 +1,100 @@
+package satellite
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Failure of one element set of a TLE file
+type TLEEntryError struct {
+	// Line the element set starts on, counted from 1
+	Line int
+
+	// Name line of a three line element set, empty otherwise
+	Name string
+
+	Err error
+}
+
+func (e *TLEEntryError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("element set %q on line %d: %v", e.Name, e.Line, e.Err)
+	}
+	return fmt.Sprintf("element set on line %d: %v", e.Line, e.Err)
+}
+
+func (e *TLEEntryError) Unwrap() error {
+	return e.Err
+}
+
+// Reads a file of two or three line element sets and returns the initialized satellites.
+// Blank lines and comment lines starting with # are skipped; any other line that is not a
+// TLE line is taken as the name of the element set that follows. Malformed entries do not
+// stop the reading: each is reported as a *TLEEntryError, joined with errors.Join, next to
+// the satellites of the good ones.
+func ParseTLEs(r io.Reader, gravconst GravModel) ([]Satellite, error) {
+	return ParseTLEsWithOptions(r, gravconst, TLEOptions{})
+}
+
+// Reads a file of two or three line element sets like ParseTLEs, reading the epoch years
+// with the given options
+func ParseTLEsWithOptions(r io.Reader, gravconst GravModel, opts TLEOptions) ([]Satellite, error) {
+	var sats []Satellite
+	var errs []error
+	var name, line1 string
+	nameLine, start, n := 0, 0, 0
+	fail := func(line int, err error) {
+		errs = append(errs, &TLEEntryError{Line: line, Name: tleName(name), Err: err})
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		n++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "1 "):
+			if line1 != "" {
+				fail(start, errors.New("line 1 is not followed by line 2"))
+				name = ""
+			}
+			start, line1 = n, line
+			if name != "" {
+				start = nameLine
+			}
+		case strings.HasPrefix(line, "2 "):
+			if line1 == "" {
+				fail(n, errors.New("line 2 is not preceded by line 1"))
+				name = ""
+				continue
+			}
+			sat, err := NewSatFromTLEWithOptions(line1, line, gravconst, opts)
+			sat.Name = tleName(name)
+			if err == nil && strings.TrimSpace(line1[2:7]) != strings.TrimSpace(line[2:7]) {
+				err = errors.New("catalog numbers of line 1 and line 2 differ")
+			}
+			if err != nil {
+				fail(start, err)
+			} else {
+				sats = append(sats, sat)
+			}
+			name, line1 = "", ""
+		default:
+			if line1 != "" {
+				fail(start, errors.New("line 1 is not followed by line 2"))
+				line1 = ""
+			}
+			name, nameLine = line, n
+		}
+	}
+	if line1 != "" {
+		fail(start, errors.New("line 1 is not followed by line 2"))
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return sats, errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package satellite
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const tleFile = `# stations
+ISS (ZARYA)
+1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927
+2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537
+
+1 33591U 09005A   08264.48990228  .00000077  00000-0  66998-4 0  9990
+2 33591  99.0394 120.2160 0013054 232.8317 127.1662 14.12079902378332
+`
+
+var _ = Describe("ParseTLEs", func() {
+	It("should read two and three line element sets", func() {
+		sats, err := ParseTLEs(strings.NewReader(strings.ReplaceAll(tleFile, "\n", "\r\n")), "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sats).To(HaveLen(2))
+		Expect(sats[0].Name).To(Equal("ISS (ZARYA)"))
+		Expect(sats[0].Satnum).To(Equal(int64(25544)))
+		Expect(sats[1].Name).To(BeEmpty())
+		Expect(sats[1].Satnum).To(Equal(int64(33591)))
+	})
+
+	It("should report malformed entries and keep the good ones", func() {
+		lines := strings.Split(tleFile, "\n")
+		doc := strings.Join([]string{
+			"BROKEN",
+			lines[2][:60],
+			lines[3],
+			lines[4],
+			"ORPHAN",
+			lines[2],
+			lines[5],
+			lines[6],
+			lines[3],
+		}, "\n")
+		sats, err := ParseTLEs(strings.NewReader(doc), "wgs72")
+		Expect(sats).To(HaveLen(1))
+		Expect(sats[0].Satnum).To(Equal(int64(33591)))
+
+		var entry *TLEEntryError
+		Expect(errors.As(err, &entry)).To(BeTrue())
+		Expect(entry.Line).To(Equal(1))
+		Expect(entry.Name).To(Equal("BROKEN"))
+		Expect(errors.Is(err, ErrBadLineLength)).To(BeTrue())
+
+		errs := err.(interface{ Unwrap() []error }).Unwrap()
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[1].Error()).To(Equal(`element set "ORPHAN" on line 5: line 1 is not followed by line 2`))
+		Expect(errs[2].Error()).To(Equal("element set on line 9: line 2 is not preceded by line 1"))
+	})
+
+	It("should report line pairs of different objects", func() {
+		lines := strings.Split(tleFile, "\n")
+		_, err := ParseTLEs(strings.NewReader(lines[2]+"\n"+lines[6]+"\n"), "wgs72")
+		Expect(err).To(MatchError("element set on line 1: catalog numbers of line 1 and line 2 differ"))
+	})
+})
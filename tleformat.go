@@ -0,0 +1,149 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats a general perturbations element set as checksummed TLE lines, the inverse of
+// ParseTLE. Catalog numbers from 100000 on are written in the Alpha-5 scheme. Angles are
+// normalized to 0 to 360 deg; the element set number and revolution number keep their
+// last four and five digits. An empty classification is written as U.
+func FormatTLE(gp GPElements) (line1, line2 string, err error) {
+	satnum, err := FormatAlpha5(gp.NoradCatID)
+	if err != nil {
+		return "", "", err
+	}
+	if gp.Eccentricity < 0 || gp.Eccentricity >= 1 {
+		return "", "", fmt.Errorf("eccentricity %g does not fit a TLE", gp.Eccentricity)
+	}
+	if gp.MeanMotion <= 0 || gp.MeanMotion >= 100 {
+		return "", "", fmt.Errorf("mean motion %g does not fit a TLE", gp.MeanMotion)
+	}
+	ndot, err := tleDecimal(gp.MeanMotionDot)
+	if err != nil {
+		return "", "", fmt.Errorf("mean motion derivative: %w", err)
+	}
+	nddot, err := tleExponential(gp.MeanMotionDDot)
+	if err != nil {
+		return "", "", fmt.Errorf("mean motion second derivative: %w", err)
+	}
+	bstar, err := tleExponential(gp.Bstar)
+	if err != nil {
+		return "", "", fmt.Errorf("BSTAR: %w", err)
+	}
+
+	class := gp.ClassificationType
+	if class == "" {
+		class = "U"
+	}
+	epoch := gp.Epoch.UTC()
+	days := float64(epoch.YearDay()) + epoch.Sub(epoch.Truncate(24*time.Hour)).Hours()/24
+
+	line1 = fmt.Sprintf("1 %s%.1s %-8.8s %02d%012.8f %s %s %s %d %4d",
+		satnum, class, tleDesignator(gp.ObjectID), epoch.Year()%100, days,
+		ndot, nddot, bstar, gp.EphemerisType%10, gp.ElementSetNo%10000)
+	line2 = fmt.Sprintf("2 %s %8.4f %8.4f %07d %8.4f %8.4f %11.8f%5d",
+		satnum, tleAngle(gp.Inclination), tleAngle(gp.RAOfAscNode),
+		int64(math.Round(gp.Eccentricity*1e7)), tleAngle(gp.ArgOfPericenter), tleAngle(gp.MeanAnomaly),
+		gp.MeanMotion, gp.RevAtEpoch%100000)
+	line1 += strconv.Itoa(TLEChecksum(line1))
+	line2 += strconv.Itoa(TLEChecksum(line2))
+	return line1, line2, nil
+}
+
+// Returns TLE lines describing the current mean elements and metadata of the satellite,
+// which differ from Line1 and Line2 after the elements have been adjusted
+func (sat *Satellite) TLE() (line1, line2 string, err error) {
+	return FormatTLE(sat.gpElements())
+}
+
+// Returns the general perturbations element set of the satellite
+func (sat *Satellite) gpElements() GPElements {
+	el := sat.Elements()
+	gp := GPElements{
+		ObjectName:         sat.Name,
+		ObjectID:           sat.designator,
+		NoradCatID:         sat.Satnum,
+		EphemerisType:      sat.ephemerisType,
+		ClassificationType: sat.classification,
+		ElementSetNo:       sat.elementSetNo,
+		RevAtEpoch:         sat.revNumber,
+		Epoch:              el.Epoch,
+		MeanMotion:         el.MeanMotion,
+		Eccentricity:       el.Eccentricity,
+		Inclination:        el.Inclination,
+		RAOfAscNode:        el.RAAN,
+		ArgOfPericenter:    el.ArgOfPericenter,
+		MeanAnomaly:        el.MeanAnomaly,
+		Source:             sat.source,
+		Bstar:              el.Bstar,
+		MeanMotionDot:      el.MeanMotionDot,
+		MeanMotionDDot:     el.MeanMotionDDot,
+	}
+	return gp
+}
+
+// Returns the TLE form of an international designator such as 1998-067A, which is 98067A
+func tleDesignator(id string) string {
+	id = strings.TrimSpace(id)
+	if len(id) > 5 && id[4] == '-' {
+		return id[2:4] + id[5:]
+	}
+	return id
+}
+
+// Returns an angle in degrees normalized to 0 to 360
+func tleAngle(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	// Values rounding up to 360.0000 in the field wrap to zero
+	if math.Round(deg*1e4) >= 360e4 {
+		deg = 0
+	}
+	return deg
+}
+
+// Formats a value below one as the ten column signed decimal of the mean motion derivative,
+// such as -.00002182
+func tleDecimal(v float64) (string, error) {
+	s := strconv.FormatFloat(math.Abs(v), 'f', 8, 64)
+	if !strings.HasPrefix(s, "0.") {
+		return "", fmt.Errorf("%g does not fit a TLE", v)
+	}
+	if v < 0 && s != "0.00000000" {
+		return "-" + s[1:], nil
+	}
+	return " " + s[1:], nil
+}
+
+// Formats a value in the eight column TLE exponential notation with an implied leading
+// decimal point, such as -11606-4 for -0.11606e-4
+func tleExponential(v float64) (string, error) {
+	sign := " "
+	if v < 0 {
+		sign = "-"
+	}
+	a := math.Abs(v)
+	if a == 0 {
+		return " 00000-0", nil
+	}
+	exp := int(math.Floor(math.Log10(a))) + 1
+	mantissa := int64(math.Round(a / math.Pow(10, float64(exp)) * 1e5))
+	if mantissa >= 100000 {
+		mantissa /= 10
+		exp++
+	}
+	if exp < -9 {
+		return " 00000-0", nil
+	}
+	if exp > 9 {
+		return "", fmt.Errorf("%g does not fit a TLE", v)
+	}
+	return fmt.Sprintf("%s%05d%+d", sign, mantissa, exp), nil
+}
@@ -0,0 +1,87 @@
+package satellite
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FormatTLE", func() {
+	It("should reproduce parsed TLE lines", func() {
+		for _, lines := range [][2]string{
+			{"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+				"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"},
+			{"1 33591U 09005A   08264.48990228  .00000077  00000-0  66998-4 0  9990",
+				"2 33591  99.0394 120.2160 0013054 232.8317 127.1662 14.12079902378332"},
+			{"1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600",
+				"2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119"},
+		} {
+			sat, err := NewSatFromTLE(lines[0], lines[1], "wgs72")
+			Expect(err).To(BeNil())
+			line1, line2, err := sat.TLE()
+			Expect(err).To(BeNil())
+			// The checksums of the test element sets are not all valid
+			Expect(line1[:68]).To(Equal(lines[0][:68]))
+			Expect(line2[:68]).To(Equal(lines[1][:68]))
+			_, err = ParseTLEStrict(line1, line2)
+			Expect(err).To(BeNil())
+		}
+	})
+
+	It("should format element sets without TLE lines", func() {
+		gp := GPElements{
+			ObjectID: "2019-074A", NoradCatID: 44713, Epoch: time.Date(2021, 1, 1, 6, 0, 0, 0, time.UTC),
+			MeanMotion: 15.06391, Eccentricity: .0001362, Inclination: 53.0536, RAOfAscNode: -112.5,
+			ArgOfPericenter: 85.1376, MeanAnomaly: 274.9826, ElementSetNo: 999, RevAtEpoch: 123456,
+			Bstar: 0.00012, MeanMotionDot: 0.00001, MeanMotionDDot: 1.5e-9,
+		}
+		line1, line2, err := FormatTLE(gp)
+		Expect(err).To(BeNil())
+		Expect(line1).To(Equal("1 44713U 19074A   21001.25000000  .00001000  15000-8  12000-3 0  9992"))
+		Expect(line2).To(Equal("2 44713  53.0536 247.5000 0001362  85.1376 274.9826 15.06391000234566"))
+		Expect(TLEChecksum(line1)).To(Equal(int(line1[68] - '0')))
+
+		sat, err := ParseTLEStrict(line1, line2)
+		Expect(err).To(BeNil())
+		Expect(sat.Satnum).To(Equal(int64(44713)))
+	})
+
+	It("should refuse values the TLE fields cannot hold", func() {
+		_, _, err := FormatTLE(GPElements{NoradCatID: 340000, MeanMotion: 15})
+		Expect(err).To(MatchError("catalog number 340000 does not fit a TLE"))
+		_, _, err = FormatTLE(GPElements{NoradCatID: 1, MeanMotion: 15, MeanMotionDot: 1.5})
+		Expect(err).To(MatchError("mean motion derivative: 1.5 does not fit a TLE"))
+	})
+
+	It("should round trip Alpha-5 catalog numbers", func() {
+		line1 := "1 E8493U 22001A   22001.50000000  .00001000  00000-0  12000-3 0  9992"
+		line2 := "2 E8493  53.0536 247.5000 0001362  85.1376 274.9826 15.06391000  1236"
+		line1 = line1[:68] + fmt.Sprint(TLEChecksum(line1))
+		line2 = line2[:68] + fmt.Sprint(TLEChecksum(line2))
+		sat, err := NewSatFromTLE(line1, line2, "wgs72")
+		Expect(err).To(BeNil())
+		Expect(sat.Satnum).To(Equal(int64(148493)))
+
+		out1, out2, err := sat.TLE()
+		Expect(err).To(BeNil())
+		Expect(out1).To(Equal(line1))
+		Expect(out2).To(Equal(line2))
+	})
+
+	It("should decode and encode Alpha-5 catalog numbers", func() {
+		for s, n := range map[string]int64{"25544": 25544, "A0000": 100000, "E8493": 148493, "J0001": 180001, "Z9999": 339999} {
+			got, err := ParseAlpha5(s)
+			Expect(err).To(BeNil())
+			Expect(got).To(Equal(n))
+			str, err := FormatAlpha5(n)
+			Expect(err).To(BeNil())
+			Expect(str).To(Equal(s))
+		}
+		_, err := ParseAlpha5("I0001")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseAlpha5("E84")
+		Expect(err).To(MatchError(`bad Alpha-5 catalog number "E84"`))
+	})
+})
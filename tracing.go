@@ -0,0 +1,67 @@
+package satellite
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Receives spans around batch propagation, pass searches and screening. The package
+// depends on no tracing library; the otelsat module adapts an OpenTelemetry tracer
+// provider to this interface.
+type Tracer interface {
+	// Starts a span as a child of any span in ctx and returns the context carrying it
+	Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+}
+
+// Span started by a Tracer
+type Span interface {
+	SetAttributes(attrs ...slog.Attr)
+
+	// Ends the span, marking it failed when err is not nil
+	End(err error)
+}
+
+type tracerHolder struct {
+	t Tracer
+}
+
+var pkgTracer atomic.Pointer[tracerHolder]
+
+// Sets the tracer receiving spans around batch propagation, pass searches and screening.
+// Tracing is disabled until a tracer is set; passing nil disables it again.
+func SetTracer(t Tracer) {
+	if t == nil {
+		pkgTracer.Store(nil)
+		return
+	}
+	pkgTracer.Store(&tracerHolder{t})
+}
+
+// Starts a span with the given attributes as a child of any span in ctx
+func startSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if h := pkgTracer.Load(); h != nil {
+		return h.t.Start(ctx, name, attrs...)
+	}
+	return ctx, noopSpan{}
+}
+
+// Span recording nothing, used while no tracer is set
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+// Returns the span attributes of a search or propagation of one satellite between start and stop
+func satelliteWindowAttributes(satnum int64, start, stop time.Time) []slog.Attr {
+	return []slog.Attr{
+		slog.Int64("satellite.satnum", satnum),
+		slog.String("window.start", start.UTC().Format(time.RFC3339)),
+		slog.String("window.stop", stop.UTC().Format(time.RFC3339)),
+		slog.Float64("window.minutes", stop.Sub(start).Minutes()),
+	}
+}
@@ -0,0 +1,134 @@
+package satellite
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Span kept by recordingTracer
+type recordedSpan struct {
+	name, parent string
+	attrs        map[string]slog.Value
+	err          error
+	ended        bool
+}
+
+type spanKey struct{}
+
+// Tracer keeping every span with the name of its parent
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	s := &recordedSpan{name: name, attrs: map[string]slog.Value{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		s.parent = parent.name
+	}
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), recordingSpan{r, s}
+}
+
+func (r *recordingTracer) named(name string) []*recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*recordedSpan
+	for _, s := range r.spans {
+		if s.name == name {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+type recordingSpan struct {
+	r *recordingTracer
+	s *recordedSpan
+}
+
+func (s recordingSpan) SetAttributes(attrs ...slog.Attr) {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	for _, a := range attrs {
+		s.s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s recordingSpan) End(err error) {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	s.s.err, s.s.ended = err, true
+}
+
+var _ = Describe("Tracing", func() {
+	var tracer *recordingTracer
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	obs := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	BeforeEach(func() {
+		tracer = &recordingTracer{}
+		SetTracer(tracer)
+	})
+
+	AfterEach(func() {
+		SetTracer(nil)
+	})
+
+	It("should start pass searches as children of the span in the context", func() {
+		sats := jobTestSatellites()
+		catalog := NewCatalog(sats...)
+		_, err := catalog.Passes(context.Background(), obs, start, start.Add(6*time.Hour), PassOptions{})
+		Expect(err).To(BeNil())
+
+		Expect(tracer.named("satellite.Catalog.Passes")).To(HaveLen(1))
+		searches := tracer.named("satellite.PredictPasses")
+		Expect(searches).NotTo(BeEmpty())
+		for _, s := range searches {
+			Expect(s.parent).To(Equal("satellite.Catalog.Passes"))
+			Expect(s.ended).To(BeTrue())
+			Expect(s.attrs).To(HaveKey("satellite.satnum"))
+			Expect(s.attrs["window.start"].String()).To(Equal("2008-09-20T00:00:00Z"))
+			Expect(s.attrs["window.stop"].String()).To(Equal("2008-09-20T06:00:00Z"))
+		}
+	})
+
+	It("should link propagation and snapshots to the caller's span", func() {
+		ctx, parent := tracer.Start(context.Background(), "caller")
+		sat := jobTestSatellites()[0]
+		_, err := sat.PropagateRangeContext(ctx, start, start.Add(time.Hour), time.Minute)
+		Expect(err).To(BeNil())
+		_, err = NextPassesContext(ctx, &sat, obs, start, 1, PassOptions{})
+		Expect(err).To(BeNil())
+		NewCatalog(sat).SnapshotContext(ctx, start)
+		parent.End(nil)
+
+		for _, name := range []string{"satellite.PropagateRange", "satellite.NextPasses", "satellite.Catalog.Snapshot"} {
+			spans := tracer.named(name)
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].parent).To(Equal("caller"))
+		}
+		Expect(tracer.named("satellite.PropagateRange")[0].attrs["satellite.satnum"].Int64()).To(Equal(int64(25544)))
+	})
+
+	It("should record the error of a canceled search", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sat := jobTestSatellites()[0]
+		_, err := PredictPassesContext(ctx, &sat, obs, start, start.Add(24*time.Hour), PassOptions{})
+		Expect(err).To(MatchError(context.Canceled))
+		spans := tracer.named("satellite.PredictPasses")
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].err).To(MatchError(context.Canceled))
+	})
+})
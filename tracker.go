@@ -0,0 +1,127 @@
+package satellite
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// State of the tracked satellite delivered to Tracker callbacks
+type TrackUpdate struct {
+	Time   time.Time
+	Satnum int64
+
+	// Geodetic sub-satellite point in radians and altitude in km
+	Position LatLongAlt
+
+	Angles LookAngles
+
+	// Range rate in km/s, positive when receding, and the Doppler corrected frequencies
+	// of the alive transmitters in the tracker table
+	RangeRate float64
+	Doppler   []TransmitterDoppler
+}
+
+// Real-time loop following one satellite from an observer. The element set can be
+// replaced with SetSatellite while the loop runs, the next update uses the new one.
+type Tracker struct {
+	Observer LatLongAlt
+
+	// Interval between updates, one second when zero
+	Rate time.Duration
+
+	// Optional Earth orientation parameters, rotating the Earth by GMST at UT1 instead of
+	// UTC. UT1 is taken as UTC when nil and at times outside the table.
+	EOP *EOPTable
+
+	// Transmitters whose Doppler corrected frequencies are reported, none when nil
+	Transmitters TransmitterTable
+
+	// Called with every update and with propagation errors, which do not stop the loop
+	OnUpdate func(TrackUpdate)
+	OnError  func(error)
+
+	// Source of the current time, time.Now when nil
+	Now func() time.Time
+
+	mu  sync.Mutex
+	sat Satellite
+}
+
+// Returns a tracker following a copy of sat from the observer
+func NewTracker(sat *Satellite, obs LatLongAlt) *Tracker {
+	return &Tracker{Observer: obs, sat: *sat}
+}
+
+// Replaces the tracked element set, for example with a fresher TLE
+func (tr *Tracker) SetSatellite(sat *Satellite) {
+	tr.mu.Lock()
+	tr.sat = *sat
+	tr.mu.Unlock()
+}
+
+// Computes the update at t with the current element set
+func (tr *Tracker) Update(t time.Time) (TrackUpdate, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	pos, vel, err := tr.sat.propagateAt(t)
+	if err != nil {
+		return TrackUpdate{}, err
+	}
+	gmst := gmstAt(t)
+	if tr.EOP != nil {
+		if i, err := tr.EOP.Instant(t); err == nil {
+			gmst = i.GMST()
+		}
+	}
+	ecf, ecfVel := ECIToECEFState(pos, vel, gmst)
+	obsECEF := LLAToECEF(tr.Observer, tr.sat.Gravity)
+	alt, _, ll := ECIToLLA(pos, gmst)
+	ll.Longitude = wrapPi(ll.Longitude)
+
+	u := TrackUpdate{
+		Time:      t,
+		Satnum:    tr.sat.Satnum,
+		Position:  LatLongAlt{LatLong: ll, AltitudeKm: alt},
+		Angles:    ecefLookAngles(ecf, obsECEF, tr.Observer),
+		RangeRate: topocentricRangeRate(ecf, ecfVel, obsECEF),
+	}
+	if tr.Transmitters != nil {
+		u.Doppler = tr.Transmitters.doppler(u.Satnum, t, u.RangeRate)
+	}
+	return u, nil
+}
+
+// Runs the loop until the context is done, producing an update right away and then
+// at every tick. Returns the context error.
+func (tr *Tracker) Run(ctx context.Context) error {
+	rate := tr.Rate
+	if rate <= 0 {
+		rate = time.Second
+	}
+	now := tr.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	ticker := time.NewTicker(rate)
+	defer ticker.Stop()
+	for {
+		u, err := tr.Update(now())
+		switch {
+		case err != nil:
+			if tr.OnError != nil {
+				tr.OnError(err)
+			}
+		case tr.OnUpdate != nil:
+			tr.OnUpdate(u)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package satellite
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracker", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should report position, look angles and Doppler", func() {
+		sats := jobTestSatellites()
+		tr := NewTracker(&sats[0], copenhagen)
+		tr.Transmitters = TransmitterTable{}
+		tr.Transmitters.Add(Transmitter{Satnum: 25544, Alive: true, DownlinkLow: 145.8e6})
+
+		u, err := tr.Update(start)
+		Expect(err).To(BeNil())
+		lla, _, err := sats[0].PropagateLLA(start)
+		Expect(err).To(BeNil())
+		Expect(u.Position.LatLong.Latitude).To(BeNumerically("~", lla.LatLong.Latitude, 1e-12))
+		Expect(u.Position.AltitudeKm).To(BeNumerically("~", lla.AltitudeKm, 1e-9))
+
+		pos, vel, _ := sats[0].PropagateECEF(start)
+		obsECEF := LLAToECEF(copenhagen, sats[0].Gravity)
+		Expect(u.Angles).To(Equal(ecefLookAngles(pos, obsECEF, copenhagen)))
+		Expect(u.RangeRate).To(BeNumerically("~", topocentricRangeRate(pos, vel, obsECEF), 1e-12))
+		Expect(u.Doppler).To(HaveLen(1))
+		Expect(u.Doppler[0].Downlink).To(Equal(DopplerShift(145.8e6, u.RangeRate)))
+	})
+
+	It("should run at the rate and pick up a new element set", func() {
+		sats := jobTestSatellites()
+		tr := NewTracker(&sats[0], copenhagen)
+		tr.Rate = time.Millisecond
+		ticks := 0
+		tr.Now = func() time.Time {
+			ticks++
+			return start.Add(time.Duration(ticks) * time.Second)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var updates []TrackUpdate
+		tr.OnUpdate = func(u TrackUpdate) {
+			updates = append(updates, u)
+			if len(updates) == 3 {
+				tr.SetSatellite(&sats[1])
+			}
+			if len(updates) == 6 {
+				cancel()
+			}
+		}
+		Expect(tr.Run(ctx)).To(MatchError(context.Canceled))
+		Expect(updates).To(HaveLen(6))
+		for i, u := range updates {
+			Expect(u.Time).To(Equal(start.Add(time.Duration(i+1) * time.Second)))
+			if i < 3 {
+				Expect(u.Satnum).To(Equal(int64(25544)))
+			} else {
+				Expect(u.Satnum).To(Equal(int64(25545)))
+			}
+		}
+	})
+
+	It("should report propagation errors without stopping", func() {
+		sats := jobTestSatellites()
+		tr := NewTracker(&sats[0], copenhagen)
+		tr.Rate = time.Millisecond
+		tr.Now = func() time.Time { return start.AddDate(60, 0, 0) }
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		errs := 0
+		tr.OnError = func(error) {
+			if errs++; errs == 2 {
+				cancel()
+			}
+		}
+		tr.OnUpdate = func(TrackUpdate) { Fail("unexpected update") }
+		Expect(tr.Run(ctx)).To(MatchError(context.Canceled))
+		Expect(errs).To(Equal(2))
+	})
+
+	It("should rotate the Earth by GMST at UT1 from the EOP table", func() {
+		sats := jobTestSatellites()
+		plain, err := NewTracker(&sats[0], copenhagen).Update(start)
+		Expect(err).To(BeNil())
+
+		tr := NewTracker(&sats[0], copenhagen)
+		tr.EOP = &EOPTable{Records: []EOPRecord{
+			{MJD: 54728, EOP: EOP{DUT1: -0.5}},
+			{MJD: 54730, EOP: EOP{DUT1: -0.5}},
+		}}
+		u, err := tr.Update(start)
+		Expect(err).To(BeNil())
+		shift := math.Remainder(u.Position.LatLong.Longitude-plain.Position.LatLong.Longitude, TWOPI)
+		Expect(shift).To(BeNumerically("~", 0.5*1.00273790935*TWOPI/86400, 1e-9))
+		Expect(u.Angles.Az).NotTo(Equal(plain.Angles.Az))
+
+		outside, err := tr.Update(start.AddDate(0, 0, 3))
+		Expect(err).To(BeNil())
+		want, err := NewTracker(&sats[0], copenhagen).Update(start.AddDate(0, 0, 3))
+		Expect(err).To(BeNil())
+		Expect(outside).To(Equal(want))
+	})
+})
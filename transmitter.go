@@ -0,0 +1,142 @@
+package satellite
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Speed of light in km/s
+const speedOfLight = 299792.458
+
+// Radio transmitter carried by a satellite. Frequencies are in Hz, zero when the
+// transmitter has no such link.
+type Transmitter struct {
+	UUID        string
+	Description string
+	Satnum      int64
+	Alive       bool
+
+	// Modulation or protocol such as "FM", "CW" or "GMSK"
+	Mode string
+
+	UplinkLow, UplinkHigh     float64
+	DownlinkLow, DownlinkHigh float64
+
+	// Symbol rate in baud, zero when unknown
+	Baud float64
+
+	// Transponder inverts the passband
+	Invert bool
+}
+
+// Transmitters keyed by NORAD catalog number
+type TransmitterTable map[int64][]Transmitter
+
+// Adds transmitters to the table under their catalog numbers
+func (tt TransmitterTable) Add(transmitters ...Transmitter) {
+	for _, tx := range transmitters {
+		tt[tx.Satnum] = append(tt[tx.Satnum], tx)
+	}
+}
+
+// Transmitter entry of the SatNOGS DB API
+type satnogsTransmitter struct {
+	UUID         string   `json:"uuid"`
+	Description  string   `json:"description"`
+	Alive        bool     `json:"alive"`
+	Status       string   `json:"status"`
+	Mode         string   `json:"mode"`
+	UplinkLow    *float64 `json:"uplink_low"`
+	UplinkHigh   *float64 `json:"uplink_high"`
+	DownlinkLow  *float64 `json:"downlink_low"`
+	DownlinkHigh *float64 `json:"downlink_high"`
+	Baud         *float64 `json:"baud"`
+	Invert       bool     `json:"invert"`
+	NoradCatID   int64    `json:"norad_cat_id"`
+}
+
+// Reads a transmitter list in the JSON format of the SatNOGS DB transmitters API
+func ReadSatNOGSTransmitters(r io.Reader) (TransmitterTable, error) {
+	var entries []satnogsTransmitter
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	value := func(v *float64) float64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	tt := make(TransmitterTable)
+	for _, e := range entries {
+		tt.Add(Transmitter{
+			UUID:         e.UUID,
+			Description:  e.Description,
+			Satnum:       e.NoradCatID,
+			Alive:        e.Alive && e.Status != "invalid",
+			Mode:         e.Mode,
+			UplinkLow:    value(e.UplinkLow),
+			UplinkHigh:   value(e.UplinkHigh),
+			DownlinkLow:  value(e.DownlinkLow),
+			DownlinkHigh: value(e.DownlinkHigh),
+			Baud:         value(e.Baud),
+			Invert:       e.Invert,
+		})
+	}
+	return tt, nil
+}
+
+// Returns the frequency received from a source at freq moving away at rangeRate km/s
+func DopplerShift(freq, rangeRate float64) float64 {
+	return freq * (1 - rangeRate/speedOfLight)
+}
+
+// Doppler corrected frequencies of a transmitter at one moment. Downlink is the
+// frequency to listen on at the ground; Uplink is the frequency to transmit on from
+// the ground so that the satellite receives the nominal frequency.
+type TransmitterDoppler struct {
+	Transmitter Transmitter
+	Time        time.Time
+
+	// Range rate of the satellite seen from the observer in km/s, positive when receding
+	RangeRate float64
+
+	Downlink, Uplink float64
+}
+
+// Returns the Doppler corrected frequencies of every alive transmitter at one range rate
+func (tt TransmitterTable) doppler(satnum int64, t time.Time, rangeRate float64) []TransmitterDoppler {
+	var out []TransmitterDoppler
+	for _, tx := range tt[satnum] {
+		if !tx.Alive {
+			continue
+		}
+		d := TransmitterDoppler{Transmitter: tx, Time: t, RangeRate: rangeRate}
+		if tx.DownlinkLow > 0 {
+			d.Downlink = DopplerShift(tx.DownlinkLow, rangeRate)
+		}
+		if tx.UplinkLow > 0 {
+			d.Uplink = tx.UplinkLow / (1 - rangeRate/speedOfLight)
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// Returns the Doppler corrected frequencies of the alive transmitters of the satellite
+// seen from the observer at t
+func (tt TransmitterTable) DopplerAt(sat *Satellite, obs LatLongAlt, t time.Time) ([]TransmitterDoppler, error) {
+	pos, vel, err := sat.PropagateECEF(t)
+	if err != nil {
+		return nil, err
+	}
+	return tt.doppler(sat.Satnum, t, topocentricRangeRate(pos, vel, LLAToECEF(obs, sat.Gravity))), nil
+}
+
+// Returns the rate of change of the distance between an Earth fixed observer and the
+// satellite in km/s
+func topocentricRangeRate(satECEF, velECEF, obsECEF Vector3) float64 {
+	rho := Vector3{satECEF.X - obsECEF.X, satECEF.Y - obsECEF.Y, satECEF.Z - obsECEF.Z}
+	return dot(rho, velECEF) / rho.Magnitude()
+}
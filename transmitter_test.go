@@ -0,0 +1,67 @@
+package satellite
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const satnogsSample = `[
+{"uuid":"a","description":"Mode V/V FM Voice","alive":true,"uplink_low":145990000,"uplink_high":null,"downlink_low":145800000,"downlink_high":null,"mode":"FM","invert":false,"baud":null,"norad_cat_id":25544,"status":"active"},
+{"uuid":"b","description":"Retired beacon","alive":false,"uplink_low":null,"uplink_high":null,"downlink_low":437550000,"downlink_high":null,"mode":"CW","invert":false,"baud":null,"norad_cat_id":25544,"status":"inactive"},
+{"uuid":"c","description":"APT","alive":true,"uplink_low":null,"uplink_high":null,"downlink_low":137100000,"downlink_high":null,"mode":"APT","invert":false,"baud":null,"norad_cat_id":33591,"status":"active"}
+]`
+
+var _ = Describe("Transmitters", func() {
+	start := time.Date(2008, 9, 20, 0, 0, 0, 0, time.UTC)
+	copenhagen := NewLatLongAlt(55.6167, 12.6500, 0.005)
+
+	It("should read the SatNOGS DB format", func() {
+		tt, err := ReadSatNOGSTransmitters(strings.NewReader(satnogsSample))
+		Expect(err).To(BeNil())
+		Expect(tt[25544]).To(HaveLen(2))
+		Expect(tt[33591]).To(HaveLen(1))
+
+		tx := tt[25544][0]
+		Expect(tx.Mode).To(Equal("FM"))
+		Expect(tx.UplinkLow).To(Equal(145990000.0))
+		Expect(tx.DownlinkLow).To(Equal(145800000.0))
+		Expect(tx.DownlinkHigh).To(Equal(0.0))
+		Expect(tt[25544][1].Alive).To(BeFalse())
+	})
+
+	It("should shift frequencies by the range rate", func() {
+		Expect(DopplerShift(145.8e6, -7)).To(BeNumerically("~", 145.8e6*(1+7/speedOfLight), 1e-6))
+		Expect(DopplerShift(145.8e6, 0)).To(Equal(145.8e6))
+	})
+
+	It("should include Doppler corrected frequencies in access windows", func() {
+		tt, err := ReadSatNOGSTransmitters(strings.NewReader(satnogsSample))
+		Expect(err).To(BeNil())
+		sat := jobTestSatellites()[0]
+
+		windows, err := NextAccesses(&sat, copenhagen, start, 1, AccessOptions{MinElevation: Degrees(10).Radians(), Transmitters: tt})
+		Expect(err).To(BeNil())
+		w := windows[0]
+
+		// Only the alive transmitter at start, culmination and stop
+		Expect(w.Doppler).To(HaveLen(3))
+		aos, tca, los := w.Doppler[0], w.Doppler[1], w.Doppler[2]
+		Expect(aos.Time).To(Equal(w.Start))
+		Expect(los.Time).To(Equal(w.Stop))
+
+		// Approaching at rise, receding at set, nearly no shift at culmination
+		Expect(aos.RangeRate).To(BeNumerically("<", -3))
+		Expect(los.RangeRate).To(BeNumerically(">", 3))
+		Expect(tca.RangeRate).To(BeNumerically("~", 0, 0.1))
+		Expect(aos.Downlink).To(BeNumerically(">", 145.8e6+1500))
+		Expect(los.Downlink).To(BeNumerically("<", 145.8e6-1500))
+		Expect(aos.Uplink).To(BeNumerically("<", 145.99e6))
+
+		direct, err := tt.DopplerAt(&sat, copenhagen, w.Start)
+		Expect(err).To(BeNil())
+		Expect(direct[0].Downlink).To(BeNumerically("~", aos.Downlink, 1e-3))
+	})
+})
@@ -0,0 +1,73 @@
+package satellite
+
+import "math"
+
+// Angle in radians
+type Radians float64
+
+// Angle in degrees
+type Degrees float64
+
+// Distance in kilometers
+type Kilometers float64
+
+// Speed in kilometers per second
+type KmPerSec float64
+
+// Convert radians to degrees
+func (r Radians) Degrees() Degrees {
+	return Degrees(float64(r) * RAD2DEG)
+}
+
+// Returns the angle wrapped into the range [0, 2pi)
+func (r Radians) Normalize() Radians {
+	n := math.Mod(float64(r), TWOPI)
+	if n < 0 {
+		n += TWOPI
+	}
+	return Radians(n)
+}
+
+// Convert degrees to radians
+func (d Degrees) Radians() Radians {
+	return Radians(float64(d) * DEG2RAD)
+}
+
+// Returns the angle wrapped into the range [0, 360)
+func (d Degrees) Normalize() Degrees {
+	n := math.Mod(float64(d), 360)
+	if n < 0 {
+		n += 360
+	}
+	return Degrees(n)
+}
+
+// Convert kilometers to meters
+func (k Kilometers) Meters() float64 {
+	return float64(k) * 1000
+}
+
+// Convert kilometers per second to meters per second
+func (v KmPerSec) MetersPerSec() float64 {
+	return float64(v) * 1000
+}
+
+// Build a LatLong in radians from typed radian values
+func NewLatLong(latitude, longitude Radians) LatLong {
+	return LatLong{Latitude: float64(latitude), Longitude: float64(longitude)}
+}
+
+// Build a LatLong in radians from typed degree values
+func NewLatLongFromDeg(latitude, longitude Degrees) LatLong {
+	return NewLatLong(latitude.Radians(), longitude.Radians())
+}
+
+// Returns latitude and longitude of a radian LatLong as typed degrees
+func (ll LatLong) Deg() (latitude, longitude Degrees) {
+	return Radians(ll.Latitude).Degrees(), Radians(ll.Longitude).Degrees()
+}
+
+// Returns the length of the vector
+func (v Vector3) Magnitude() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
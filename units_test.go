@@ -0,0 +1,34 @@
+package satellite
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Units", func() {
+	It("should convert between degrees and radians", func() {
+		Expect(float64(Degrees(180).Radians())).To(BeNumerically("~", math.Pi, 1e-12))
+		Expect(float64(Radians(math.Pi / 2).Degrees())).To(BeNumerically("~", 90, 1e-12))
+	})
+
+	It("should normalize angles", func() {
+		Expect(float64(Degrees(-90).Normalize())).To(BeNumerically("~", 270, 1e-12))
+		Expect(float64(Radians(3 * math.Pi).Normalize())).To(BeNumerically("~", math.Pi, 1e-12))
+	})
+
+	It("should build radian LatLong from degrees", func() {
+		ll := NewLatLongFromDeg(55.6167, 12.65)
+		Expect(ll).To(Equal(NewLatLongAlt(55.6167, 12.65, 0).LatLong))
+
+		lat, lon := ll.Deg()
+		Expect(float64(lat)).To(BeNumerically("~", 55.6167, 1e-12))
+		Expect(float64(lon)).To(BeNumerically("~", 12.65, 1e-12))
+	})
+
+	It("should convert distances and speeds", func() {
+		Expect(Kilometers(1.5).Meters()).To(Equal(1500.0))
+		Expect(KmPerSec(7.5).MetersPerSec()).To(Equal(7500.0))
+	})
+})
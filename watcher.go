@@ -0,0 +1,192 @@
+package satellite
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Keeps a catalog in step with an element set file or a directory of them, for services
+// that run across the daily TLE refreshes. Files hold two or three line element sets or
+// OMM element sets in KVN, XML or JSON. Changes are found by polling the size and
+// modification time of the files; a changed set of files is read completely and swapped
+// into the catalog at once, so readers of the catalog never see a half loaded file.
+// Files that fail to read keep the previous catalog contents and are retried at the next
+// poll. Malformed element sets are left out and reported while the good ones are swapped in.
+type CatalogWatcher struct {
+	// File or directory watched. Files in a directory whose names start with a dot are
+	// skipped.
+	Path string
+
+	Catalog *Catalog
+
+	// Gravity model of the loaded satellites, wgs72 when empty
+	Gravity GravModel
+
+	// Interval between polls, ten seconds when zero
+	Interval time.Duration
+
+	// Called with the number of satellites after every reload and with failed reloads,
+	// which do not stop the watcher. A reload leaving out malformed element sets calls both.
+	OnReload func(n int)
+	OnError  func(error)
+
+	stamp string
+}
+
+// Returns a watcher loading the file or directory at path into catalog
+func NewCatalogWatcher(path string, catalog *Catalog) *CatalogWatcher {
+	return &CatalogWatcher{Path: path, Catalog: catalog}
+}
+
+// Reloads the catalog when the watched files changed since the last reload. Reports whether
+// the catalog was replaced; it is also replaced, without the malformed element sets, when
+// the error only reports such element sets.
+func (w *CatalogWatcher) Reload() (bool, error) {
+	log := Logger().With("path", w.Path)
+	files, stamp, err := watchedFiles(w.Path)
+	if err != nil {
+		log.Warn("catalog reload failed", "err", err)
+		return false, err
+	}
+	if stamp == w.stamp {
+		return false, nil
+	}
+	gravconst := w.Gravity
+	if gravconst == "" {
+		gravconst = WGS72
+	}
+	var sats []Satellite
+	var bad []error
+	for _, name := range files {
+		s, badEntries, err := readCatalogFile(name, gravconst)
+		if err != nil {
+			log.Warn("catalog reload failed", "file", name, "err", err)
+			return false, fmt.Errorf("%s: %w", name, err)
+		}
+		if badEntries != nil {
+			log.Warn("catalog file has malformed element sets", "file", name, "err", badEntries)
+			bad = append(bad, fmt.Errorf("%s: %w", name, badEntries))
+		}
+		sats = append(sats, s...)
+	}
+	w.Catalog.Replace(sats...)
+	w.stamp = stamp
+	log.Info("catalog reloaded", "files", len(files), "satellites", len(sats))
+	return true, errors.Join(bad...)
+}
+
+// Runs the watcher until the context is done, loading the catalog right away and then
+// polling at every tick. Returns the context error.
+func (w *CatalogWatcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reloaded, err := w.Reload()
+		if err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		if reloaded && w.OnReload != nil {
+			w.OnReload(w.Catalog.Len())
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the files at path in name order and a stamp of their names, sizes and
+// modification times
+func watchedFiles(path string) ([]string, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+	var infos []os.FileInfo
+	var files []string
+	if !info.IsDir() {
+		infos, files = []os.FileInfo{info}, []string{path}
+	} else {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") || !e.Type().IsRegular() {
+				continue
+			}
+			fi, err := e.Info()
+			if err != nil {
+				return nil, "", err
+			}
+			infos = append(infos, fi)
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+
+	var stamp strings.Builder
+	for _, fi := range infos {
+		fmt.Fprintf(&stamp, "%s\x00%d\x00%d\n", fi.Name(), fi.Size(), fi.ModTime().UnixNano())
+	}
+	return files, stamp.String(), nil
+}
+
+// Reads the satellites of a TLE or OMM file. OMM element sets of ephemeris type 4, which
+// need SGP4-XP, are left out. Malformed element sets are left out and reported in bad, err
+// reports a file that could not be read.
+func readCatalogFile(name string, gravconst GravModel) (sats []Satellite, bad error, err error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	br := bufio.NewReader(bytes.NewReader(data))
+	first, err := firstByte(br)
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	rest, _ := br.Peek(len("CCSDS_OMM_VERS"))
+	if first != '<' && first != '{' && first != '[' && string(rest) != "CCSDS_OMM_VERS" {
+		sats, bad = ParseTLEs(br, gravconst)
+		return sats, bad, nil
+	}
+
+	gps, err := ReadOMM(br)
+	if err != nil {
+		return nil, nil, err
+	}
+	sats = make([]Satellite, 0, len(gps))
+	var errs []error
+	for _, gp := range gps {
+		sat, err := NewSatFromGP(gp, gravconst)
+		if errors.Is(err, ErrUnsupportedEphemeris) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("element set of %d: %w", gp.NoradCatID, err))
+			continue
+		}
+		sats = append(sats, sat)
+	}
+	return sats, errors.Join(errs...), nil
+}
@@ -0,0 +1,80 @@
+package satellite
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CatalogWatcher", func() {
+	It("should swap in changed files and keep the catalog on failures", func() {
+		dir, err := os.MkdirTemp("", "watcher")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+		Expect(os.WriteFile(filepath.Join(dir, "stations.tle"), []byte(tleFile), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, ".partial"), []byte("garbage"), 0o644)).To(Succeed())
+
+		catalog := &Catalog{}
+		w := NewCatalogWatcher(dir, catalog)
+		reloaded, err := w.Reload()
+		Expect(err).To(BeNil())
+		Expect(reloaded).To(BeTrue())
+		Expect(catalog.Len()).To(Equal(2))
+		reloaded, err = w.Reload()
+		Expect(err).To(BeNil())
+		Expect(reloaded).To(BeFalse())
+
+		Expect(os.WriteFile(filepath.Join(dir, "iss.kvn"), []byte(ommKVN), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "stations.tle"), []byte(tleFile[:len(tleFile)-71]), 0o644)).To(Succeed())
+		reloaded, err = w.Reload()
+		var entry *TLEEntryError
+		Expect(errors.As(err, &entry)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("stations.tle")))
+		Expect(reloaded).To(BeTrue())
+		Expect(catalog.Len()).To(Equal(1))
+		_, ok := catalog.Get(25544)
+		Expect(ok).To(BeTrue())
+
+		Expect(os.WriteFile(filepath.Join(dir, "broken.kvn"), []byte("CCSDS_OMM_VERS = 2.0\nOBJECT_NAME"), 0o644)).To(Succeed())
+		reloaded, err = w.Reload()
+		Expect(err).To(HaveOccurred())
+		Expect(reloaded).To(BeFalse())
+		Expect(catalog.Len()).To(Equal(1))
+		Expect(os.Remove(filepath.Join(dir, "broken.kvn"))).To(Succeed())
+
+		Expect(os.Remove(filepath.Join(dir, "stations.tle"))).To(Succeed())
+		reloaded, err = w.Reload()
+		Expect(err).To(BeNil())
+		Expect(reloaded).To(BeTrue())
+		Expect(catalog.Len()).To(Equal(1))
+		_, ok = catalog.Get(33591)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should report reloads while running", func() {
+		dir, err := os.MkdirTemp("", "watcher")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "iss.json")
+		Expect(os.WriteFile(path, []byte(ommJSON), 0o644)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		reloads := make(chan int, 1)
+		var failures []error
+		w := NewCatalogWatcher(path, &Catalog{})
+		w.Interval = time.Millisecond
+		w.OnReload = func(n int) { reloads <- n }
+		w.OnError = func(err error) { failures = append(failures, err) }
+		done := make(chan error)
+		go func() { done <- w.Run(ctx) }()
+		Eventually(reloads).Should(Receive(Equal(2)))
+		cancel()
+		Expect(<-done).To(Equal(context.Canceled))
+		Expect(failures).To(BeEmpty())
+	})
+})